@@ -0,0 +1,39 @@
+package crossplanepause
+
+import "time"
+
+// Action the action a reconcile takes on a resource.
+type Action string
+
+// The Actions.
+const (
+	// ActionNone nothing to do, e.g. the resource is gone.
+	ActionNone Action = "None"
+	// ActionIgnore the resource is left alone, e.g. it's paused by others.
+	ActionIgnore Action = "Ignore"
+	// ActionPause the resource is paused.
+	ActionPause Action = "Pause"
+	// ActionUnpause the resource is unpaused.
+	ActionUnpause Action = "Unpause"
+	// ActionRestorePause the stripped pause annotation of the paused resource is added back.
+	ActionRestorePause Action = "RestorePause"
+	// ActionExtendPause the paused resource is kept paused for another UnPausePollInterval by SoftUnpause.
+	ActionExtendPause Action = "ExtendPause"
+	// ActionKeepPaused the paused resource is kept paused.
+	ActionKeepPaused Action = "KeepPaused"
+	// ActionKeepUnpaused the resource is kept unpaused, e.g. it's not Ready and Synced yet.
+	ActionKeepUnpaused Action = "KeepUnpaused"
+	// ActionWouldPause the resource would be paused but it's left unpaused in ModeObserve.
+	ActionWouldPause Action = "WouldPause"
+)
+
+// decision the action a reconcile takes and why, see decide.
+type decision struct {
+	action Action
+	reason string
+	// after the Duration to requeue the resource after, 0 if it waits for the next event.
+	after time.Duration
+	// record if sets, the observation recorded into the pause info before taking the action, e.g. the fingerprint
+	// for the QuiescencePeriod.
+	record observation
+}
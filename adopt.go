@@ -0,0 +1,38 @@
+package crossplanepause
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// PauseReasonAdopted the reason of pausing a resource paused by other guy already, see Reconciler.AdoptManuallyPaused.
+const PauseReasonAdopted = "adopted"
+
+// adoptable returns true if obj, paused by other guy without our pause info, is to adopt by the AdoptManuallyPaused, or
+// the delay to check it again if it's held by the gates of a fresh pause, like the rollout and the settling.
+// Only the ready ones are adopted, since the ones paused in the middle of a change may be never ready once unpaused,
+// and never the deleting ones or the ones the SpecMatch rejects.
+func (r *Reconciler) adoptable(ctx context.Context, obj *unstructured.Unstructured, now time.Time) (bool, time.Duration, error) {
+	if !r.AdoptManuallyPaused || !obj.GetDeletionTimestamp().IsZero() || !r.specMatched(obj) {
+		return false, 0, nil
+	}
+
+	ready, err := r.isReadyAndSynced(ctx, obj)
+	if err != nil || !ready {
+		return false, 0, err
+	}
+
+	reason, after, err := r.pauseGate(ctx, obj, now)
+	if err != nil {
+		return false, 0, err
+	}
+	if reason != "" {
+		log.FromContext(ctx).V(1).Info("not adopt yet", "reason", reason, "after", after.String())
+		return false, after, nil
+	}
+
+	return true, 0, nil
+}
@@ -0,0 +1,87 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+)
+
+func TestAdoptManuallyPaused(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		adopt      bool
+		ready      bool
+		deleting   bool
+		mismatched bool
+		outRollout bool
+		adopted    bool
+	}{
+		{name: "ignored by default", ready: true},
+		{name: "adopted", adopt: true, ready: true, adopted: true},
+		{name: "not ready", adopt: true},
+		{name: "deleting", adopt: true, ready: true, deleting: true},
+		{name: "spec not matched", adopt: true, ready: true, mismatched: true},
+		{name: "out of the rollout", adopt: true, ready: true, outRollout: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHarness(t)
+			h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+			h.r.UnPausePollJitter = pointer.Float64(0)
+			h.r.AdoptManuallyPaused = tc.adopt
+			thing := newThing(t, "thing")
+			thing.SetAnnotations(map[string]string{AnnotationKeyReconciliationPaused: "true"})
+			if !tc.ready {
+				setConditions(t, thing, xpv1.Creating(), xpv1.ReconcileSuccess())
+			}
+			if tc.mismatched {
+				h.r.SpecMatch = func(spec map[string]interface{}) bool { return false }
+			}
+			if tc.outRollout {
+				h.r.RolloutPercentage = pointer.Int(0)
+			}
+			if tc.deleting {
+				thing.SetFinalizers([]string{"test"})
+			}
+			err := h.cli.Create(context.Background(), thing)
+			require.Nil(t, err)
+			if tc.deleting {
+				err = h.cli.Delete(context.Background(), getThing(t, h.cli, "thing"))
+				require.Nil(t, err)
+			}
+
+			version := getThing(t, h.cli, "thing").GetResourceVersion()
+			decisions, err := h.r.PreviewDecisions(context.Background())
+			require.Nil(t, err)
+			require.Len(t, decisions, 1)
+			report, err := h.r.PreviewPauseCandidates(context.Background())
+			require.Nil(t, err)
+			h.reconcile()
+			paused, info := h.state()
+			require.True(t, paused)
+			if !tc.adopted {
+				require.Equal(t, ActionIgnore, decisions[0].Action)
+				require.Equal(t, 1, report.Ignored)
+				require.Nil(t, info)
+				require.Equal(t, version, getThing(t, h.cli, "thing").GetResourceVersion())
+				return
+			}
+
+			require.Equal(t, PreviewDecision{Key: decisions[0].Key, Action: ActionPause, Reason: PauseReasonAdopted}, decisions[0])
+			require.Equal(t, 1, report.Candidates)
+			require.True(t, info.Pause)
+			require.Equal(t, h.r.controllerName(), getThing(t, h.cli, "thing").GetAnnotations()[AnnotationKeyPausedBy])
+			require.True(t, info.ShouldUnpauseTime.Time.Equal(h.clock.Now().Add(time.Hour)))
+
+			// unpaused by the UnPausePollInterval like the ones we paused.
+			h.advance(time.Hour)
+			h.reconcile()
+			paused, info = h.state()
+			require.False(t, paused)
+			require.Equal(t, UnpauseReasonPollInterval, info.History[len(info.History)-1].Reason)
+		})
+	}
+}
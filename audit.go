@@ -0,0 +1,113 @@
+package crossplanepause
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AuditEntry records a pause or unpause of a resource.
+type AuditEntry struct {
+	Time             metav1.Time `json:"time"`
+	GroupVersionKind string      `json:"groupVersionKind"`
+	Namespace        string      `json:"namespace,omitempty"`
+	Name             string      `json:"name"`
+	UID              types.UID   `json:"uid,omitempty"`
+	// Action is ActionPause or ActionUnpause.
+	Action          Action       `json:"action"`
+	Reason          string       `json:"reason"`
+	LastPauseTime   *metav1.Time `json:"lastPauseTime,omitempty"`
+	LastUnPauseTime *metav1.Time `json:"lastUnPauseTime,omitempty"`
+	// Note the note of the operators in the AnnotationKeyPauseNote annotation.
+	Note string `json:"note,omitempty"`
+}
+
+// AuditSink records the AuditEntries to an external audit system.
+type AuditSink interface {
+	Record(ctx context.Context, entry AuditEntry) error
+}
+
+// HTTPAuditSink posts every AuditEntry as JSON to URL.
+type HTTPAuditSink struct {
+	URL string
+	// Client if not set, http.DefaultClient will be used.
+	Client *http.Client
+	// Header the extra headers of the requests, e.g. the authorization.
+	Header http.Header
+}
+
+var _ AuditSink = &HTTPAuditSink{}
+
+// Record posts entry to URL, any status other than 2xx is an error.
+func (s *HTTPAuditSink) Record(ctx context.Context, entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("unable to marshal audit entry: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("unable to create audit request: %w", err)
+	}
+	for key, values := range s.Header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	cli := s.Client
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+	resp, err := cli.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to post audit entry: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unable to post audit entry: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// audit records the action taken on obj to the AuditSink if it's set. The error is returned only if AuditFatal is set,
+// otherwise it's logged.
+func (r *Reconciler) audit(ctx context.Context, obj *unstructured.Unstructured, action Action, reason string, info *PauseInfo) error {
+	if r.AuditSink == nil {
+		return nil
+	}
+
+	entry := AuditEntry{
+		Time:             metav1.NewTime(r.now()),
+		GroupVersionKind: obj.GroupVersionKind().String(),
+		Namespace:        obj.GetNamespace(),
+		Name:             obj.GetName(),
+		UID:              obj.GetUID(),
+		Action:           action,
+		Reason:           reason,
+		LastPauseTime:    info.LastPauseTime,
+		LastUnPauseTime:  info.LastUnPauseTime,
+		Note:             info.Note,
+	}
+	err := r.AuditSink.Record(ctx, entry)
+	if err == nil {
+		return nil
+	}
+
+	if r.AuditFatal {
+		return fmt.Errorf("unable to audit %s: %w", action, err)
+	}
+	log.FromContext(ctx).Error(err, "unable to audit, ignore it", "action", action, "reason", reason)
+	return nil
+}
@@ -0,0 +1,112 @@
+package crossplanepause
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeAuditSink records the entries, it fails with err if set.
+type fakeAuditSink struct {
+	entries []AuditEntry
+	err     error
+}
+
+func (s *fakeAuditSink) Record(_ context.Context, entry AuditEntry) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func TestAuditSink(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+	err := cli.Create(ctx, newThing(t, "thing"))
+	require.Nil(t, err)
+
+	sink := new(fakeAuditSink)
+	r := newThingReconciler(cli)
+	r.FrozenTimeDuration = pointer.Duration(0)
+	r.AuditSink = sink
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}}
+
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	// nothing to audit if nothing changes.
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Len(t, sink.entries, 1)
+	entry := sink.entries[0]
+	require.Equal(t, ActionPause, entry.Action)
+	require.Equal(t, "Ready=Available, Synced=ReconcileSuccess", entry.Reason)
+	require.Equal(t, testGVK.String(), entry.GroupVersionKind)
+	require.Equal(t, "thing", entry.Name)
+	require.NotNil(t, entry.LastPauseTime)
+
+	thing := getThing(t, cli, "thing")
+	err = unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Len(t, sink.entries, 2)
+	entry = sink.entries[1]
+	require.Equal(t, ActionUnpause, entry.Action)
+	require.Equal(t, string(UnpauseReasonUpdated), entry.Reason)
+	require.NotNil(t, entry.LastUnPauseTime)
+
+	// best-effort by default.
+	sink.err = errors.New("audit system down")
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.True(t, isPaused(getThing(t, cli, "thing").GetAnnotations()[AnnotationKeyReconciliationPaused]))
+
+	// fatal, the pause is not rolled back.
+	r.AuditFatal = true
+	thing = getThing(t, cli, "thing")
+	err = unstructured.SetNestedField(thing.Object, "b", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.ErrorIs(t, err, sink.err)
+	require.False(t, isPaused(getThing(t, cli, "thing").GetAnnotations()[AnnotationKeyReconciliationPaused]))
+}
+
+func TestHTTPAuditSink(t *testing.T) {
+	var got AuditEntry
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		require.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+		err := json.NewDecoder(req.Body).Decode(&got)
+		require.Nil(t, err)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := &HTTPAuditSink{URL: server.URL, Header: http.Header{"Authorization": []string{"Bearer token"}}}
+	entry := AuditEntry{GroupVersionKind: testGVK.String(), Name: "thing", Action: ActionPause, Reason: "Ready and Synced"}
+	err := sink.Record(context.Background(), entry)
+	require.Nil(t, err)
+	require.Equal(t, entry.Name, got.Name)
+	require.Equal(t, entry.Action, got.Action)
+
+	status = http.StatusInternalServerError
+	err = sink.Record(context.Background(), entry)
+	require.NotNil(t, err)
+}
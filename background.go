@@ -0,0 +1,28 @@
+package crossplanepause
+
+import (
+	"fmt"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// addBackground registers a background component of the reconciler.
+// The component must return from Start once the context is done and must not leave any goroutine behind.
+func (r *Reconciler) addBackground(runnable manager.Runnable) {
+	r.backgrounds = append(r.backgrounds, runnable)
+}
+
+// setupBackgrounds adds all the background components to mgr, so they're tied to the manager's context:
+// they're started once the manager starts and stopped once it's stopped.
+// The manager waits for them to return on shutdown, so nothing leaks after mgr.Start returns.
+func (r *Reconciler) setupBackgrounds(mgr ctrl.Manager) error {
+	for _, runnable := range r.backgrounds {
+		err := mgr.Add(runnable)
+		if err != nil {
+			return fmt.Errorf("unable to add background component: %w", err)
+		}
+	}
+
+	return nil
+}
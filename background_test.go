@@ -0,0 +1,73 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// newTestManager returns a manager which never talks to an API server unless something watches.
+func newTestManager(t *testing.T, opts ...func(*manager.Options)) manager.Manager {
+	t.Helper()
+
+	options := manager.Options{
+		Scheme:             runtime.NewScheme(),
+		MetricsBindAddress: "0",
+		MapperProvider: func(c *rest.Config) (meta.RESTMapper, error) {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(testGVK, meta.RESTScopeRoot)
+			mapper.Add(testNamespacedGVK, meta.RESTScopeNamespace)
+			return mapper, nil
+		},
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	mgr, err := manager.New(&rest.Config{Host: "http://127.0.0.1:1"}, options)
+	require.Nil(t, err)
+	return mgr
+}
+
+func TestBackgroundLifecycle(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	mgr := newTestManager(t)
+	r := newThingReconciler(mgr.GetClient())
+
+	started := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		r.addBackground(manager.RunnableFunc(func(ctx context.Context) error {
+			started <- struct{}{}
+			ticker := time.NewTicker(time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		}))
+	}
+	err := r.setupBackgrounds(mgr)
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- mgr.Start(ctx)
+	}()
+	<-started
+	<-started
+
+	cancel()
+	require.Nil(t, <-done)
+}
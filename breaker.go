@@ -0,0 +1,98 @@
+package crossplanepause
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultUpdateFailureWindow the default window in which the consecutive update failures of a resource are counted.
+const DefaultUpdateFailureWindow = 10 * time.Minute
+
+// DefaultUpdateFailureBackoff the default Duration we back off once the update of a resource keeps failing.
+const DefaultUpdateFailureBackoff = 30 * time.Minute
+
+// EventReasonUpdateFailing the reason of the warning event emitted once the update of a resource keeps failing.
+const EventReasonUpdateFailing = "UpdateFailing"
+
+// updateFailures the consecutive update failures of a resource.
+type updateFailures struct {
+	count int
+	// first the time of the first failure in the window.
+	first time.Time
+}
+
+func (r *Reconciler) updateFailureWindow() time.Duration {
+	if r.UpdateFailureWindow > 0 {
+		return r.UpdateFailureWindow
+	}
+
+	return DefaultUpdateFailureWindow
+}
+
+func (r *Reconciler) updateFailureBackoff() time.Duration {
+	if r.UpdateFailureBackoff > 0 {
+		return r.UpdateFailureBackoff
+	}
+
+	return DefaultUpdateFailureBackoff
+}
+
+// recordUpdateResult counts the consecutive update failures of obj, a success resets it. It emits a warning event
+// once the failures reach the UpdateFailureThreshold in the UpdateFailureWindow.
+func (r *Reconciler) recordUpdateResult(obj *unstructured.Unstructured, err error) {
+	// The composed resources are not the ones we reconcile.
+	if r.UpdateFailureThreshold <= 0 || obj.GroupVersionKind() != r.GroupVersionKind {
+		return
+	}
+
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	r.updateFailuresMu.Lock()
+	defer r.updateFailuresMu.Unlock()
+
+	if err == nil {
+		delete(r.updateFailures, key)
+		return
+	}
+
+	if r.updateFailures == nil {
+		r.updateFailures = make(map[types.NamespacedName]*updateFailures)
+	}
+	now := r.now()
+	f, ok := r.updateFailures[key]
+	if !ok || now.Sub(f.first) > r.updateFailureWindow() {
+		f = &updateFailures{first: now}
+		r.updateFailures[key] = f
+	}
+	f.count++
+
+	if f.count == r.UpdateFailureThreshold {
+		r.event(obj, corev1.EventTypeWarning, EventReasonUpdateFailing,
+			"Failed to update %d times in a row since %s, back off for %s: %s",
+			f.count, f.first.Format(time.RFC3339), r.updateFailureBackoff(), err.Error())
+	}
+}
+
+// isUpdateFailing returns true if the update of the resource of key has failed at least UpdateFailureThreshold times
+// in a row in the UpdateFailureWindow.
+func (r *Reconciler) isUpdateFailing(key types.NamespacedName) bool {
+	if r.UpdateFailureThreshold <= 0 {
+		return false
+	}
+
+	r.updateFailuresMu.Lock()
+	defer r.updateFailuresMu.Unlock()
+
+	f, ok := r.updateFailures[key]
+	return ok && f.count >= r.UpdateFailureThreshold && r.now().Sub(f.first) <= r.updateFailureWindow()
+}
+
+// forgetUpdateFailures forgets the update failures of the resource once it's gone.
+func (r *Reconciler) forgetUpdateFailures(key types.NamespacedName) {
+	r.updateFailuresMu.Lock()
+	defer r.updateFailuresMu.Unlock()
+
+	delete(r.updateFailures, key)
+}
@@ -0,0 +1,59 @@
+package crossplanepause
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestUpdateFailureBackoff(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+	err := h.cli.Create(ctx, newThing(t, "thing"))
+	require.Nil(t, err)
+
+	recorder := record.NewFakeRecorder(10)
+	h.r.Client = &updateErrorClient{Client: h.cli, err: errors.New("denied by webhook")}
+	h.r.Recorder = recorder
+	h.r.UpdateFailureThreshold = 3
+	h.r.UpdateFailureWindow = time.Hour
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}}
+
+	// retry by the rate limited requeue at first.
+	for i := 0; i < 2; i++ {
+		_, err = h.r.Reconcile(ctx, req)
+		require.NotNil(t, err)
+		h.advance(time.Minute)
+	}
+	require.Empty(t, recorder.Events)
+
+	// back off once it keeps failing.
+	for i := 0; i < 2; i++ {
+		result, err := h.r.Reconcile(ctx, req)
+		require.Nil(t, err)
+		require.Equal(t, DefaultUpdateFailureBackoff, result.RequeueAfter)
+	}
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, "Warning UpdateFailing Failed to update 3 times in a row")
+
+	// the failures out of the window are not counted.
+	h.advance(2 * time.Hour)
+	_, err = h.r.Reconcile(ctx, req)
+	require.NotNil(t, err)
+
+	// a success resets it.
+	h.r.Client = h.cli
+	result, err := h.r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Zero(t, result.RequeueAfter)
+	paused, _ := h.state()
+	require.True(t, paused)
+	require.False(t, h.r.isUpdateFailing(req.NamespacedName))
+	require.Empty(t, h.r.updateFailures)
+}
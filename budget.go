@@ -0,0 +1,42 @@
+package crossplanepause
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// validatePauseBudget returns an error if the PauseBudget never allows a pause.
+func (r *Reconciler) validatePauseBudget() error {
+	if r.PauseBudget == nil || r.PauseBudget.Limit() == rate.Inf {
+		return nil
+	}
+
+	if r.PauseBudget.Burst() <= 0 {
+		return fmt.Errorf("invalid PauseBudget: the burst must be positive")
+	}
+
+	return nil
+}
+
+// pauseBudgetDelay takes a token from the PauseBudget to pause a resource at now, it returns how long to wait before
+// pausing if it's out of the budget, the token is not taken in that case.
+func (r *Reconciler) pauseBudgetDelay(now time.Time) (time.Duration, error) {
+	if r.PauseBudget == nil {
+		return 0, nil
+	}
+
+	rsv := r.PauseBudget.ReserveN(now, 1)
+	if !rsv.OK() {
+		return 0, r.validatePauseBudget()
+	}
+
+	delay := rsv.DelayFrom(now)
+	if delay > 0 {
+		// Give the token back, the resource will compete for it again once requeued.
+		rsv.CancelAt(now)
+	}
+
+	return delay, nil
+}
@@ -0,0 +1,71 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPauseBudget(t *testing.T) {
+	h := newHarness(t)
+	h.r.PauseBudget = rate.NewLimiter(rate.Every(time.Minute), 2)
+	ctx := context.Background()
+
+	names := make([]string, 5)
+	for i := range names {
+		names[i] = fmt.Sprintf("thing-%d", i)
+		err := h.cli.Create(ctx, newThing(t, names[i]))
+		require.Nil(t, err)
+	}
+
+	// reconcile the not paused ones until all of them are paused, advancing to the earliest requeue.
+	rounds := 0
+	for paused := 0; paused < len(names); {
+		rounds++
+		require.LessOrEqual(t, rounds, 10)
+
+		var after time.Duration
+		for _, name := range names {
+			info, err := h.r.parsePauseInfo(getThing(t, h.cli, name))
+			require.Nil(t, err)
+			if info != nil && info.Pause {
+				continue
+			}
+
+			result, err := h.r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: name}})
+			require.Nil(t, err)
+			if result.RequeueAfter == 0 {
+				paused++
+				continue
+			}
+			require.LessOrEqual(t, result.RequeueAfter, time.Minute)
+			if after == 0 || result.RequeueAfter < after {
+				after = result.RequeueAfter
+			}
+		}
+
+		// the burst is paused at once, then one per minute.
+		if rounds == 1 {
+			require.Equal(t, 2, paused)
+		}
+		h.advance(after)
+	}
+	require.Equal(t, 4, rounds)
+}
+
+func TestValidatePauseBudget(t *testing.T) {
+	r := newThingReconciler(nil)
+	require.Nil(t, r.validatePauseBudget())
+
+	r.PauseBudget = rate.NewLimiter(rate.Inf, 0)
+	require.Nil(t, r.validatePauseBudget())
+
+	r.PauseBudget = rate.NewLimiter(1, 0)
+	require.NotNil(t, r.validatePauseBudget())
+}
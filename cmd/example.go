@@ -1,20 +1,43 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
 	"time"
 
 	ec2v1beta1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1beta1"
 	pause "github.com/july2993/crossplane-pause"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "preview" {
+		err := preview(os.Args[2:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var mgr manager.Manager
 	// ...
 	// setup mgr
 	// ...
 
+	// The Scheme must know the GroupVersionKind, or SetupWithManager fails.
+	err := ec2v1beta1.SchemeBuilder.AddToScheme(mgr.GetScheme())
+	if err != nil {
+		panic(err)
+	}
+
 	r := pause.Reconciler{
 		Client:              mgr.GetClient(),
 		Scheme:              mgr.GetScheme(),
@@ -23,8 +46,57 @@ func main() {
 		FrozenTimeDuration:  pointer.Duration(time.Minute * 5),
 	}
 
-	err := r.SetupWithManager(mgr)
+	err = r.SetupWithManager(mgr)
 	if err != nil {
 		panic(err)
 	}
 }
+
+// preview prints what the reconciler would do to every resource of a GVK without writing anything, like
+//
+//	example preview --gvk Subnet.v1beta1.ec2.aws.crossplane.io
+func preview(args []string) error {
+	fs := flag.NewFlagSet("preview", flag.ContinueOnError)
+	gvkArg := fs.String("gvk", "", "the GVK of the resources like Subnet.v1beta1.ec2.aws.crossplane.io")
+	unPausePollInterval := fs.Duration("unpause-poll-interval", 5*time.Hour, "the UnPausePollInterval, 0 disables it")
+	frozenTimeDuration := fs.Duration("frozen-time-duration", pause.DefaultFrozenTimeDuration, "the FrozenTimeDuration")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	gvk, _ := schema.ParseKindArg(*gvkArg)
+	if gvk == nil {
+		return fmt.Errorf("invalid --gvk %q, expected Kind.version.group", *gvkArg)
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("unable to get kubeconfig: %w", err)
+	}
+	cli, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return fmt.Errorf("unable to create client: %w", err)
+	}
+
+	r := &pause.Reconciler{
+		Client:             cli,
+		GroupVersionKind:   *gvk,
+		FrozenTimeDuration: frozenTimeDuration,
+	}
+	if *unPausePollInterval > 0 {
+		r.UnPausePollInterval = unPausePollInterval
+	}
+
+	decisions, err := r.PreviewDecisions(context.Background())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tACTION\tREASON")
+	for _, d := range decisions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.Key.Namespace, d.Key.Name, d.Action, d.Reason)
+	}
+	return w.Flush()
+}
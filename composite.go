@@ -0,0 +1,219 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// resourceRefs returns the composed resources referenced by spec.resourceRefs of the composite resource obj.
+func resourceRefs(obj *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	refs, found, err := unstructured.NestedSlice(obj.Object, "spec", "resourceRefs")
+	if err != nil {
+		return nil, fmt.Errorf("unable to get spec.resourceRefs: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	children := make([]*unstructured.Unstructured, 0, len(refs))
+	for i := range refs {
+		ref, ok := refs[i].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("malformed spec.resourceRefs[%d]", i)
+		}
+		apiVersion, _, _ := unstructured.NestedString(ref, "apiVersion")
+		kind, _, _ := unstructured.NestedString(ref, "kind")
+		name, _, _ := unstructured.NestedString(ref, "name")
+		namespace, _, _ := unstructured.NestedString(ref, "namespace")
+		// The composed resource is not created yet.
+		if name == "" {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil || kind == "" {
+			return nil, fmt.Errorf("malformed spec.resourceRefs[%d]", i)
+		}
+
+		child := new(unstructured.Unstructured)
+		child.SetGroupVersionKind(gv.WithKind(kind))
+		child.SetNamespace(namespace)
+		child.SetName(name)
+		children = append(children, child)
+	}
+
+	return children, nil
+}
+
+// DefaultResourceRefsConcurrency the default max number of the composed resources paused or unpaused concurrently.
+const DefaultResourceRefsConcurrency = 4
+
+func (r *Reconciler) resourceRefsConcurrency() int {
+	if r.ResourceRefsConcurrency > 0 {
+		return r.ResourceRefsConcurrency
+	}
+
+	return DefaultResourceRefsConcurrency
+}
+
+// forEachComposed calls fn with each of the composed resources which still exist, at most ResourceRefsConcurrency at a
+// time. There is no transaction across the objects, so the failure of one doesn't stop the others, the errors of all
+// the failed ones are aggregated. Once ctx is done, the ones not started yet fail with the error of ctx.
+func (r *Reconciler) forEachComposed(ctx context.Context, children []*unstructured.Unstructured, fn func(ctx context.Context, child *unstructured.Unstructured) error) error {
+	errs := make([]error, len(children))
+	sem := make(chan struct{}, r.resourceRefsConcurrency())
+	var wg sync.WaitGroup
+	for i, child := range children {
+		// The select picks randomly if both are ready, so check ctx first.
+		acquired := false
+		if ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case sem <- struct{}{}:
+				acquired = true
+			}
+		}
+		if !acquired {
+			errs[i] = fmt.Errorf("unable to get composed resource %s %s: %w", child.GroupVersionKind(), child.GetName(), ctx.Err())
+			continue
+		}
+		wg.Add(1)
+		go func(i int, child *unstructured.Unstructured) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			err := r.Client.Get(ctx, types.NamespacedName{Namespace: child.GetNamespace(), Name: child.GetName()}, child)
+			if apierrors.IsNotFound(err) {
+				return
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("unable to get composed resource %s %s: %w", child.GroupVersionKind(), child.GetName(), err)
+				return
+			}
+
+			errs[i] = fn(ctx, child)
+		}(i, child)
+	}
+	wg.Wait()
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// pauseResourceRefs pauses the composed resources of the composite resource obj we just paused with info, without
+// checking their own conditions, since the Ready and Synced of the composite already aggregate them.
+func (r *Reconciler) pauseResourceRefs(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo) error {
+	children, err := resourceRefs(obj)
+	if err != nil {
+		return err
+	}
+
+	return r.forEachComposed(ctx, children, func(ctx context.Context, child *unstructured.Unstructured) error {
+		err := r.pauseComposed(ctx, child, info)
+		if err != nil {
+			return fmt.Errorf("unable to pause composed resource %s %s: %w", child.GroupVersionKind(), child.GetName(), err)
+		}
+		return nil
+	})
+}
+
+// unpauseResourceRefs unpauses the composed resources of the composite resource obj we just unpaused, the ones paused
+// on their own, e.g. by the reconciler of their GroupVersionKind, are left alone.
+func (r *Reconciler) unpauseResourceRefs(ctx context.Context, obj *unstructured.Unstructured, reason UnpauseReason) error {
+	children, err := resourceRefs(obj)
+	if err != nil {
+		return err
+	}
+
+	return r.forEachComposed(ctx, children, func(ctx context.Context, child *unstructured.Unstructured) error {
+		if !r.pausedByUs(child) {
+			return nil
+		}
+
+		info, err := r.parsePauseInfo(child)
+		if err != nil {
+			return fmt.Errorf("unable to parse pause info of composed resource %s %s: %w", child.GroupVersionKind(), child.GetName(), err)
+		}
+
+		err = r.ensureUnPause(ctx, child, info, reason)
+		if err != nil {
+			return fmt.Errorf("unable to unpause composed resource %s %s: %w", child.GroupVersionKind(), child.GetName(), err)
+		}
+		return nil
+	})
+}
+
+// pauseComposed pauses the composed resource obj along with its composite paused with compositeInfo, it's left alone
+// if it's paused already or paused by other guy. The drift of obj is covered by the snapshot of the composite, so
+// instead of a snapshot of its own, it carries the ShouldUnpauseTime of the composite.
+func (r *Reconciler) pauseComposed(ctx context.Context, obj *unstructured.Unstructured, compositeInfo *PauseInfo) error {
+	paused := false
+	var info *PauseInfo
+	err := r.update(ctx, obj, func(obj *unstructured.Unstructured, _ bool) (bool, error) {
+		paused = false
+		var err error
+		info, err = r.parsePauseInfo(obj)
+		if err != nil {
+			return false, fmt.Errorf("unable to parse pause info: %w", err)
+		}
+		if isPaused(obj.GetAnnotations()[AnnotationKeyReconciliationPaused]) && (info == nil || !info.Pause) {
+			return false, nil
+		}
+		if info == nil {
+			info = new(PauseInfo)
+		}
+		if info.Pause {
+			return false, nil
+		}
+
+		info.Pause = true
+		now := metav1.NewTime(r.now())
+		info.LastPauseTime = &now
+		info.Object = nil
+		info.ShouldUnpauseTime = compositeInfo.ShouldUnpauseTime.DeepCopy()
+		info.UnPausePollInterval = compositeInfo.UnPausePollInterval.DeepCopy()
+		info.PauseUntil = compositeInfo.PauseUntil.DeepCopy()
+		info.SkippedUnpauses = 0
+		info.Note = pauseNote(obj)
+
+		err = r.setPauseInfo(obj, info)
+		if err != nil {
+			return false, err
+		}
+
+		ann := obj.GetAnnotations()
+		ann[AnnotationKeyReconciliationPaused] = "true"
+		ann[AnnotationKeyPausedBy] = r.controllerName()
+		obj.SetAnnotations(ann)
+		paused = true
+		return true, nil
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update object: %w", err)
+	}
+
+	if !paused {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("pause composed resource", "gvk", obj.GroupVersionKind().String(), "name", obj.GetName())
+	r.metrics().incObject(transitions, obj, string(ActionPause))
+	err = r.audit(ctx, obj, ActionPause, "composite paused", info)
+	if err != nil {
+		return err
+	}
+	// A composed resource may be a composite itself.
+	return r.pauseResourceRefs(ctx, obj, info)
+}
@@ -0,0 +1,171 @@
+package crossplanepause
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var testCompositeGVK = testGVK.GroupVersion().WithKind("XThing")
+
+func TestCascadeToResourceRefs(t *testing.T) {
+	for _, cascade := range []bool{false, true} {
+		cli := fake.NewClientBuilder().Build()
+		ctx := context.Background()
+
+		ready := newThing(t, "ready")
+		err := cli.Create(ctx, ready)
+		require.Nil(t, err)
+		unready := newThing(t, "unready")
+		setConditions(t, unready, xpv1.Creating(), xpv1.ReconcileSuccess())
+		err = cli.Create(ctx, unready)
+		require.Nil(t, err)
+		// paused on its own by the reconciler of its GroupVersionKind.
+		own := newThing(t, "own")
+		err = (&Reconciler{}).setPauseInfo(own, &PauseInfo{Pause: true})
+		require.Nil(t, err)
+		ann := own.GetAnnotations()
+		ann[AnnotationKeyReconciliationPaused] = "true"
+		ann[AnnotationKeyPausedBy] = "pause-thing"
+		own.SetAnnotations(ann)
+		err = cli.Create(ctx, own)
+		require.Nil(t, err)
+
+		// the composite is ready even if a composed resource is not, we trust its aggregation.
+		composite := newThing(t, "composite")
+		composite.SetGroupVersionKind(testCompositeGVK)
+		refs := []interface{}{
+			map[string]interface{}{"apiVersion": testGVK.GroupVersion().String(), "kind": testGVK.Kind, "name": "ready"},
+			map[string]interface{}{"apiVersion": testGVK.GroupVersion().String(), "kind": testGVK.Kind, "name": "unready"},
+			map[string]interface{}{"apiVersion": testGVK.GroupVersion().String(), "kind": testGVK.Kind, "name": "own"},
+			map[string]interface{}{"apiVersion": testGVK.GroupVersion().String(), "kind": testGVK.Kind, "name": "gone"},
+		}
+		err = unstructured.SetNestedSlice(composite.Object, refs, "spec", "resourceRefs")
+		require.Nil(t, err)
+		err = cli.Create(ctx, composite)
+		require.Nil(t, err)
+
+		r := newThingReconciler(cli)
+		r.GroupVersionKind = testCompositeGVK
+		r.CascadeToResourceRefs = cascade
+		r.UnPausePollInterval = pointer.Duration(time.Hour)
+		req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "composite"}}
+
+		isPausedByUs := func(t *testing.T, u *unstructured.Unstructured) bool {
+			t.Helper()
+			info, err := r.parsePauseInfo(u)
+			require.Nil(t, err)
+			return info != nil && info.Pause && isPaused(u.GetAnnotations()[AnnotationKeyReconciliationPaused])
+		}
+		getComposite := func(t *testing.T) *unstructured.Unstructured {
+			t.Helper()
+			u := new(unstructured.Unstructured)
+			u.SetGroupVersionKind(testCompositeGVK)
+			err := cli.Get(ctx, client.ObjectKey{Name: "composite"}, u)
+			require.Nil(t, err)
+			return u
+		}
+
+		_, err = r.Reconcile(ctx, req)
+		require.Nil(t, err)
+		require.True(t, isPausedByUs(t, getComposite(t)))
+		require.Equal(t, cascade, isPausedByUs(t, getThing(t, cli, "ready")))
+		require.Equal(t, cascade, isPausedByUs(t, getThing(t, cli, "unready")))
+		if cascade {
+			// the composed resources carry the ShouldUnpauseTime of the composite instead of their own snapshots.
+			compositeInfo, err := r.parsePauseInfo(getComposite(t))
+			require.Nil(t, err)
+			info, err := r.parsePauseInfo(getThing(t, cli, "ready"))
+			require.Nil(t, err)
+			require.Nil(t, info.Object)
+			require.NotNil(t, info.ShouldUnpauseTime)
+			require.Equal(t, compositeInfo.ShouldUnpauseTime.Unix(), info.ShouldUnpauseTime.Unix())
+		}
+
+		// unpause the composed resources along with the composite.
+		composite = getComposite(t)
+		err = unstructured.SetNestedField(composite.Object, "b", "spec", "forProvider", "field")
+		require.Nil(t, err)
+		err = cli.Update(ctx, composite)
+		require.Nil(t, err)
+		_, err = r.Reconcile(ctx, req)
+		require.Nil(t, err)
+		require.False(t, isPausedByUs(t, getComposite(t)))
+		require.False(t, isPausedByUs(t, getThing(t, cli, "ready")))
+		require.False(t, isPausedByUs(t, getThing(t, cli, "unready")))
+		// not paused by the cascade, it's left alone.
+		require.True(t, isPausedByUs(t, getThing(t, cli, "own")))
+	}
+}
+
+// nameUpdateErrorClient returns err on updating the object named name.
+type nameUpdateErrorClient struct {
+	client.Client
+	name string
+	err  error
+}
+
+func (c *nameUpdateErrorClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if obj.GetName() == c.name {
+		return c.err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func TestPauseResourceRefsPartialFailure(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	names := []string{"a", "b", "broken", "c", "d"}
+	refs := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		err := cli.Create(ctx, newThing(t, name))
+		require.Nil(t, err)
+		refs = append(refs, map[string]interface{}{"apiVersion": testGVK.GroupVersion().String(), "kind": testGVK.Kind, "name": name})
+	}
+	composite := newThing(t, "composite")
+	composite.SetGroupVersionKind(testCompositeGVK)
+	err := unstructured.SetNestedSlice(composite.Object, refs, "spec", "resourceRefs")
+	require.Nil(t, err)
+
+	r := newThingReconciler(&nameUpdateErrorClient{Client: cli, name: "broken", err: errors.New("boom")})
+	r.GroupVersionKind = testCompositeGVK
+	r.ResourceRefsConcurrency = 2
+
+	// the failure of one doesn't stop the others.
+	err = r.pauseResourceRefs(ctx, composite, &PauseInfo{Pause: true})
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "broken")
+	require.Contains(t, err.Error(), "boom")
+	for _, name := range names {
+		info, err := r.parsePauseInfo(getThing(t, cli, name))
+		require.Nil(t, err)
+		require.Equal(t, name != "broken", info != nil && info.Pause, name)
+	}
+
+	// the failed ones are aggregated.
+	r.Client = &updateErrorClient{Client: cli, err: errors.New("boom")}
+	err = r.unpauseResourceRefs(ctx, composite, UnpauseReasonUpdated)
+	require.NotNil(t, err)
+	var agg utilerrors.Aggregate
+	require.ErrorAs(t, err, &agg)
+	require.Len(t, agg.Errors(), len(names)-1)
+
+	// the ones not started yet fail once ctx is done, instead of waiting for a slot.
+	r.Client = cli
+	r.ResourceRefsConcurrency = 1
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	err = r.unpauseResourceRefs(cancelled, composite, UnpauseReasonUpdated)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,90 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultMaxScaledConcurrency the default max of the MaxConcurrentReconciles computed by the ConcurrencyFunc.
+const DefaultMaxScaledConcurrency = 50
+
+// countPageSize the page size to list the resources to count them.
+const countPageSize = 500
+
+// countTimeout the max Duration we count the resources for the ConcurrencyFunc in SetupWithManager.
+const countTimeout = time.Minute
+
+// ConcurrencyPerObjects returns a ConcurrencyFunc which runs one worker per objectsPerWorker resources.
+func ConcurrencyPerObjects(objectsPerWorker int) func(count int) int {
+	if objectsPerWorker <= 0 {
+		objectsPerWorker = 1
+	}
+
+	return func(count int) int {
+		return (count + objectsPerWorker - 1) / objectsPerWorker
+	}
+}
+
+// concurrency returns the MaxConcurrentReconciles computed by the ConcurrencyFunc from count, clamped to
+// [MinScaledConcurrency, MaxScaledConcurrency].
+func (r *Reconciler) concurrency(count int) int {
+	min, max := r.MinScaledConcurrency, r.MaxScaledConcurrency
+	if min <= 0 {
+		min = 1
+	}
+	if max <= 0 {
+		max = DefaultMaxScaledConcurrency
+	}
+	if max < min {
+		max = min
+	}
+
+	n := r.ConcurrencyFunc(count)
+	if n < min {
+		return min
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+// countObjects returns the number of the resources of the GroupVersionKind, only their metadata are listed page by page.
+func (r *Reconciler) countObjects(ctx context.Context, reader client.Reader) (int, error) {
+	list := new(metav1.PartialObjectMetadataList)
+	list.SetGroupVersionKind(r.GroupVersionKind.GroupVersion().WithKind(r.GroupVersionKind.Kind + "List"))
+
+	count := 0
+	for {
+		err := reader.List(ctx, list, client.Limit(countPageSize), client.Continue(list.GetContinue()))
+		if err != nil {
+			return 0, fmt.Errorf("unable to list %s: %w", r.GroupVersionKind, err)
+		}
+		count += len(list.Items)
+		if list.GetContinue() == "" {
+			return count, nil
+		}
+	}
+}
+
+// scaleConcurrency sets the MaxConcurrentReconciles of the controller by the ConcurrencyFunc from the number of the
+// resources now, it's a no-op if the ConcurrencyFunc is not set. The counting is bounded by the countTimeout, so a slow
+// API server fails the setup instead of hanging it.
+func (r *Reconciler) scaleConcurrency(ctx context.Context, reader client.Reader) error {
+	if r.ConcurrencyFunc == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, countTimeout)
+	defer cancel()
+	count, err := r.countObjects(ctx, reader)
+	if err != nil {
+		return fmt.Errorf("unable to count the resources for the ConcurrencyFunc: %w", err)
+	}
+	r.scaledConcurrency = r.concurrency(count)
+	return nil
+}
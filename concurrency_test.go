@@ -0,0 +1,77 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConcurrencyPerObjects(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		count       int
+		min, max    int
+		concurrency int
+	}{
+		{name: "empty", count: 0, concurrency: 1},
+		{name: "small", count: 150, concurrency: 2},
+		{name: "exact", count: 1000, concurrency: 10},
+		{name: "large", count: 100000, concurrency: DefaultMaxScaledConcurrency},
+		{name: "min", count: 150, min: 4, concurrency: 4},
+		{name: "max", count: 1000, max: 8, concurrency: 8},
+		{name: "max below min", count: 0, min: 4, max: 2, concurrency: 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newThingReconciler(nil)
+			r.ConcurrencyFunc = ConcurrencyPerObjects(100)
+			r.MinScaledConcurrency = tc.min
+			r.MaxScaledConcurrency = tc.max
+			require.Equal(t, tc.concurrency, r.concurrency(tc.count))
+		})
+	}
+
+	require.Equal(t, 3, ConcurrencyPerObjects(0)(3))
+}
+
+// deadlineReader records if the List is called with a deadline.
+type deadlineReader struct {
+	client.Reader
+	deadline bool
+}
+
+func (r *deadlineReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	_, r.deadline = ctx.Deadline()
+	return r.Reader.List(ctx, list, opts...)
+}
+
+func TestScaleConcurrency(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+	for i := 0; i < 12; i++ {
+		err := cli.Create(ctx, newThing(t, fmt.Sprintf("thing-%d", i)))
+		require.Nil(t, err)
+	}
+
+	// left as is if not set.
+	r := newThingReconciler(cli)
+	err := r.scaleConcurrency(ctx, cli)
+	require.Nil(t, err)
+	require.Equal(t, maxConcurrentReconciles, r.controllerOptions().MaxConcurrentReconciles)
+
+	r.ConcurrencyFunc = ConcurrencyPerObjects(5)
+	r.UseDefaultConcurrency = true
+	reader := &deadlineReader{Reader: cli}
+	err = r.scaleConcurrency(ctx, reader)
+	require.Nil(t, err)
+	require.Equal(t, 3, r.controllerOptions().MaxConcurrentReconciles)
+	// the counting is bounded.
+	require.True(t, reader.deadline)
+
+	count, err := r.countObjects(ctx, cli)
+	require.Nil(t, err)
+	require.Equal(t, 12, count)
+}
@@ -0,0 +1,152 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// EventReasonConditionsLost the reason of the warning event emitted once we unpause a resource whose required
+// conditions disappeared while it's paused.
+const EventReasonConditionsLost = "ConditionsLost"
+
+// presentConditions returns the required condition types obj has, it's recorded once we pause it for lostConditions.
+func (r *Reconciler) presentConditions(ctx context.Context, obj *unstructured.Unstructured) ([]string, error) {
+	var present []string
+	for _, ty := range requiredConditionTypes {
+		c, err := r.condition(ctx, obj, ty)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			present = append(present, string(ty))
+		}
+	}
+
+	return present, nil
+}
+
+// lostConditions returns the condition types in paused, the required ones obj had once we paused it, which are missing
+// from obj now, so they're removed while it's paused, e.g. by another tool acting on the provider. The Synced condition
+// is not required if it's optional or a missing one is considered as true. It returns nil under the QuiescencePeriod or
+// the ReadinessProbeOnly, which don't rely on the conditions to pause.
+func (r *Reconciler) lostConditions(ctx context.Context, obj *unstructured.Unstructured, paused []string) ([]string, error) {
+	if r.QuiescencePeriod > 0 || r.readinessProbeOnly() {
+		return nil, nil
+	}
+
+	var lost []string
+	for _, ty := range paused {
+		if ty == string(xpv1.TypeSynced) && (r.SyncedOptional || r.TreatMissingSyncedAsTrue) {
+			continue
+		}
+		c, err := r.condition(ctx, obj, xpv1.ConditionType(ty))
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			lost = append(lost, ty)
+		}
+	}
+
+	return lost, nil
+}
+
+// reportLostConditions emits a warning event if the required conditions of obj we just unpaused disappeared while
+// it's paused, paused is the ones it had once we paused it. It's kept unpaused by them missing until the provider
+// reports them again.
+func (r *Reconciler) reportLostConditions(ctx context.Context, obj *unstructured.Unstructured, paused []string) {
+	lost, err := r.lostConditions(ctx, obj, paused)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "unable to check the lost conditions")
+		return
+	}
+	if len(lost) == 0 {
+		return
+	}
+
+	log.FromContext(ctx).Info("conditions disappeared while paused", "conditions", lost)
+	r.event(obj, corev1.EventTypeWarning, EventReasonConditionsLost,
+		"Conditions %s disappeared while paused, keep it unpaused until they're reported again", strings.Join(lost, ", "))
+}
+
+// trimConditions returns the conditions of obj we keep in the snapshot for WatchConditionChanges, only the type,
+// the status and the reason are kept, sorted by the type. It returns false if obj has no conditions at all.
+func trimConditions(obj *unstructured.Unstructured) ([]interface{}, bool) {
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		return nil, false
+	}
+
+	trimmed := make([]interface{}, 0, len(conditions))
+	for _, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		res := make(map[string]interface{}, 3)
+		for _, key := range []string{"type", "status", "reason"} {
+			if v, ok := m[key]; ok {
+				res[key] = v
+			}
+		}
+		trimmed = append(trimmed, res)
+	}
+	sort.SliceStable(trimmed, func(i, j int) bool {
+		return fmt.Sprint(trimmed[i].(map[string]interface{})["type"]) < fmt.Sprint(trimmed[j].(map[string]interface{})["type"])
+	})
+
+	return trimmed, true
+}
+
+// checkConditionsEqual returns true if the trimmed conditions of obj1 and obj2 are equal. The Synced condition is not
+// compared if crossplane reports the reconcile is paused by it, which is what we do. It returns true if obj1 has no
+// conditions, e.g. it's a snapshot taken before WatchConditionChanges is set.
+func checkConditionsEqual(ctx context.Context, obj1, obj2 *unstructured.Unstructured) bool {
+	conditions1, found := trimConditions(obj1)
+	if !found {
+		return true
+	}
+	conditions2, _ := trimConditions(obj2)
+
+	if isReconcilePaused(conditions1) || isReconcilePaused(conditions2) {
+		conditions1 = withoutType(conditions1, xpv1.TypeSynced)
+		conditions2 = withoutType(conditions2, xpv1.TypeSynced)
+	}
+
+	if !reflect.DeepEqual(conditions1, conditions2) {
+		logFieldNotEqual(ctx, "status.conditions", conditions1, conditions2)
+		return false
+	}
+
+	return true
+}
+
+func isReconcilePaused(conditions []interface{}) bool {
+	for _, c := range conditions {
+		m := c.(map[string]interface{})
+		if m["type"] == string(xpv1.TypeSynced) && m["reason"] == string(xpv1.ReasonReconcilePaused) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func withoutType(conditions []interface{}, ty xpv1.ConditionType) []interface{} {
+	res := make([]interface{}, 0, len(conditions))
+	for _, c := range conditions {
+		if c.(map[string]interface{})["type"] != string(ty) {
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,203 @@
+package crossplanepause
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+)
+
+func TestWatchConditionChanges(t *testing.T) {
+	ctx := context.Background()
+	old := newThing(t, "thing")
+	setConditions(t, old, xpv1.Available(), xpv1.ReconcileSuccess())
+
+	cases := map[string]struct {
+		change  func(u *unstructured.Unstructured)
+		updated bool
+	}{
+		"Ready flipped": {
+			change: func(u *unstructured.Unstructured) {
+				setConditions(t, u, xpv1.Unavailable(), xpv1.ReconcileSuccess())
+			},
+			updated: true,
+		},
+		"new condition type": {
+			change: func(u *unstructured.Unstructured) {
+				setConditions(t, u, xpv1.Available(), xpv1.ReconcileSuccess(), xpv1.Condition{
+					Type:   "LastAsyncOperation",
+					Status: "True",
+					Reason: "Success",
+				})
+			},
+			updated: true,
+		},
+		"lastTransitionTime and observedGeneration": {
+			change: func(u *unstructured.Unstructured) {
+				later := metav1.NewTime(time.Now().Add(time.Hour))
+				ready := xpv1.Available()
+				ready.LastTransitionTime = later
+				synced := xpv1.ReconcileSuccess()
+				synced.LastTransitionTime = later
+				setConditions(t, u, synced, ready)
+				conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+				conditions[0].(map[string]interface{})["observedGeneration"] = int64(2)
+				require.Nil(t, unstructured.SetNestedSlice(u.Object, conditions, "status", "conditions"))
+			},
+			updated: false,
+		},
+		"reconcile paused": {
+			change: func(u *unstructured.Unstructured) {
+				setConditions(t, u, xpv1.Available(), xpv1.ReconcilePaused())
+			},
+			updated: false,
+		},
+	}
+
+	for name, c := range cases {
+		now := old.DeepCopy()
+		c.change(now)
+
+		r := newThingReconciler(nil)
+		updated, err := r.isUpdated(ctx, r.trimObject(old), now)
+		require.Nil(t, err)
+		require.False(t, updated, name)
+
+		r.WatchConditionChanges = true
+		updated, err = r.isUpdated(ctx, r.trimObject(old), now)
+		require.Nil(t, err)
+		require.Equal(t, c.updated, updated, name)
+
+		// a snapshot taken before it's set has no conditions to compare.
+		updated, err = r.isUpdated(ctx, newThingReconciler(nil).trimObject(old), now)
+		require.Nil(t, err)
+		require.False(t, updated, name)
+	}
+}
+
+func TestConditionsLost(t *testing.T) {
+	for _, lost := range []bool{false, true} {
+		h := newHarness(t)
+		recorder := record.NewFakeRecorder(10)
+		h.r.Recorder = recorder
+		h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+		h.r.UnPausePollJitter = pointer.Float64(0)
+		h.r.FrozenTimeDuration = pointer.Duration(0)
+		err := h.cli.Create(context.Background(), newThing(t, "thing"))
+		require.Nil(t, err)
+
+		h.reconcile()
+		paused, _ := h.state()
+		require.True(t, paused)
+		if lost {
+			// cleared by another tool while paused.
+			h.mutate(func(thing *unstructured.Unstructured) {
+				unstructured.RemoveNestedField(thing.Object, "status", "conditions")
+			})
+		}
+
+		h.advance(time.Hour)
+		h.reconcile()
+		paused, info := h.state()
+		require.False(t, paused)
+		require.Equal(t, UnpauseReasonPollInterval, info.History[len(info.History)-1].Reason)
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		if !lost {
+			require.NotContains(t, strings.Join(events, "\n"), EventReasonConditionsLost)
+			continue
+		}
+		require.Contains(t, events, "Warning ConditionsLost Conditions Ready, Synced disappeared while paused, keep it unpaused until they're reported again")
+
+		// kept unpaused until they're reported again.
+		h.reconcile()
+		paused, _ = h.state()
+		require.False(t, paused)
+		h.mutate(func(thing *unstructured.Unstructured) {
+			setConditions(t, thing, xpv1.Available(), xpv1.ReconcileSuccess())
+		})
+		h.reconcile()
+		paused, _ = h.state()
+		require.True(t, paused)
+	}
+}
+
+func TestConditionsLostSincePause(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		setup func(t *testing.T, h *harness)
+		// pause the resource, then change it while paused.
+		change func(t *testing.T, h *harness, thing *unstructured.Unstructured)
+	}{
+		{
+			name: "missing synced treated as true",
+			setup: func(t *testing.T, h *harness) {
+				h.r.TreatMissingSyncedAsTrue = true
+				thing := newThing(t, "thing")
+				setConditions(t, thing, xpv1.Available())
+				err := h.cli.Create(context.Background(), thing)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name: "synced removed but treated as true",
+			setup: func(t *testing.T, h *harness) {
+				h.r.TreatMissingSyncedAsTrue = true
+				err := h.cli.Create(context.Background(), newThing(t, "thing"))
+				require.Nil(t, err)
+			},
+			change: func(t *testing.T, h *harness, thing *unstructured.Unstructured) {
+				setConditions(t, thing, xpv1.Available())
+			},
+		},
+		{
+			name: "not recorded at pause",
+			setup: func(t *testing.T, h *harness) {
+				err := h.cli.Create(context.Background(), newThing(t, "thing"))
+				require.Nil(t, err)
+			},
+			change: func(t *testing.T, h *harness, thing *unstructured.Unstructured) {
+				// like the composed resources paused along with the composite.
+				info, err := h.r.parsePauseInfo(thing)
+				require.Nil(t, err)
+				info.Conditions = nil
+				err = h.r.setPauseInfo(thing, info)
+				require.Nil(t, err)
+				unstructured.RemoveNestedField(thing.Object, "status", "conditions")
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHarness(t)
+			recorder := record.NewFakeRecorder(10)
+			h.r.Recorder = recorder
+			h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+			h.r.UnPausePollJitter = pointer.Float64(0)
+			tc.setup(t, h)
+
+			h.reconcile()
+			paused, _ := h.state()
+			require.True(t, paused)
+			if tc.change != nil {
+				h.mutate(func(thing *unstructured.Unstructured) {
+					tc.change(t, h, thing)
+				})
+			}
+
+			h.advance(time.Hour)
+			h.reconcile()
+			paused, _ = h.state()
+			require.False(t, paused)
+			require.NotContains(t, strings.Join(drainEvents(recorder), "\n"), EventReasonConditionsLost)
+		})
+	}
+}
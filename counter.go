@@ -0,0 +1,68 @@
+package crossplanepause
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/lru"
+)
+
+// reconcileCount the reconciles of a resource since its last write.
+type reconcileCount struct {
+	// resourceVersion the resourceVersion of the last write.
+	resourceVersion string
+	count           int
+}
+
+// reconcileCounter returns the counter of the reconciles since the last write keyed by UID, or nil if it's disabled.
+func (r *Reconciler) reconcileCounter() *lru.Cache {
+	if r.ReconcileCounterSize <= 0 {
+		return nil
+	}
+
+	r.reconcileCountsOnce.Do(func() {
+		r.reconcileCounts = lru.New(r.ReconcileCounterSize)
+	})
+	return r.reconcileCounts
+}
+
+// countReconcile counts a reconcile of obj and observes the count since its last write. Our own write resets it by
+// resetReconcileCount, any other write changing the resourceVersion resets it here, so the reconcile right after
+// the write of others is not counted.
+func (r *Reconciler) countReconcile(obj *unstructured.Unstructured) {
+	counter := r.reconcileCounter()
+	if counter == nil {
+		return
+	}
+
+	c := reconcileCount{resourceVersion: obj.GetResourceVersion()}
+	if v, ok := counter.Get(obj.GetUID()); ok {
+		if last := v.(reconcileCount); last.resourceVersion == c.resourceVersion {
+			c.count = last.count + 1
+		}
+	}
+	counter.Add(obj.GetUID(), c)
+	r.metrics().observeObject(reconcilesSinceWrite, obj, float64(c.count))
+}
+
+// resetReconcileCount resets the count of obj once we write it.
+func (r *Reconciler) resetReconcileCount(obj *unstructured.Unstructured) {
+	counter := r.reconcileCounter()
+	if counter == nil || obj.GroupVersionKind() != r.GroupVersionKind {
+		return
+	}
+
+	counter.Add(obj.GetUID(), reconcileCount{resourceVersion: obj.GetResourceVersion()})
+}
+
+// countedReconciles returns the number of reconciles of obj since its last write, 0 if it's not counted.
+func (r *Reconciler) countedReconciles(obj *unstructured.Unstructured) int {
+	counter := r.reconcileCounter()
+	if counter == nil {
+		return 0
+	}
+
+	v, ok := counter.Get(obj.GetUID())
+	if !ok || v.(reconcileCount).resourceVersion != obj.GetResourceVersion() {
+		return 0
+	}
+	return v.(reconcileCount).count
+}
@@ -0,0 +1,58 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCountReconcile(t *testing.T) {
+	h := newHarness(t)
+	h.r.ReconcileCounterSize = 1
+	thing := newThing(t, "thing")
+	thing.SetUID(types.UID("thing-uid"))
+	err := h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+
+	// paused, it's our write.
+	h.reconcile()
+	paused, _ := h.state()
+	require.True(t, paused)
+	require.Equal(t, 0, h.r.countedReconciles(getThing(t, h.cli, "thing")))
+
+	// the no-op reconciles.
+	for i := 1; i <= 3; i++ {
+		h.reconcile()
+		require.Equal(t, i, h.r.countedReconciles(getThing(t, h.cli, "thing")))
+	}
+
+	// a write by others resets it.
+	h.mutate(func(thing *unstructured.Unstructured) {
+		thing.SetLabels(map[string]string{"a": "b"})
+	})
+	require.Equal(t, 0, h.r.countedReconciles(getThing(t, h.cli, "thing")))
+	h.reconcile()
+	require.Equal(t, 0, h.r.countedReconciles(getThing(t, h.cli, "thing")))
+	h.reconcile()
+	require.Equal(t, 1, h.r.countedReconciles(getThing(t, h.cli, "thing")))
+
+	// bounded, the least recently reconciled one is evicted.
+	other := newThing(t, "other")
+	other.SetUID(types.UID("other-uid"))
+	h.r.countReconcile(other)
+	h.r.countReconcile(other)
+	require.Equal(t, 1, h.r.countedReconciles(other))
+	require.Equal(t, 0, h.r.countedReconciles(getThing(t, h.cli, "thing")))
+}
+
+func TestCountReconcileDisabled(t *testing.T) {
+	r := newThingReconciler(nil)
+	thing := newThing(t, "thing")
+	r.countReconcile(thing)
+	r.countReconcile(thing)
+	require.Nil(t, r.reconcileCounter())
+	require.Equal(t, 0, r.countedReconciles(thing))
+}
@@ -0,0 +1,380 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// The reasons of the decisions which reconcile takes the side effects of, or the preview reports separately.
+const (
+	reasonFrozenWindow      = "in the frozen window"
+	reasonReconcileOnceWait = "wait for the reconcile once"
+	reasonDeleting          = "deleting"
+	reasonDeletionFailing   = "deletion failing"
+	reasonOutOfRollout      = "out of the rollout"
+	reasonNotOnboarded      = "not onboarded yet"
+	reasonPausedByOthers    = "paused by others"
+	reasonPauseUntilPassed  = "pause until passed"
+)
+
+// observation records what we observed into the pause info of a resource, e.g. the fingerprint for the
+// QuiescencePeriod, obj is the fresh object if it's refetched after a conflict.
+type observation func(obj *unstructured.Unstructured, info *PauseInfo) error
+
+// recordObservation records the observation into the pause info of the unpaused obj, info is updated in place.
+func (r *Reconciler) recordObservation(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, record observation) error {
+	err := r.update(ctx, obj, func(obj *unstructured.Unstructured, refetched bool) (bool, error) {
+		if refetched {
+			freshInfo, err := r.parsePauseInfo(obj)
+			if err != nil {
+				return false, fmt.Errorf("unable to parse pause info: %w", err)
+			}
+			if freshInfo == nil {
+				freshInfo = new(PauseInfo)
+			}
+			// Let the next reconcile handle it.
+			if freshInfo.Pause {
+				return false, nil
+			}
+			*info = *freshInfo
+		}
+
+		err := record(obj, info)
+		if err != nil {
+			return false, err
+		}
+		err = r.setPauseInfo(obj, info)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update object: %w", err)
+	}
+
+	return nil
+}
+
+// decide returns the decision for obj with its pause info at now, info is nil if there is none. It has no side effect,
+// the writes, the events and the metrics are taken by reconcile on the decision, so the preview shares it.
+// The hooks which may have side effects, the ReadinessProbe, the PrePauseValidate and the PauseBudget, are left to
+// reconcile once it decides to pause.
+func (r *Reconciler) decide(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, now time.Time) (decision, error) {
+	logger := log.FromContext(ctx)
+
+	if !r.nameAllowed(obj.GetName()) {
+		return decision{action: ActionIgnore, reason: "name excluded"}, nil
+	}
+
+	ann := obj.GetAnnotations()
+	paused := isPaused(ann[AnnotationKeyReconciliationPaused])
+
+	// We add pause ann and info ann both.
+	// in case the pause ann is added by other guy we just ignore this resource.
+	if paused && info == nil {
+		// Unless we paused it but the info ann is removed by others, unpause it or it's paused forever.
+		if r.pausedByUs(obj) {
+			return decision{action: ActionUnpause, reason: string(UnpauseReasonPauseInfoLost)}, nil
+		}
+
+		adopt, after, err := r.adoptable(ctx, obj, now)
+		if err != nil {
+			return decision{}, fmt.Errorf("unable to check conditions: %w", err)
+		}
+		if adopt && !r.observing() {
+			return decision{action: ActionPause, reason: PauseReasonAdopted}, nil
+		}
+
+		logger.Info("ignore paused by other guy")
+		return decision{action: ActionIgnore, reason: reasonPausedByOthers, after: after}, nil
+	}
+
+	// We didn't pause it this cycle, the pause ann is added manually after we unpaused it last time.
+	if r.RespectManualPause && paused && !info.Pause {
+		logger.Info("ignore paused manually")
+		return decision{action: ActionIgnore, reason: "paused manually"}, nil
+	}
+
+	// We never pause this resource yet, so missing the info annotation.
+	if info == nil {
+		info = new(PauseInfo)
+	}
+
+	// Never pause the deleted resource, unless its deletion is stuck, the UnpauseOnDeletion only decides whether
+	// to keep the resource paused before its deletion.
+	if !obj.GetDeletionTimestamp().IsZero() {
+		if !info.Pause {
+			return r.decideDeleting(ctx, obj, info, now)
+		}
+		if r.unpauseOnDeletion() && !info.DeletionStuck {
+			return decision{action: ActionUnpause, reason: string(UnpauseReasonDeleted)}, nil
+		}
+	}
+
+	if !r.specMatched(obj) {
+		if !info.Pause {
+			return decision{action: ActionIgnore, reason: "spec not matched"}, nil
+		}
+		return decision{action: ActionUnpause, reason: string(UnpauseReasonSpecNotMatched)}, nil
+	}
+
+	if info.Pause {
+		return r.decidePaused(ctx, obj, info, now)
+	}
+	return r.decideUnpaused(ctx, obj, info, now)
+}
+
+// decideDeleting returns the decision for the deleting obj which is not paused, it's never paused unless its
+// deletion keeps failing, see MaxDeletionFailures.
+func (r *Reconciler) decideDeleting(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, now time.Time) (decision, error) {
+	if r.MaxDeletionFailures <= 0 {
+		return decision{action: ActionKeepUnpaused, reason: reasonDeleting}, nil
+	}
+
+	failure, err := r.deletionFailure(ctx, obj)
+	if err != nil {
+		return decision{}, err
+	}
+	if failure == nil {
+		return decision{action: ActionKeepUnpaused, reason: reasonDeleting}, nil
+	}
+
+	stuck, after, record := r.observeDeletionFailure(info, now)
+	if !stuck {
+		log.FromContext(ctx).V(1).Info("deletion failed", "failures", info.DeletionFailures, "after", after.String())
+		return decision{action: ActionKeepUnpaused, reason: reasonDeletionFailing, after: after, record: record}, nil
+	}
+	return decision{action: ActionPause, reason: PauseReasonDeletionStuck, record: record}, nil
+}
+
+// decidePaused returns the decision for obj we paused.
+func (r *Reconciler) decidePaused(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, now time.Time) (decision, error) {
+	logger := log.FromContext(ctx)
+
+	if isReconcileOnceRequested(obj) {
+		return decision{action: ActionUnpause, reason: string(UnpauseReasonReconcileOnce)}, nil
+	}
+
+	// Checked before restoring the stripped pause, the update may be what the actor stripping it wants to apply.
+	if !r.DisableUnpauseOnUpdate && info.Object != nil {
+		updated, err := r.isUpdated(ctx, obj, info.Object)
+		if err != nil {
+			return decision{}, fmt.Errorf("unable to check if updated: %w", err)
+		}
+		if updated {
+			return decision{action: ActionUnpause, reason: string(UnpauseReasonUpdated)}, nil
+		}
+	}
+
+	if (r.RestoreStrippedPause || r.verifyingPause(info, now)) && !isPaused(obj.GetAnnotations()[AnnotationKeyReconciliationPaused]) {
+		ready, err := r.isReadyAndSynced(ctx, obj)
+		if err != nil {
+			return decision{}, err
+		}
+		if !ready {
+			return decision{action: ActionUnpause, reason: string(UnpauseReasonPauseStripped)}, nil
+		}
+
+		// Give up fighting the actor which keeps unpausing it.
+		if limit := r.maxPauseRestores(); limit > 0 && info.PauseRestores >= limit {
+			return decision{action: ActionUnpause, reason: string(UnpauseReasonPauseRestoresExhausted)}, nil
+		}
+
+		return decision{action: ActionRestorePause, reason: "pause annotation stripped", after: r.VerifyPauseRequeue}, nil
+	}
+
+	if isPinned(obj) {
+		logger.Info("keep pause since pinned")
+		return decision{action: ActionKeepPaused, reason: "pinned"}, nil
+	}
+
+	if info.PauseUntil != nil {
+		if now.Before(info.PauseUntil.Time) {
+			after := info.PauseUntil.Sub(now)
+			logger.Info("requeue after to unpause by the pause until", "after", after.String())
+			return decision{action: ActionKeepPaused, reason: "wait for the pause until", after: after}, nil
+		}
+		return decision{action: ActionUnpause, reason: string(UnpauseReasonPauseUntil)}, nil
+	}
+
+	if unPausePollInterval := r.settings().unPausePollInterval; unPausePollInterval != nil {
+		shouldUnpauseTime := shouldUnpauseTime(info, *unPausePollInterval)
+		if now.Before(shouldUnpauseTime) {
+			after := shouldUnpauseTime.Sub(now)
+			logger.Info("requeue after to check if should unpause by UnPausePollInterval", "after", after.String())
+			return decision{action: ActionKeepPaused, reason: "wait for the UnPausePollInterval", after: after}, nil
+		}
+
+		if r.SoftUnpause && (r.ForceUnpauseEvery <= 0 || info.SkippedUnpauses+1 < r.ForceUnpauseEvery) {
+			// We have checked it's not drifted above, unless DisableUnpauseOnUpdate is set.
+			drifted := false
+			if r.DisableUnpauseOnUpdate && info.Object != nil {
+				var err error
+				drifted, err = r.isUpdated(ctx, obj, info.Object)
+				if err != nil {
+					return decision{}, fmt.Errorf("unable to check if updated: %w", err)
+				}
+			}
+			if !drifted {
+				return decision{action: ActionExtendPause, reason: "not drifted"}, nil
+			}
+		}
+		return decision{action: ActionUnpause, reason: string(UnpauseReasonPollInterval)}, nil
+	}
+
+	logger.Info("keep pause")
+	return decision{action: ActionKeepPaused, reason: "paused"}, nil
+}
+
+// decideUnpaused returns the decision for obj which is not paused by us.
+func (r *Reconciler) decideUnpaused(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, now time.Time) (decision, error) {
+	logger := log.FromContext(ctx)
+
+	if info.ReconcileOnce != nil {
+		// Wait for crossplane to reconcile it instead of the frozen window.
+		done, after, err := r.reconcileOnceDone(ctx, obj, info, now)
+		if err != nil {
+			return decision{}, err
+		}
+		if !done {
+			logger.Info("keep unpause until reconciled once", "checkAfter", after.String())
+			return decision{action: ActionKeepUnpaused, reason: reasonReconcileOnceWait, after: after}, nil
+		}
+	} else if frozenTimeDuration := r.frozenTimeDuration(ctx, obj); info.LastUnPauseTime != nil && info.LastUnPauseTime.Add(frozenTimeDuration).After(now) {
+		after := info.LastUnPauseTime.Add(frozenTimeDuration).Sub(now)
+		logger.Info("keep unpause in frozen time duration", "checkAfter", after.String())
+		return decision{action: ActionKeepUnpaused, reason: reasonFrozenWindow, after: after}, nil
+	}
+
+	if r.QuiescencePeriod > 0 {
+		after, record, err := r.checkQuiescence(obj, info, now)
+		if err != nil {
+			return decision{}, err
+		}
+		if after > 0 {
+			logger.V(1).Info("not pause since changed recently", "after", after.String())
+			return decision{action: ActionKeepUnpaused, reason: "not quiescent", after: after, record: record}, nil
+		}
+	}
+
+	blocking, err := r.getBlockingCondition(ctx, obj)
+	if err != nil {
+		return decision{}, err
+	}
+
+	// The observation is recorded whatever the decision is.
+	var syncedWait time.Duration
+	var record observation
+	if r.MinSyncedObservations > 0 && r.QuiescencePeriod <= 0 {
+		syncedWait, record, err = r.observeSynced(ctx, obj, info, blocking == nil, now)
+		if err != nil {
+			return decision{}, err
+		}
+	}
+	keep := func(reason string, after time.Duration) decision {
+		return decision{action: ActionKeepUnpaused, reason: reason, after: after, record: record}
+	}
+
+	if blocking != nil && r.QuiescencePeriod <= 0 {
+		if blocking.Status == corev1.ConditionTrue {
+			logger.V(1).Info("not pause since the condition has a blocking reason", "condition", blocking.Type, "reason", blocking.Reason)
+		} else {
+			status := string(blocking.Status)
+			if status == "" {
+				status = "Missing"
+			}
+			logger.V(1).Info("not pause since the condition is not true", "condition", blocking.Type, "status", status, "reason", blocking.Reason)
+		}
+		return keep(blocking.String(), r.NotReadyRequeue), nil
+	}
+
+	if syncedWait > 0 {
+		logger.V(1).Info("not pause since not observed synced enough", "observations", info.SyncedObservations, "after", syncedWait.String())
+		return keep("not observed synced enough", syncedWait), nil
+	}
+
+	reason, after, err := r.pauseGate(ctx, obj, now)
+	if err != nil {
+		return decision{}, err
+	}
+	if reason != "" {
+		return keep(reason, after), nil
+	}
+
+	// Pausing it would unpause it at once, the stale annotation is removed instead, then it's paused as usual.
+	// The malformed one is reported by ensurePause.
+	if until, err := parsePauseUntil(obj); err == nil && until != nil && !until.After(now) && !r.observing() {
+		logger.Info("not pause since the pause until has passed", "pauseUntil", until.String())
+		return keep(reasonPauseUntilPassed, 0), nil
+	}
+
+	d := decision{action: ActionPause, reason: "quiescent", record: record}
+	if r.QuiescencePeriod <= 0 {
+		d.reason, err = r.pauseReason(ctx, obj)
+		if err != nil {
+			return decision{}, err
+		}
+	}
+	if r.observing() {
+		d.action = ActionWouldPause
+	}
+	return d, nil
+}
+
+// pauseGate returns the reason and the delay to check it again if obj, which is ready, should not be paused yet,
+// or an empty reason if it can be. It gates the fresh pause and the adoption alike.
+func (r *Reconciler) pauseGate(ctx context.Context, obj *unstructured.Unstructured, now time.Time) (string, time.Duration, error) {
+	logger := log.FromContext(ctx)
+
+	if r.QuiescencePeriod <= 0 {
+		delay, reason, err := r.settlingPolicy().Wait(ctx, obj, now)
+		if err != nil {
+			return "", 0, err
+		}
+		if delay > 0 {
+			return reason, delay, nil
+		}
+	}
+
+	pending, err := r.generationPending(obj)
+	if err != nil {
+		return "", 0, err
+	}
+	if pending {
+		logger.Info("spec change not observed yet", "generation", obj.GetGeneration())
+		return "spec change not observed", r.NotReadyRequeue, nil
+	}
+
+	if r.RequireObservedGeneration {
+		reached, err := observedGenerationReached(obj, r.observedGenerationPath())
+		if err != nil {
+			return "", 0, err
+		}
+		if !reached {
+			logger.Info("observed generation not reached yet", "generation", obj.GetGeneration())
+			return "observed generation not reached", r.NotReadyRequeue, nil
+		}
+	}
+
+	if !r.inRollout(obj) {
+		logger.V(1).Info("not pause since out of the rollout", "rolloutPercentage", *r.RolloutPercentage)
+		return reasonOutOfRollout, 0, nil
+	}
+	if delay := r.onboardDelay(obj, now); delay > 0 {
+		logger.V(1).Info("not pause since not onboarded yet", "onboardAfterAge", r.OnboardAfterAge.String())
+		return reasonNotOnboarded, delay, nil
+	}
+
+	if delay := r.providerCooldownDelay(now); delay > 0 {
+		logger.V(1).Info("not pause since the provider restarted recently", "after", delay.String())
+		return "provider restarted recently", delay, nil
+	}
+
+	return "", 0, nil
+}
@@ -0,0 +1,67 @@
+package crossplanepause
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/lru"
+)
+
+// update updates obj by updateWithRetry, and remembers the resourceVersion superseded by the update
+// if SelfWriteCacheSize is set, see isSuperseded.
+func (r *Reconciler) update(ctx context.Context, obj *unstructured.Unstructured, mutate func(obj *unstructured.Unstructured, refetched bool) (bool, error)) error {
+	var superseded string
+	err := updateWithRetry(ctx, r.kube(), obj, func(obj *unstructured.Unstructured, refetched bool) (bool, error) {
+		superseded = obj.GetResourceVersion()
+		need, err := mutate(obj, refetched)
+		if !need || err != nil {
+			superseded = ""
+		}
+		return need, err
+	})
+	if err == nil && superseded != "" {
+		r.rememberSuperseded(obj, superseded)
+		r.resetReconcileCount(obj)
+	}
+	if !apierrors.IsNotFound(err) {
+		r.recordUpdateResult(obj, err)
+	}
+
+	return err
+}
+
+// selfWriteCache returns the cache of the resourceVersions superseded by our own writes, or nil if it's disabled.
+func (r *Reconciler) selfWriteCache() *lru.Cache {
+	if r.SelfWriteCacheSize <= 0 {
+		return nil
+	}
+
+	r.selfWritesOnce.Do(func() {
+		r.selfWrites = lru.New(r.SelfWriteCacheSize)
+	})
+	return r.selfWrites
+}
+
+func (r *Reconciler) rememberSuperseded(obj *unstructured.Unstructured, resourceVersion string) {
+	cache := r.selfWriteCache()
+	// The composed resources are not the ones we reconcile, they may share the name with one we do.
+	if cache == nil || obj.GroupVersionKind() != r.GroupVersionKind {
+		return
+	}
+
+	cache.Add(types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, resourceVersion)
+}
+
+// isSuperseded returns true if obj is a stale copy we have acted on and superseded by our own write.
+// It's safe to skip reconciling it since the event of our write will reconcile the newer one.
+func (r *Reconciler) isSuperseded(obj *unstructured.Unstructured) bool {
+	cache := r.selfWriteCache()
+	if cache == nil || obj.GroupVersionKind() != r.GroupVersionKind {
+		return false
+	}
+
+	v, ok := cache.Get(types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()})
+	return ok && v.(string) == obj.GetResourceVersion()
+}
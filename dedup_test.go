@@ -0,0 +1,91 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// staleClient returns the stale copy of the object like a cache which doesn't catch up with the writes yet.
+type staleClient struct {
+	client.Client
+	stale *unstructured.Unstructured
+}
+
+func (c *staleClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.stale.DeepCopyInto(obj.(*unstructured.Unstructured))
+	return nil
+}
+
+func TestSelfWriteCache(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	err := cli.Create(ctx, newThing(t, "thing"))
+	require.Nil(t, err)
+	stale := getThing(t, cli, "thing")
+
+	r := newThingReconciler(cli)
+	r.SelfWriteCacheSize = 1
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}}
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	info, err := r.parsePauseInfo(getThing(t, cli, "thing"))
+	require.Nil(t, err)
+	require.True(t, info.Pause)
+
+	// the stale copy superseded by our own write is skipped.
+	r.Client = &staleClient{Client: cli, stale: stale}
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+
+	// the write of a composed resource with the same name doesn't evict it.
+	composed := newThing(t, "thing")
+	composed.SetGroupVersionKind(testCompositeGVK)
+	err = cli.Create(ctx, composed)
+	require.Nil(t, err)
+	err = cli.Update(ctx, composed)
+	require.Nil(t, err)
+	require.NotEqual(t, stale.GetResourceVersion(), composed.GetResourceVersion())
+	r.Client = cli
+	err = r.update(ctx, composed, func(obj *unstructured.Unstructured, refetched bool) (bool, error) {
+		obj.SetLabels(map[string]string{"composed": "true"})
+		return true, nil
+	})
+	require.Nil(t, err)
+	require.True(t, r.isSuperseded(stale))
+	require.False(t, r.isSuperseded(composed))
+	r.Client = &staleClient{Client: cli, stale: stale}
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+
+	// it keeps conflicting without the cache.
+	noCache := newThingReconciler(&staleClient{Client: cli, stale: stale})
+	_, err = noCache.Reconcile(ctx, req)
+	require.Error(t, err)
+
+	// a genuine later change is reconciled.
+	r.Client = cli
+	thing := getThing(t, cli, "thing")
+	err = unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	info, err = r.parsePauseInfo(getThing(t, cli, "thing"))
+	require.Nil(t, err)
+	require.False(t, info.Pause)
+
+	// the cache is bounded.
+	other := newThing(t, "other")
+	other.SetResourceVersion("1")
+	r.rememberSuperseded(other, "1")
+	require.True(t, r.isSuperseded(other))
+	require.Equal(t, 1, r.selfWriteCache().Len())
+}
@@ -0,0 +1,82 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// DefaultDeletionFailureInterval the default min Duration between two failed deletion attempts counted for the
+// MaxDeletionFailures.
+const DefaultDeletionFailureInterval = time.Minute
+
+// EventReasonDeletionStuck the reason of the warning event emitted once we pause a resource whose deletion is stuck.
+const EventReasonDeletionStuck = "DeletionStuck"
+
+// PauseReasonDeletionStuck the reason of pausing a deleting resource after MaxDeletionFailures failed attempts.
+const PauseReasonDeletionStuck = "deletion stuck"
+
+func (r *Reconciler) deletionFailureInterval() time.Duration {
+	if r.DeletionFailureInterval > 0 {
+		return r.DeletionFailureInterval
+	}
+
+	return DefaultDeletionFailureInterval
+}
+
+// deletionFailure returns the Synced condition of the deleting obj if it reports a failed deletion attempt, or nil.
+func (r *Reconciler) deletionFailure(ctx context.Context, obj *unstructured.Unstructured) (*xpv1.Condition, error) {
+	c, err := r.condition(ctx, obj, xpv1.TypeSynced)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get synced condition: %w", err)
+	}
+	if c == nil || c.Status != corev1.ConditionFalse || c.Reason != xpv1.ReasonReconcileError {
+		return nil, nil
+	}
+
+	return c, nil
+}
+
+// observeDeletionFailure counts the failed deletion attempts of the deleting obj with info at now, at most one per
+// DeletionFailureInterval since crossplane retries with a backoff and its status may not change between the attempts.
+// It returns true once the failures reach the MaxDeletionFailures, otherwise how long to wait for the next one, along
+// with the observation to record the count, nil if it's not changed.
+func (r *Reconciler) observeDeletionFailure(info *PauseInfo, now time.Time) (bool, time.Duration, observation) {
+	interval := r.deletionFailureInterval()
+	if info.LastDeletionFailure != nil {
+		if elapsed := now.Sub(info.LastDeletionFailure.Time); elapsed < interval {
+			return info.DeletionFailures >= r.MaxDeletionFailures, interval - elapsed, nil
+		}
+	}
+
+	count := info.DeletionFailures + 1
+	last := metav1.NewTime(now)
+	return count >= r.MaxDeletionFailures, interval, func(_ *unstructured.Unstructured, info *PauseInfo) error {
+		info.DeletionFailures = count
+		info.LastDeletionFailure = &last
+		return nil
+	}
+}
+
+// pauseStuckDeletion pauses the deleting obj whose deletion keeps failing, so crossplane stops retrying it forever,
+// and emits a warning event for the operators to intervene.
+func (r *Reconciler) pauseStuckDeletion(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, failure *xpv1.Condition) error {
+	info.DeletionStuck = true
+	err := r.ensurePause(ctx, obj, info, PauseReasonDeletionStuck)
+	if err != nil {
+		return err
+	}
+	// It's ready to pause no more, or gone.
+	if !isPaused(obj.GetAnnotations()[AnnotationKeyReconciliationPaused]) {
+		return nil
+	}
+
+	r.event(obj, corev1.EventTypeWarning, EventReasonDeletionStuck,
+		"Deletion failed %d times, pause it until an operator intervenes: %s", info.DeletionFailures, failure.Message)
+	return nil
+}
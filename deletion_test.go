@@ -0,0 +1,126 @@
+package crossplanepause
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+)
+
+// deletingThing pauses the thing and then deletes it, it's kept by the finalizer.
+func deletingThing(t *testing.T, h *harness) {
+	thing := newThing(t, "thing")
+	thing.SetFinalizers([]string{"test"})
+	err := h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+	h.reconcile()
+	paused, _ := h.state()
+	require.True(t, paused)
+
+	err = h.cli.Delete(context.Background(), getThing(t, h.cli, "thing"))
+	require.Nil(t, err)
+}
+
+// drainEvents returns the events recorded so far.
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestDeletionNotStuck(t *testing.T) {
+	h := newHarness(t)
+	recorder := record.NewFakeRecorder(10)
+	h.r.Recorder = recorder
+	h.r.MaxDeletionFailures = 2
+	deletingThing(t, h)
+
+	h.mutate(func(thing *unstructured.Unstructured) {
+		setConditions(t, thing, xpv1.Deleting(), xpv1.ReconcileSuccess())
+	})
+	for i := 0; i < 3; i++ {
+		h.reconcile()
+		paused, info := h.state()
+		require.False(t, paused)
+		require.Zero(t, info.DeletionFailures)
+		h.advance(DefaultDeletionFailureInterval)
+	}
+	for _, event := range drainEvents(recorder) {
+		require.NotContains(t, event, EventReasonDeletionStuck)
+	}
+}
+
+func TestDeletionStuck(t *testing.T) {
+	h := newHarness(t)
+	recorder := record.NewFakeRecorder(10)
+	h.r.Recorder = recorder
+	h.r.MaxDeletionFailures = 2
+	deletingThing(t, h)
+
+	h.mutate(func(thing *unstructured.Unstructured) {
+		setConditions(t, thing, xpv1.Deleting(), xpv1.ReconcileError(errors.New("DependencyViolation")))
+	})
+
+	// unpaused, then the first failure counted.
+	h.reconcile()
+	result := h.reconcile()
+	require.Equal(t, DefaultDeletionFailureInterval, result.RequeueAfter)
+	paused, info := h.state()
+	require.False(t, paused)
+	require.Equal(t, 1, info.DeletionFailures)
+
+	// counted at most once per interval.
+	h.advance(time.Second)
+	result = h.reconcile()
+	require.Equal(t, DefaultDeletionFailureInterval-time.Second, result.RequeueAfter)
+	paused, info = h.state()
+	require.False(t, paused)
+	require.Equal(t, 1, info.DeletionFailures)
+
+	// paused again with a warning.
+	h.advance(DefaultDeletionFailureInterval)
+	h.reconcile()
+	paused, info = h.state()
+	require.True(t, paused)
+	require.True(t, info.DeletionStuck)
+	require.Equal(t, 2, info.DeletionFailures)
+	var stuckEvents []string
+	for _, event := range drainEvents(recorder) {
+		if strings.Contains(event, EventReasonDeletionStuck) {
+			stuckEvents = append(stuckEvents, event)
+		}
+	}
+	require.Len(t, stuckEvents, 1)
+	require.Contains(t, stuckEvents[0], "Deletion failed 2 times")
+	require.Contains(t, stuckEvents[0], "DependencyViolation")
+
+	// kept paused though it's deleting.
+	h.advance(time.Hour)
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+
+	// an operator requests a retry.
+	h.mutate(func(thing *unstructured.Unstructured) {
+		ann := thing.GetAnnotations()
+		ann[AnnotationKeyReconcileOnce] = "true"
+		thing.SetAnnotations(ann)
+	})
+	h.reconcile()
+	paused, info = h.state()
+	require.False(t, paused)
+	require.False(t, info.DeletionStuck)
+	require.Zero(t, info.DeletionFailures)
+}
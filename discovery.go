@@ -0,0 +1,98 @@
+package crossplanepause
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// CategoryManaged the category of all the crossplane managed resources.
+const CategoryManaged = "managed"
+
+// DiscoverManagedGVKs returns the GVKs of the managed resources served by the API server whose group matches
+// groupPattern like "*.aws.crossplane.io", see path.Match for the syntax. Only the preferred version of a resource is returned.
+func DiscoverManagedGVKs(dc discovery.DiscoveryInterface, groupPattern string) ([]schema.GroupVersionKind, error) {
+	// Validate the pattern, path.Match only reports it once it meets the bad part.
+	if _, err := path.Match(groupPattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid group pattern %q: %w", groupPattern, err)
+	}
+
+	lists, err := discovery.ServerPreferredResources(dc)
+	if err != nil {
+		// Tolerate the unavailable groups we don't care about, e.g. a broken aggregated API server.
+		var failed *discovery.ErrGroupDiscoveryFailed
+		if !errors.As(err, &failed) {
+			return nil, fmt.Errorf("unable to discover resources: %w", err)
+		}
+		for gv, groupErr := range failed.Groups {
+			if ok, _ := path.Match(groupPattern, gv.Group); ok {
+				return nil, fmt.Errorf("unable to discover resources of %s: %w", gv, groupErr)
+			}
+		}
+	}
+
+	var gvks []schema.GroupVersionKind
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse group version %q: %w", list.GroupVersion, err)
+		}
+		if ok, _ := path.Match(groupPattern, gv.Group); !ok {
+			continue
+		}
+
+		for _, resource := range list.APIResources {
+			// Skip the subresources like status.
+			if strings.Contains(resource.Name, "/") || !hasCategory(resource.Categories, CategoryManaged) {
+				continue
+			}
+			gvks = append(gvks, gv.WithKind(resource.Kind))
+		}
+	}
+
+	sort.Slice(gvks, func(i, j int) bool {
+		return gvks[i].String() < gvks[j].String()
+	})
+	return gvks, nil
+}
+
+func hasCategory(categories []string, category string) bool {
+	for _, c := range categories {
+		if c == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetupReconcilers sets up a reconciler for every GVK with the manager, newReconciler returns the reconciler of a GVK
+// with the GroupVersionKind set. The pds are passed to SetupWithManager of every reconciler.
+func SetupReconcilers(mgr ctrl.Manager, gvks []schema.GroupVersionKind, newReconciler func(gvk schema.GroupVersionKind) *Reconciler,
+	pds ...predicate.Predicate) ([]*Reconciler, error) {
+	reconcilers := make([]*Reconciler, 0, len(gvks))
+	seen := make(map[schema.GroupVersionKind]bool, len(gvks))
+	for _, gvk := range gvks {
+		// The versions of a kind are reconciled separately, they may even differ in the scope.
+		if seen[gvk] {
+			return reconcilers, fmt.Errorf("duplicated GroupVersionKind %s", gvk)
+		}
+		seen[gvk] = true
+
+		r := newReconciler(gvk)
+		err := r.SetupWithManager(mgr, pds...)
+		if err != nil {
+			return reconcilers, fmt.Errorf("unable to setup reconciler of %s: %w", gvk, err)
+		}
+		reconcilers = append(reconcilers, r)
+	}
+
+	return reconcilers, nil
+}
@@ -0,0 +1,150 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// testNamespacedGVK a namespaced version of the cluster-scoped testGVK.
+var testNamespacedGVK = testGVK.GroupKind().WithVersion("v2")
+
+func TestDiscoverManagedGVKs(t *testing.T) {
+	managed := []string{"crossplane", CategoryManaged, "aws"}
+	dc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{
+			GroupVersion: "ec2.aws.crossplane.io/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "subnets", Kind: "Subnet", Categories: managed},
+				{Name: "subnets/status", Kind: "Subnet", Categories: managed},
+				{Name: "vpcs", Kind: "VPC", Categories: managed},
+			},
+		},
+		{
+			// not the preferred version.
+			GroupVersion: "ec2.aws.crossplane.io/v1alpha1",
+			APIResources: []metav1.APIResource{
+				{Name: "subnets", Kind: "Subnet", Categories: managed},
+			},
+		},
+		{
+			GroupVersion: "rds.aws.crossplane.io/v1alpha1",
+			APIResources: []metav1.APIResource{
+				{Name: "dbinstances", Kind: "DBInstance", Categories: managed},
+				// not a managed resource.
+				{Name: "configs", Kind: "Config", Categories: []string{"crossplane"}},
+			},
+		},
+		{
+			GroupVersion: "aws.crossplane.io/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "providerconfigs", Kind: "ProviderConfig", Categories: []string{"crossplane", "providerconfig", "aws"}},
+			},
+		},
+		{
+			GroupVersion: "compute.gcp.crossplane.io/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "networks", Kind: "Network", Categories: managed},
+			},
+		},
+	}}}
+
+	gvks, err := DiscoverManagedGVKs(dc, "*.aws.crossplane.io")
+	require.Nil(t, err)
+	require.Equal(t, []schema.GroupVersionKind{
+		{Group: "ec2.aws.crossplane.io", Version: "v1beta1", Kind: "Subnet"},
+		{Group: "ec2.aws.crossplane.io", Version: "v1beta1", Kind: "VPC"},
+		{Group: "rds.aws.crossplane.io", Version: "v1alpha1", Kind: "DBInstance"},
+	}, gvks)
+
+	gvks, err = DiscoverManagedGVKs(dc, "*.crossplane.io")
+	require.Nil(t, err)
+	require.Len(t, gvks, 4)
+
+	_, err = DiscoverManagedGVKs(dc, "[")
+	require.ErrorContains(t, err, "invalid group pattern")
+}
+
+func TestSetupReconcilers(t *testing.T) {
+	mgr := newTestManager(t)
+	newReconciler := func(gvk schema.GroupVersionKind) *Reconciler {
+		r := newThingReconciler(mgr.GetClient())
+		r.GroupVersionKind = gvk
+		return r
+	}
+
+	reconcilers, err := SetupReconcilers(mgr, []schema.GroupVersionKind{testGVK}, newReconciler)
+	require.Nil(t, err)
+	require.Len(t, reconcilers, 1)
+	require.Equal(t, testGVK, reconcilers[0].GroupVersionKind)
+
+	_, err = SetupReconcilers(newTestManager(t), []schema.GroupVersionKind{testGVK, testGVK.GroupVersion().WithKind("NotRegistered")}, newReconciler)
+	require.ErrorContains(t, err, "NotRegistered")
+}
+
+func TestSetupReconcilersScopes(t *testing.T) {
+	mgr := newTestManager(t)
+	cli := fake.NewClientBuilder().Build()
+	newReconciler := func(gvk schema.GroupVersionKind) *Reconciler {
+		r := newThingReconciler(cli)
+		r.GroupVersionKind = gvk
+		return r
+	}
+
+	reconcilers, err := SetupReconcilers(mgr, []schema.GroupVersionKind{testGVK, testNamespacedGVK}, newReconciler)
+	require.Nil(t, err)
+	require.Len(t, reconcilers, 2)
+	cluster, namespaced := reconcilers[0], reconcilers[1]
+	require.Equal(t, meta.RESTScopeNameRoot, cluster.scope)
+	require.Equal(t, meta.RESTScopeNameNamespace, namespaced.scope)
+	require.NotEqual(t, cluster.controllerName(), namespaced.controllerName())
+
+	// the namespace is dropped for the cluster-scoped, and kept for the namespaced.
+	key := types.NamespacedName{Namespace: "ns", Name: "thing"}
+	require.Equal(t, types.NamespacedName{Name: "thing"}, cluster.objectKey(key))
+	require.Equal(t, key, namespaced.objectKey(key))
+
+	ctx := context.Background()
+	err = cli.Create(ctx, newThing(t, "thing"))
+	require.Nil(t, err)
+	thing := newThing(t, "thing")
+	thing.SetGroupVersionKind(testNamespacedGVK)
+	thing.SetNamespace("ns")
+	err = cli.Create(ctx, thing)
+	require.Nil(t, err)
+
+	paused := func(gvk schema.GroupVersionKind, key types.NamespacedName) bool {
+		u := new(unstructured.Unstructured)
+		u.SetGroupVersionKind(gvk)
+		err := cli.Get(ctx, key, u)
+		require.Nil(t, err)
+		return isPaused(u.GetAnnotations()[AnnotationKeyReconciliationPaused])
+	}
+
+	// a request with the namespace, e.g. from the ExternalEvents, still finds the cluster-scoped one.
+	_, err = cluster.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.Nil(t, err)
+	require.True(t, paused(testGVK, types.NamespacedName{Name: "thing"}))
+	require.False(t, paused(testNamespacedGVK, key))
+
+	// a request without the namespace is ignored by the namespaced one.
+	_, err = namespaced.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "thing"}})
+	require.Nil(t, err)
+	require.False(t, paused(testNamespacedGVK, key))
+	_, err = namespaced.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.Nil(t, err)
+	require.True(t, paused(testNamespacedGVK, key))
+
+	_, err = SetupReconcilers(newTestManager(t), []schema.GroupVersionKind{testGVK, testGVK}, newReconciler)
+	require.ErrorContains(t, err, "duplicated GroupVersionKind")
+}
@@ -0,0 +1,44 @@
+package crossplanepause
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EventRecorderName the name of the event recorder used by the reconciler if the Recorder is not set.
+const EventRecorderName = "crossplane-pause"
+
+// EventReasonFrozenWindow the reason of the event emitted once a resource enters the frozen window.
+const EventReasonFrozenWindow = "FrozenWindow"
+
+// event records an event of obj if the Recorder is set.
+func (r *Reconciler) event(obj client.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+
+	r.Recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
+
+// recordFrozenWindow records an event that obj stays unpaused until end, only once for every frozen window
+// which is identified by the lastUnPauseTime, instead of every requeue.
+func (r *Reconciler) recordFrozenWindow(obj client.Object, lastUnPauseTime, end time.Time) {
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	// The same resource is never reconciled concurrently, no need to make it atomic.
+	prev, ok := r.frozenWindows.Load(key)
+	if ok && prev.(time.Time).Equal(lastUnPauseTime) {
+		return
+	}
+	r.frozenWindows.Store(key, lastUnPauseTime)
+
+	r.event(obj, corev1.EventTypeNormal, EventReasonFrozenWindow,
+		"Keep unpaused in the frozen window until %s", end.Format(time.RFC3339))
+}
+
+// forgetFrozenWindow forgets the frozen window of the resource once it's out of the window or gone.
+func (r *Reconciler) forgetFrozenWindow(key types.NamespacedName) {
+	r.frozenWindows.Delete(key)
+}
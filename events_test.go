@@ -0,0 +1,68 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFrozenWindowEvent(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	err := cli.Create(ctx, newThing(t, "thing"))
+	require.Nil(t, err)
+
+	recorder := record.NewFakeRecorder(10)
+	r := newThingReconciler(cli)
+	r.Recorder = recorder
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}}
+
+	update := func(t *testing.T, cidr string) {
+		t.Helper()
+		thing := getThing(t, cli, "thing")
+		err := unstructured.SetNestedField(thing.Object, cidr, "spec", "forProvider", "cidrBlock")
+		require.Nil(t, err)
+		err = cli.Update(ctx, thing)
+		require.Nil(t, err)
+	}
+
+	// pause then unpause by an update.
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	update(t, "a")
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, UnpauseReasonUpdated.EventReason())
+
+	// only one event no matter how many times it's requeued in the frozen window.
+	for i := 0; i < 3; i++ {
+		result, err := r.Reconcile(ctx, req)
+		require.Nil(t, err)
+		require.NotZero(t, result.RequeueAfter)
+	}
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, EventReasonFrozenWindow)
+
+	// a new frozen window.
+	r.FrozenTimeDuration = pointer.Duration(0)
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	r.FrozenTimeDuration = pointer.Duration(DefaultFrozenTimeDuration)
+	update(t, "b")
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Len(t, recorder.Events, 2)
+	require.Contains(t, <-recorder.Events, UnpauseReasonUpdated.EventReason())
+	require.Contains(t, <-recorder.Events, EventReasonFrozenWindow)
+}
@@ -0,0 +1,118 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// PauseReasonImported the reason of pausing a resource by ImportPauseState.
+const PauseReasonImported = "imported"
+
+// PauseStateEntry the pause state of a resource exported by ExportPauseState, it's serialized as JSON for the backup
+// or the migration between clusters.
+type PauseStateEntry struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Paused true if the resource has the paused annotation, by us or by others.
+	Paused bool `json:"paused"`
+	// Info the pause info, nil if we never paused the resource.
+	Info *PauseInfo `json:"info,omitempty"`
+}
+
+// ExportPauseState returns the pause state of all the resources of the GroupVersionKind, the pause info is read with
+// the configuration of the reconciler like the PauseInfoAnnotationKey and the LegacyPauseInfoAnnotationKeys.
+func (r *Reconciler) ExportPauseState(ctx context.Context) ([]PauseStateEntry, error) {
+	list := new(unstructured.UnstructuredList)
+	list.SetGroupVersionKind(r.GroupVersionKind.GroupVersion().WithKind(r.GroupVersionKind.Kind + "List"))
+	err := r.kube().List(ctx, list)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %s: %w", r.GroupVersionKind, err)
+	}
+
+	entries := make([]PauseStateEntry, 0, len(list.Items))
+	for i := range list.Items {
+		obj := &list.Items[i]
+		info, err := r.parsePauseInfo(obj)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse pause info of %s: %w", client.ObjectKeyFromObject(obj), err)
+		}
+
+		entries = append(entries, PauseStateEntry{
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+			Paused:    isPaused(obj.GetAnnotations()[AnnotationKeyReconciliationPaused]),
+			Info:      info,
+		})
+	}
+
+	return entries, nil
+}
+
+// ImportPauseState pauses the resources of the GroupVersionKind which are paused by us in the entries exported by
+// ExportPauseState, e.g. from another cluster, with the configuration of the reconciler. The history of the pause info
+// is kept, while the snapshot is taken from the resource in this cluster, so it's not unpaused for the difference
+// between the clusters. The resources which don't exist, are deleting, are not selected by the reconciler, e.g. by the
+// IncludeNames, the LabelSelector or the SpecMatch, or are not ready and synced are skipped with a warning, and the ones
+// paused already or not paused by us in the entries are left alone.
+func (r *Reconciler) ImportPauseState(ctx context.Context, entries []PauseStateEntry) error {
+	gvk := r.GroupVersionKind
+	logger := log.FromContext(ctx)
+
+	for _, entry := range entries {
+		if entry.Info == nil || !entry.Info.Pause {
+			continue
+		}
+
+		key := types.NamespacedName{Namespace: entry.Namespace, Name: entry.Name}
+		obj := new(unstructured.Unstructured)
+		obj.SetGroupVersionKind(gvk)
+		err := r.kube().Get(ctx, key, obj)
+		if apierrors.IsNotFound(err) {
+			logger.Info("skip importing pause state since resource is gone", "gvk", gvk.String(), "key", key)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to get object %s: %w", key, err)
+		}
+
+		current, err := r.parsePauseInfo(obj)
+		if err != nil {
+			return fmt.Errorf("unable to parse pause info of %s: %w", key, err)
+		}
+		if isPaused(obj.GetAnnotations()[AnnotationKeyReconciliationPaused]) || (current != nil && current.Pause) {
+			continue
+		}
+
+		if !obj.GetDeletionTimestamp().IsZero() {
+			logger.Info("skip importing pause state since resource is deleting", "gvk", gvk.String(), "key", key)
+			continue
+		}
+		if !r.selected(obj) || !r.specMatched(obj) {
+			logger.Info("skip importing pause state since resource is not selected", "gvk", gvk.String(), "key", key)
+			continue
+		}
+
+		ready, err := r.isReadyAndSynced(ctx, obj)
+		if err != nil {
+			return fmt.Errorf("unable to check if %s is ready: %w", key, err)
+		}
+		if !ready {
+			logger.Info("skip importing pause state since resource is not ready", "gvk", gvk.String(), "key", key)
+			continue
+		}
+
+		info := &PauseInfo{History: entry.Info.History}
+		err = r.ensurePause(log.IntoContext(ctx, logger.WithValues("key", key)), obj, info, PauseReasonImported)
+		if err != nil {
+			return fmt.Errorf("unable to pause %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package crossplanepause
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestExportImportPauseState(t *testing.T) {
+	ctx := context.Background()
+	h := newHarness(t)
+	h.r.PauseInfoAnnotationKey = "example.com/pause-info"
+	h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+	h.r.UnPausePollJitter = pointer.Float64(0)
+	for _, name := range []string{"thing", "gone"} {
+		err := h.cli.Create(ctx, newThing(t, name))
+		require.Nil(t, err)
+	}
+	// paused with a history.
+	h.reconcile()
+	h.advance(time.Hour)
+	h.reconcile()
+	h.advance(DefaultFrozenTimeDuration)
+	h.reconcile()
+	paused, info := h.state()
+	require.True(t, paused)
+	require.Len(t, info.History, 1)
+	// paused by others.
+	other := newThing(t, "other")
+	other.SetAnnotations(map[string]string{AnnotationKeyReconciliationPaused: "true"})
+	err := h.cli.Create(ctx, other)
+	require.Nil(t, err)
+	// never paused.
+	err = h.cli.Create(ctx, newThing(t, "new"))
+	require.Nil(t, err)
+	// paused, but missing in the other cluster.
+	h.r.UnPausePollInterval = nil
+	_, err = h.r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "gone"}})
+	require.Nil(t, err)
+
+	// paused, but not ready, deleting, excluded or not matched in the other cluster.
+	skipped := []string{"broken", "deleting", "excluded", "unmatched"}
+	for _, name := range skipped {
+		err = h.cli.Create(ctx, newThing(t, name))
+		require.Nil(t, err)
+		_, err = h.r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: name}})
+		require.Nil(t, err)
+	}
+
+	entries, err := h.r.ExportPauseState(ctx)
+	require.Nil(t, err)
+	require.Len(t, entries, 8)
+
+	data, err := json.Marshal(entries)
+	require.Nil(t, err)
+	var imported []PauseStateEntry
+	err = json.Unmarshal(data, &imported)
+	require.Nil(t, err)
+
+	dst := fake.NewClientBuilder().Build()
+	for _, name := range []string{"thing", "other", "new"} {
+		err := dst.Create(ctx, newThing(t, name))
+		require.Nil(t, err)
+	}
+	broken := newThing(t, "broken")
+	setConditions(t, broken, xpv1.Unavailable(), xpv1.ReconcileSuccess())
+	err = dst.Create(ctx, broken)
+	require.Nil(t, err)
+	deleting := newThing(t, "deleting")
+	deleting.SetFinalizers([]string{"test"})
+	err = dst.Create(ctx, deleting)
+	require.Nil(t, err)
+	err = dst.Delete(ctx, deleting)
+	require.Nil(t, err)
+	err = dst.Create(ctx, newThing(t, "excluded"))
+	require.Nil(t, err)
+	unmatched := newThing(t, "unmatched")
+	err = unstructured.SetNestedField(unmatched.Object, "large", "spec", "instanceType")
+	require.Nil(t, err)
+	err = dst.Create(ctx, unmatched)
+	require.Nil(t, err)
+	r := newThingReconciler(dst)
+	r.PauseInfoAnnotationKey = h.r.PauseInfoAnnotationKey
+	r.ExcludeNames = []string{"excluded"}
+	r.SpecMatch = func(spec map[string]interface{}) bool {
+		return spec["instanceType"] != "large"
+	}
+	err = r.ImportPauseState(ctx, imported)
+	require.Nil(t, err)
+
+	thing := getThing(t, dst, "thing")
+	require.Equal(t, "true", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+	dstInfo, err := r.parsePauseInfo(thing)
+	require.Nil(t, err)
+	require.True(t, dstInfo.Pause)
+	require.Equal(t, info.History, dstInfo.History)
+	// the snapshot is of the resource in the other cluster.
+	updated, err := r.isUpdated(ctx, thing, dstInfo.Object)
+	require.Nil(t, err)
+	require.False(t, updated)
+
+	require.NotContains(t, thing.GetAnnotations(), AnnotationKeyPauseInfo)
+	for _, name := range append([]string{"other", "new"}, skipped...) {
+		require.Empty(t, getThing(t, dst, name).GetAnnotations(), name)
+	}
+
+	// importing again is a no-op.
+	resourceVersion := thing.GetResourceVersion()
+	err = r.ImportPauseState(ctx, imported)
+	require.Nil(t, err)
+	require.Equal(t, resourceVersion, getThing(t, dst, "thing").GetResourceVersion())
+}
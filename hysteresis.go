@@ -0,0 +1,77 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// DefaultSyncedObservationInterval the default min Duration between two observations counted for the MinSyncedObservations.
+const DefaultSyncedObservationInterval = 30 * time.Second
+
+func (r *Reconciler) syncedObservationInterval() time.Duration {
+	if r.SyncedObservationInterval > 0 {
+		return r.SyncedObservationInterval
+	}
+
+	return DefaultSyncedObservationInterval
+}
+
+// syncedObservations returns the number of the observations of obj with info at now, counting the one at now
+// if it's SyncedObservationInterval after the last one, along with how long to wait for the next one.
+func (r *Reconciler) syncedObservations(info *PauseInfo, now time.Time) (int, time.Duration) {
+	interval := r.syncedObservationInterval()
+	if info.LastSyncedObservation != nil {
+		if elapsed := now.Sub(info.LastSyncedObservation.Time); elapsed < interval {
+			return info.SyncedObservations, interval - elapsed
+		}
+	}
+
+	return info.SyncedObservations + 1, interval
+}
+
+// observeSynced returns how long to wait before obj which is ready to pause is observed enough times for the
+// MinSyncedObservations, or 0 if there are enough, along with the observation to record the count, nil if it's not
+// changed. The count is reset once its Synced is false.
+func (r *Reconciler) observeSynced(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, ready bool, now time.Time) (time.Duration, observation, error) {
+	c, err := r.condition(ctx, obj, xpv1.TypeSynced)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to get synced condition: %w", err)
+	}
+
+	if c != nil && c.Status == corev1.ConditionFalse {
+		if info.SyncedObservations == 0 {
+			return 0, nil, nil
+		}
+		return 0, recordSyncedObservations(0, nil), nil
+	}
+
+	if !ready || info.SyncedObservations >= r.MinSyncedObservations {
+		return 0, nil, nil
+	}
+
+	count, after := r.syncedObservations(info, now)
+	if count == info.SyncedObservations {
+		return after, nil, nil
+	}
+
+	record := recordSyncedObservations(count, &metav1.Time{Time: now})
+	if count >= r.MinSyncedObservations {
+		return 0, record, nil
+	}
+	return after, record, nil
+}
+
+// recordSyncedObservations returns the observation recording the count and the time of the last observation.
+func recordSyncedObservations(count int, last *metav1.Time) observation {
+	return func(_ *unstructured.Unstructured, info *PauseInfo) error {
+		info.SyncedObservations = count
+		info.LastSyncedObservation = last
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package crossplanepause
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestMinSyncedObservations(t *testing.T) {
+	h := newHarness(t)
+	h.r.MinSyncedObservations = 3
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	flap := func(synced bool) {
+		h.mutate(func(thing *unstructured.Unstructured) {
+			if synced {
+				setConditions(t, thing, xpv1.Available(), xpv1.ReconcileSuccess())
+			} else {
+				setConditions(t, thing, xpv1.Available(), xpv1.ReconcileError(errors.New("boom")))
+			}
+		})
+	}
+	observations := func() int {
+		_, info := h.state()
+		if info == nil {
+			return 0
+		}
+		return info.SyncedObservations
+	}
+
+	result := h.reconcile()
+	require.Equal(t, DefaultSyncedObservationInterval, result.RequeueAfter)
+	require.Equal(t, 1, observations())
+
+	// the reconciles in between, e.g. by our own write, are not counted.
+	h.advance(10 * time.Second)
+	result = h.reconcile()
+	require.Equal(t, 20*time.Second, result.RequeueAfter)
+	require.Equal(t, 1, observations())
+
+	h.advance(20 * time.Second)
+	h.reconcile()
+	require.Equal(t, 2, observations())
+
+	// flapped, start over.
+	flap(false)
+	h.reconcile()
+	require.Equal(t, 0, observations())
+	paused, _ := h.state()
+	require.False(t, paused)
+
+	flap(true)
+	for i := 1; i < 3; i++ {
+		result := h.reconcile()
+		require.Equal(t, i, observations())
+		paused, _ := h.state()
+		require.False(t, paused)
+		h.advance(result.RequeueAfter)
+	}
+
+	// paused on the third observation in a row.
+	h.reconcile()
+	paused, info := h.state()
+	require.True(t, paused)
+	require.Zero(t, info.SyncedObservations)
+	require.Nil(t, info.LastSyncedObservation)
+}
@@ -0,0 +1,56 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// MetadataWatch configures which fields of the metadata of a paused resource are considered when checking if it's
+// updated, the spec is always considered.
+type MetadataWatch struct {
+	// WatchLabels if true, changing the labels is an update.
+	WatchLabels bool
+	// WatchAnnotations if true, changing the annotations is an update, except the ones maintained by us.
+	WatchAnnotations bool
+	// WatchFinalizers if true, adding or removing a finalizer is an update, reordering the finalizers is not.
+	WatchFinalizers bool
+	// WatchOwnerReferences if true, adding, removing or changing an owner reference is an update, reordering them is not.
+	WatchOwnerReferences bool
+}
+
+// metadataWatch returns the MetadataWatch in effect, the labels and the annotations are watched if it's not set.
+func (r *Reconciler) metadataWatch() MetadataWatch {
+	if r.MetadataWatch != nil {
+		return *r.MetadataWatch
+	}
+
+	return MetadataWatch{
+		WatchLabels:      true,
+		WatchAnnotations: true,
+		WatchFinalizers:  r.WatchFinalizers,
+	}
+}
+
+// checkOwnerReferencesEqual returns true if obj1 and obj2 have the same owner references regardless of the order.
+func checkOwnerReferencesEqual(ctx context.Context, obj1, obj2 *unstructured.Unstructured) (bool, error) {
+	refs := func(obj *unstructured.Unstructured) sets.String {
+		set := sets.NewString()
+		for _, ref := range obj.GetOwnerReferences() {
+			controller := ref.Controller != nil && *ref.Controller
+			set.Insert(fmt.Sprintf("%s/%s/%s/%s/controller=%t", ref.APIVersion, ref.Kind, ref.Name, ref.UID, controller))
+		}
+		return set
+	}
+
+	set1 := refs(obj1)
+	set2 := refs(obj2)
+	if !set1.Equal(set2) {
+		logFieldNotEqual(ctx, "metadata.ownerReferences", set1.List(), set2.List())
+		return false, nil
+	}
+
+	return true, nil
+}
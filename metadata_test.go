@@ -0,0 +1,88 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+)
+
+func TestMetadataWatch(t *testing.T) {
+	ctx := context.Background()
+	changes := map[string]func(u *unstructured.Unstructured){
+		"labels": func(u *unstructured.Unstructured) {
+			u.SetLabels(map[string]string{"team": "b"})
+		},
+		"annotations": func(u *unstructured.Unstructured) {
+			u.SetAnnotations(map[string]string{"note": "b"})
+		},
+		"finalizers": func(u *unstructured.Unstructured) {
+			u.SetFinalizers([]string{"a", "b"})
+		},
+		"ownerReferences": func(u *unstructured.Unstructured) {
+			refs := u.GetOwnerReferences()
+			refs[0].Controller = pointer.Bool(false)
+			u.SetOwnerReferences(refs)
+		},
+	}
+
+	old := newThing(t, "thing")
+	old.SetLabels(map[string]string{"team": "a"})
+	old.SetAnnotations(map[string]string{"note": "a"})
+	old.SetFinalizers([]string{"a"})
+	old.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: testCompositeGVK.GroupVersion().String(),
+		Kind:       testCompositeGVK.Kind,
+		Name:       "composite",
+		UID:        "uid",
+		Controller: pointer.Bool(true),
+	}})
+
+	r := newThingReconciler(nil)
+	for i := 0; i < 16; i++ {
+		watch := MetadataWatch{
+			WatchLabels:          i&1 != 0,
+			WatchAnnotations:     i&2 != 0,
+			WatchFinalizers:      i&4 != 0,
+			WatchOwnerReferences: i&8 != 0,
+		}
+		watched := map[string]bool{
+			"labels":          watch.WatchLabels,
+			"annotations":     watch.WatchAnnotations,
+			"finalizers":      watch.WatchFinalizers,
+			"ownerReferences": watch.WatchOwnerReferences,
+		}
+		r.MetadataWatch = &watch
+
+		for field, change := range changes {
+			now := old.DeepCopy()
+			change(now)
+			updated, err := r.isUpdated(ctx, r.trimObject(old), now)
+			require.Nil(t, err)
+			require.Equal(t, watched[field], updated, "watch %+v, change %s", watch, field)
+		}
+
+		// reordering is never an update.
+		now := old.DeepCopy()
+		now.SetFinalizers([]string{"b", "a"})
+		old := old.DeepCopy()
+		old.SetFinalizers([]string{"a", "b"})
+		updated, err := r.isUpdated(ctx, r.trimObject(old), now)
+		require.Nil(t, err)
+		require.False(t, updated, "watch %+v", watch)
+	}
+}
+
+func TestMetadataWatchDefault(t *testing.T) {
+	r := newThingReconciler(nil)
+	require.Equal(t, MetadataWatch{WatchLabels: true, WatchAnnotations: true}, r.metadataWatch())
+
+	r.WatchFinalizers = true
+	require.Equal(t, MetadataWatch{WatchLabels: true, WatchAnnotations: true, WatchFinalizers: true}, r.metadataWatch())
+
+	r.MetadataWatch = &MetadataWatch{WatchOwnerReferences: true}
+	require.Equal(t, MetadataWatch{WatchOwnerReferences: true}, r.metadataWatch())
+}
@@ -0,0 +1,289 @@
+package crossplanepause
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	unPausePollIntervalTooShort = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "crossplane_pause_unpause_poll_interval_too_short",
+		Help: "1 if the UnPausePollInterval is not comfortably larger than the provider poll interval, 0 otherwise.",
+	}, []string{"gvk"})
+
+	frozenTimeDurationTooLong = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "crossplane_pause_frozen_time_duration_too_long",
+		Help: "1 if the FrozenTimeDuration is not shorter than the UnPausePollInterval, 0 otherwise.",
+	}, []string{"gvk"})
+
+	pauseInfoBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "crossplane_pause_info_bytes",
+		Help: "The serialized size in bytes of the pause info we write last time when pausing a resource.",
+	}, []string{"gvk"})
+
+	pausedDuration = newResourceHistogram(prometheus.HistogramOpts{
+		Name:    "crossplane_pause_paused_duration_seconds",
+		Help:    "The duration a resource stays paused, observed once it's unpaused.",
+		Buckets: prometheus.ExponentialBuckets(60, 2, 12),
+	}, "reason")
+
+	pauseDeferredStability = newResourceCounter(prometheus.CounterOpts{
+		Name: "crossplane_pause_deferred_stability_total",
+		Help: "The number of times we defer pausing a resource since its conditions are not stable for the StabilityWindow.",
+	})
+
+	pauseRestores = newResourceCounter(prometheus.CounterOpts{
+		Name: "crossplane_pause_restored_total",
+		Help: "The number of times we add back the pause annotation stripped by others, a steady increase indicates a chronic race.",
+	})
+
+	wouldPauseResources = newResourceGauge(prometheus.GaugeOpts{
+		Name: "crossplane_pause_would_pause_resources",
+		Help: "The number of the resources which would be paused but are left unpaused in the Observe mode.",
+	})
+
+	reconcilesSinceWrite = newResourceHistogram(prometheus.HistogramOpts{
+		Name:    "crossplane_pause_reconciles_since_write",
+		Help:    "The number of reconciles of a resource since its last write, observed on every reconcile if the ReconcileCounterSize is set.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	})
+
+	transitions = newResourceCounter(prometheus.CounterOpts{
+		Name: "crossplane_pause_transitions_total",
+		Help: "The number of times we pause or unpause a resource.",
+	}, "action")
+
+	reconcileTimeouts = newResourceCounter(prometheus.CounterOpts{
+		Name: "crossplane_pause_reconcile_timeout_total",
+		Help: "The number of reconciles exceeding the ReconcileTimeout.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		unPausePollIntervalTooShort,
+		frozenTimeDurationTooLong,
+		pauseInfoBytes,
+		pausedDuration,
+		pauseDeferredStability,
+		pauseRestores,
+		reconcileTimeouts,
+		reconcilesSinceWrite,
+		wouldPauseResources,
+		transitions,
+	)
+}
+
+// namespacedMetricName returns the name of the namespaced twin of the metric of name, e.g.
+// crossplane_pause_namespaced_transitions_total of crossplane_pause_transitions_total.
+func namespacedMetricName(name string) string {
+	return strings.Replace(name, "crossplane_pause_", "crossplane_pause_namespaced_", 1)
+}
+
+// resourceCounter counts the resources by the gvk and the labels, and by their namespace as well in the namespaced
+// twin, which is only updated if NamespaceMetricLabel is set, so the series of the plain one never change their labels.
+type resourceCounter struct {
+	plain, namespaced *prometheus.CounterVec
+}
+
+var _ prometheus.Collector = &resourceCounter{}
+
+func newResourceCounter(opts prometheus.CounterOpts, labels ...string) *resourceCounter {
+	plain := prometheus.NewCounterVec(opts, append([]string{"gvk"}, labels...))
+	opts.Name = namespacedMetricName(opts.Name)
+	namespaced := prometheus.NewCounterVec(opts, append([]string{"gvk", "namespace"}, labels...))
+	return &resourceCounter{plain: plain, namespaced: namespaced}
+}
+
+// Describe implements prometheus.Collector.
+func (c *resourceCounter) Describe(ch chan<- *prometheus.Desc) {
+	c.plain.Describe(ch)
+	c.namespaced.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *resourceCounter) Collect(ch chan<- prometheus.Metric) {
+	c.plain.Collect(ch)
+	c.namespaced.Collect(ch)
+}
+
+// Reset deletes all the series.
+func (c *resourceCounter) Reset() {
+	c.plain.Reset()
+	c.namespaced.Reset()
+}
+
+// resourceHistogram is the histogram counterpart of the resourceCounter.
+type resourceHistogram struct {
+	plain, namespaced *prometheus.HistogramVec
+}
+
+var _ prometheus.Collector = &resourceHistogram{}
+
+func newResourceHistogram(opts prometheus.HistogramOpts, labels ...string) *resourceHistogram {
+	plain := prometheus.NewHistogramVec(opts, append([]string{"gvk"}, labels...))
+	opts.Name = namespacedMetricName(opts.Name)
+	namespaced := prometheus.NewHistogramVec(opts, append([]string{"gvk", "namespace"}, labels...))
+	return &resourceHistogram{plain: plain, namespaced: namespaced}
+}
+
+// Describe implements prometheus.Collector.
+func (h *resourceHistogram) Describe(ch chan<- *prometheus.Desc) {
+	h.plain.Describe(ch)
+	h.namespaced.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (h *resourceHistogram) Collect(ch chan<- prometheus.Metric) {
+	h.plain.Collect(ch)
+	h.namespaced.Collect(ch)
+}
+
+// resourceGauge is the gauge counterpart of the resourceCounter, it counts the members added by gvkMetrics.add.
+type resourceGauge struct {
+	plain, namespaced *prometheus.GaugeVec
+}
+
+var _ prometheus.Collector = &resourceGauge{}
+
+func newResourceGauge(opts prometheus.GaugeOpts) *resourceGauge {
+	plain := prometheus.NewGaugeVec(opts, []string{"gvk"})
+	opts.Name = namespacedMetricName(opts.Name)
+	namespaced := prometheus.NewGaugeVec(opts, []string{"gvk", "namespace"})
+	return &resourceGauge{plain: plain, namespaced: namespaced}
+}
+
+// Describe implements prometheus.Collector.
+func (g *resourceGauge) Describe(ch chan<- *prometheus.Desc) {
+	g.plain.Describe(ch)
+	g.namespaced.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (g *resourceGauge) Collect(ch chan<- prometheus.Metric) {
+	g.plain.Collect(ch)
+	g.namespaced.Collect(ch)
+}
+
+// Reset deletes all the series.
+func (g *resourceGauge) Reset() {
+	g.plain.Reset()
+	g.namespaced.Reset()
+}
+
+// gvkMetrics updates the metrics labeled by the GroupVersionKind of a Reconciler, it's safe to use from the
+// concurrent reconcile workers. The gauges counting the resources are set along with their members under the lock,
+// so a stale count never overwrites a newer one. The namespaced twins of the metrics of the resources are updated
+// as well if namespaced is set.
+type gvkMetrics struct {
+	gvk        string
+	namespaced bool
+
+	mu sync.Mutex
+	// members the resources counted by the gauges, keyed by their namespace.
+	members map[*resourceGauge]map[string]map[types.NamespacedName]struct{}
+}
+
+func newGVKMetrics(gvk schema.GroupVersionKind, namespaced bool) *gvkMetrics {
+	return &gvkMetrics{
+		gvk:        gvk.String(),
+		namespaced: namespaced,
+		members:    make(map[*resourceGauge]map[string]map[types.NamespacedName]struct{}),
+	}
+}
+
+// set sets the gauge of the GroupVersionKind with the label values following the gvk.
+func (m *gvkMetrics) set(gauge *prometheus.GaugeVec, v float64, labels ...string) {
+	gauge.WithLabelValues(append([]string{m.gvk}, labels...)...).Set(v)
+}
+
+// inc increases the counter of the resources in namespace with the label values following the gvk, and the namespace
+// in the namespaced twin.
+func (m *gvkMetrics) inc(counter *resourceCounter, namespace string, labels ...string) {
+	m.incGVK(counter, m.gvk, namespace, labels...)
+}
+
+// incObject increases the counter of obj, which may be of another GroupVersionKind like a composed resource.
+func (m *gvkMetrics) incObject(counter *resourceCounter, obj *unstructured.Unstructured, labels ...string) {
+	m.incGVK(counter, obj.GroupVersionKind().String(), obj.GetNamespace(), labels...)
+}
+
+func (m *gvkMetrics) incGVK(counter *resourceCounter, gvk, namespace string, labels ...string) {
+	counter.plain.WithLabelValues(append([]string{gvk}, labels...)...).Inc()
+	if m.namespaced {
+		counter.namespaced.WithLabelValues(append([]string{gvk, namespace}, labels...)...).Inc()
+	}
+}
+
+// observeObject observes v in the histogram of obj, which may be of another GroupVersionKind like a composed resource.
+func (m *gvkMetrics) observeObject(histogram *resourceHistogram, obj *unstructured.Unstructured, v float64, labels ...string) {
+	gvk := obj.GroupVersionKind().String()
+	histogram.plain.WithLabelValues(append([]string{gvk}, labels...)...).Observe(v)
+	if m.namespaced {
+		histogram.namespaced.WithLabelValues(append([]string{gvk, obj.GetNamespace()}, labels...)...).Observe(v)
+	}
+}
+
+// add counts the resource of key in the gauge, it returns false if it's counted already.
+func (m *gvkMetrics) add(gauge *resourceGauge, key types.NamespacedName) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	namespaces, ok := m.members[gauge]
+	if !ok {
+		namespaces = make(map[string]map[types.NamespacedName]struct{})
+		m.members[gauge] = namespaces
+	}
+	members, ok := namespaces[key.Namespace]
+	if !ok {
+		members = make(map[types.NamespacedName]struct{})
+		namespaces[key.Namespace] = members
+	}
+	if _, ok := members[key]; ok {
+		return false
+	}
+	members[key] = struct{}{}
+	m.setMembers(gauge, key.Namespace)
+	return true
+}
+
+// remove stops counting the resource of key in the gauge, it returns false if it's not counted.
+func (m *gvkMetrics) remove(gauge *resourceGauge, key types.NamespacedName) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	members := m.members[gauge][key.Namespace]
+	if _, ok := members[key]; !ok {
+		return false
+	}
+	delete(members, key)
+	m.setMembers(gauge, key.Namespace)
+	return true
+}
+
+// setMembers sets the gauge to the number of its members, and the namespaced twin to the one in namespace.
+// It must be called with the lock held.
+func (m *gvkMetrics) setMembers(gauge *resourceGauge, namespace string) {
+	total := 0
+	for _, members := range m.members[gauge] {
+		total += len(members)
+	}
+	gauge.plain.WithLabelValues(m.gvk).Set(float64(total))
+	if m.namespaced {
+		gauge.namespaced.WithLabelValues(m.gvk, namespace).Set(float64(len(m.members[gauge][namespace])))
+	}
+}
+
+// metrics returns the metrics of the GroupVersionKind of r.
+func (r *Reconciler) metrics() *gvkMetrics {
+	r.gvkMetricsOnce.Do(func() {
+		r.gvkMetrics = newGVKMetrics(r.GroupVersionKind, r.NamespaceMetricLabel)
+	})
+	return r.gvkMetrics
+}
@@ -0,0 +1,136 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// TestGVKMetricsConcurrent is meant to run with -race.
+func TestGVKMetricsConcurrent(t *testing.T) {
+	gauge := newResourceGauge(prometheus.GaugeOpts{Name: "test_members"})
+	counter := newResourceCounter(prometheus.CounterOpts{Name: "test_total"})
+	histogram := newResourceHistogram(prometheus.HistogramOpts{Name: "test_seconds"})
+
+	// the reconcilers of two GroupVersionKinds sharing the metrics, each with several workers.
+	all := []*gvkMetrics{newGVKMetrics(testGVK, false), newGVKMetrics(testGVK.GroupVersion().WithKind("Other"), false)}
+	const workers = 8
+	const keys = 50
+	var wg sync.WaitGroup
+	for _, m := range all {
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(m *gvkMetrics, w int) {
+				defer wg.Done()
+				obj := new(unstructured.Unstructured)
+				obj.SetGroupVersionKind(testGVK)
+				obj.SetNamespace(m.gvk)
+				for i := 0; i < keys; i++ {
+					key := types.NamespacedName{Name: fmt.Sprintf("thing-%d", i)}
+					m.add(gauge, key)
+					m.inc(counter, "")
+					m.observeObject(histogram, obj, float64(i))
+					// the odd workers remove the odd keys while the even ones add them back.
+					if i%2 == 1 && w%2 == 1 {
+						m.remove(gauge, key)
+					}
+				}
+			}(m, w)
+		}
+	}
+	wg.Wait()
+
+	for _, m := range all {
+		require.Equal(t, float64(workers*keys), testutil.ToFloat64(counter.plain.WithLabelValues(m.gvk)))
+
+		sample := new(dto.Metric)
+		err := histogram.plain.WithLabelValues(testGVK.String()).(prometheus.Metric).Write(sample)
+		require.Nil(t, err)
+		require.Equal(t, uint64(len(all)*workers*keys), sample.Histogram.GetSampleCount())
+
+		// the gauge matches the members once it's counted again, whatever the interleaving is.
+		m.remove(gauge, types.NamespacedName{Name: "thing-0"})
+		m.mu.Lock()
+		members := len(m.members[gauge][""])
+		m.mu.Unlock()
+		require.Equal(t, float64(members), testutil.ToFloat64(gauge.plain.WithLabelValues(m.gvk)))
+		require.True(t, m.add(gauge, types.NamespacedName{Name: "thing-0"}))
+		require.False(t, m.add(gauge, types.NamespacedName{Name: "thing-0"}))
+		require.Equal(t, float64(members+1), testutil.ToFloat64(gauge.plain.WithLabelValues(m.gvk)))
+		require.True(t, m.remove(gauge, types.NamespacedName{Name: "thing-0"}))
+		require.False(t, m.remove(gauge, types.NamespacedName{Name: "thing-0"}))
+		require.Equal(t, float64(members), testutil.ToFloat64(gauge.plain.WithLabelValues(m.gvk)))
+	}
+	// the namespaced twins are left alone.
+	require.Equal(t, 0, testutil.CollectAndCount(counter.namespaced))
+	require.Equal(t, 0, testutil.CollectAndCount(histogram.namespaced))
+	require.Equal(t, 0, testutil.CollectAndCount(gauge.namespaced))
+}
+
+func TestGVKMetricsNamespace(t *testing.T) {
+	gauge := newResourceGauge(prometheus.GaugeOpts{Name: "crossplane_pause_test_members"})
+	counter := newResourceCounter(prometheus.CounterOpts{Name: "crossplane_pause_test_total"})
+	require.Equal(t, "crossplane_pause_namespaced_test_total", namespacedMetricName("crossplane_pause_test_total"))
+
+	for _, namespaced := range []bool{true, false} {
+		gauge.Reset()
+		counter.Reset()
+		m := newGVKMetrics(testGVK, namespaced)
+		for _, key := range []types.NamespacedName{{Namespace: "a", Name: "x"}, {Namespace: "a", Name: "y"}, {Namespace: "b", Name: "x"}} {
+			m.add(gauge, key)
+			m.inc(counter, key.Namespace)
+		}
+
+		// the plain ones keep their labels either way.
+		require.Equal(t, 3.0, testutil.ToFloat64(gauge.plain.WithLabelValues(testGVK.String())))
+		require.Equal(t, 3.0, testutil.ToFloat64(counter.plain.WithLabelValues(testGVK.String())))
+		if namespaced {
+			require.Equal(t, 2, testutil.CollectAndCount(gauge.namespaced))
+			require.Equal(t, 2.0, testutil.ToFloat64(gauge.namespaced.WithLabelValues(testGVK.String(), "a")))
+			require.Equal(t, 1.0, testutil.ToFloat64(gauge.namespaced.WithLabelValues(testGVK.String(), "b")))
+			require.Equal(t, 2, testutil.CollectAndCount(counter.namespaced))
+			require.Equal(t, 2.0, testutil.ToFloat64(counter.namespaced.WithLabelValues(testGVK.String(), "a")))
+
+			m.remove(gauge, types.NamespacedName{Namespace: "a", Name: "x"})
+			require.Equal(t, 2.0, testutil.ToFloat64(gauge.plain.WithLabelValues(testGVK.String())))
+			require.Equal(t, 1.0, testutil.ToFloat64(gauge.namespaced.WithLabelValues(testGVK.String(), "a")))
+			continue
+		}
+		require.Equal(t, 0, testutil.CollectAndCount(gauge.namespaced))
+		require.Equal(t, 0, testutil.CollectAndCount(counter.namespaced))
+	}
+}
+
+func TestTransitionsNamespaceLabel(t *testing.T) {
+	for _, namespaced := range []bool{true, false} {
+		t.Run(fmt.Sprintf("namespaced=%t", namespaced), func(t *testing.T) {
+			transitions.Reset()
+			h := newHarness(t)
+			h.r.NamespaceMetricLabel = namespaced
+			thing := newThing(t, "thing")
+			thing.SetNamespace("tenant-a")
+			err := h.cli.Create(context.Background(), thing)
+			require.Nil(t, err)
+
+			_, err = h.r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "tenant-a", Name: "thing"}})
+			require.Nil(t, err)
+
+			require.Equal(t, 1.0, testutil.ToFloat64(transitions.plain.WithLabelValues(testGVK.String(), string(ActionPause))))
+			if namespaced {
+				require.Equal(t, 2, testutil.CollectAndCount(transitions))
+				require.Equal(t, 1.0, testutil.ToFloat64(transitions.namespaced.WithLabelValues(testGVK.String(), "tenant-a", string(ActionPause))))
+				return
+			}
+			require.Equal(t, 1, testutil.CollectAndCount(transitions))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// isLegacyPauseInfo returns true if the pause info stores the whole object instead of the trimmed one.
+func (r *Reconciler) isLegacyPauseInfo(info *PauseInfo) bool {
+	if info == nil || info.Object == nil {
+		return false
+	}
+
+	return !reflect.DeepEqual(r.trimObject(info.Object).Object, info.Object.Object)
+}
+
+// hasLegacyPauseInfoKey returns true if obj still carries any of the LegacyPauseInfoAnnotationKeys.
+func (r *Reconciler) hasLegacyPauseInfoKey(obj *unstructured.Unstructured) bool {
+	ann := obj.GetAnnotations()
+	for _, key := range r.LegacyPauseInfoAnnotationKeys {
+		if _, ok := ann[key]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// needMigrate returns true if the pause info of obj is stored in the legacy form or under a legacy key.
+func (r *Reconciler) needMigrate(obj *unstructured.Unstructured, info *PauseInfo) bool {
+	return r.isLegacyPauseInfo(info) || r.hasLegacyPauseInfoKey(obj)
+}
+
+// migratePauseInfo rewrites the legacy pause info of obj into the compact form under the PauseInfoAnnotationKey.
+// Only the storage is changed, the pause decision is kept as it is.
+func (r *Reconciler) migratePauseInfo(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo) error {
+	err := r.update(ctx, obj, func(obj *unstructured.Unstructured, refetched bool) (bool, error) {
+		if refetched {
+			freshInfo, err := r.parsePauseInfo(obj)
+			if err != nil {
+				return false, fmt.Errorf("unable to parse pause info: %w", err)
+			}
+			if !r.needMigrate(obj, freshInfo) {
+				return false, nil
+			}
+			info = freshInfo
+		}
+
+		if info.Object != nil {
+			info.Object = r.trimObject(info.Object)
+		}
+
+		err := r.setPauseInfo(obj, info)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update object: %w", err)
+	}
+
+	log.FromContext(ctx).Info("migrate legacy pause info")
+	return nil
+}
+
+// MigratePauseInfo rewrites the legacy pause info of all the resources of the GroupVersionKind into the compact form
+// under the PauseInfoAnnotationKey. The legacy pause info stores the whole object or is stored under a legacy key.
+// Reconcile migrates a paused resource on the next reconcile anyway, this is a way to do it proactively.
+// It's idempotent and returns the number of migrated resources.
+func (r *Reconciler) MigratePauseInfo(ctx context.Context) (int, error) {
+	list := new(unstructured.UnstructuredList)
+	list.SetGroupVersionKind(r.GroupVersionKind.GroupVersion().WithKind(r.GroupVersionKind.Kind + "List"))
+	err := r.kube().List(ctx, list)
+	if err != nil {
+		return 0, fmt.Errorf("unable to list %s: %w", r.GroupVersionKind, err)
+	}
+
+	migrated := 0
+	for i := range list.Items {
+		obj := &list.Items[i]
+		info, err := r.parsePauseInfo(obj)
+		if err != nil {
+			return migrated, fmt.Errorf("unable to parse pause info of %s: %w", client.ObjectKeyFromObject(obj), err)
+		}
+
+		if info == nil || !r.needMigrate(obj, info) {
+			continue
+		}
+
+		err = r.migratePauseInfo(ctx, obj, info)
+		if err != nil {
+			return migrated, fmt.Errorf("unable to migrate %s: %w", client.ObjectKeyFromObject(obj), err)
+		}
+		migrated++
+	}
+
+	return migrated, nil
+}
@@ -0,0 +1,220 @@
+package crossplanepause
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	ec2v1beta1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1beta1"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// createLegacyPaused creates a subnet paused with the legacy pause info which stores the whole object.
+func createLegacyPaused(t *testing.T, cli client.Client, name string) *ec2v1beta1.Subnet {
+	t.Helper()
+	ctx := context.Background()
+
+	subnet := &ec2v1beta1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				"some": "value",
+			},
+		},
+		Spec: ec2v1beta1.SubnetSpec{
+			ForProvider: ec2v1beta1.SubnetParameters{
+				CIDRBlock: "a",
+			},
+		},
+		Status: ec2v1beta1.SubnetStatus{
+			AtProvider: ec2v1beta1.SubnetObservation{
+				SubnetID: "a",
+			},
+		},
+	}
+	subnet.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+	err := cli.Create(ctx, subnet)
+	require.Nil(t, err)
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(ec2v1beta1.SubnetGroupVersionKind)
+	err = cli.Get(ctx, client.ObjectKeyFromObject(subnet), u)
+	require.Nil(t, err)
+
+	now := metav1.Now()
+	info := &PauseInfo{
+		Pause:             true,
+		Object:            u.DeepCopy(),
+		LastPauseTime:     &now,
+		ShouldUnpauseTime: &metav1.Time{Time: now.Add(time.Hour)},
+	}
+	data, err := json.Marshal(info)
+	require.Nil(t, err)
+
+	ann := u.GetAnnotations()
+	ann[AnnotationKeyReconciliationPaused] = "true"
+	ann[AnnotationKeyPauseInfo] = string(data)
+	u.SetAnnotations(ann)
+	err = cli.Update(ctx, u)
+	require.Nil(t, err)
+
+	return subnet
+}
+
+func getPauseInfo(t *testing.T, cli client.Client, key client.ObjectKey) (*unstructured.Unstructured, *PauseInfo) {
+	t.Helper()
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(ec2v1beta1.SubnetGroupVersionKind)
+	err := cli.Get(context.Background(), key, u)
+	require.Nil(t, err)
+	info, err := new(Reconciler).parsePauseInfo(u)
+	require.Nil(t, err)
+	return u, info
+}
+
+func TestMigrateOnReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = ec2v1beta1.SchemeBuilder.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	ctx := context.Background()
+
+	subnet := createLegacyPaused(t, cli, "test-subnet")
+	key := client.ObjectKeyFromObject(subnet)
+	r := &Reconciler{
+		Client:              cli,
+		Scheme:              scheme,
+		GroupVersionKind:    ec2v1beta1.SubnetGroupVersionKind,
+		UnPausePollInterval: pointer.Duration(time.Hour),
+		FrozenTimeDuration:  pointer.Duration(DefaultFrozenTimeDuration),
+	}
+	_, legacy := getPauseInfo(t, cli, key)
+	require.True(t, r.isLegacyPauseInfo(legacy))
+	require.Contains(t, legacy.Object.Object, "status")
+
+	res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.Nil(t, err)
+	require.True(t, res.RequeueAfter > 0)
+
+	u, info := getPauseInfo(t, cli, key)
+	require.False(t, r.isLegacyPauseInfo(info))
+	require.NotContains(t, info.Object.Object, "status")
+	require.Equal(t, "true", u.GetAnnotations()[AnnotationKeyReconciliationPaused])
+	// the pause decision is preserved.
+	require.True(t, info.Pause)
+	require.True(t, legacy.LastPauseTime.Equal(info.LastPauseTime))
+	require.True(t, legacy.ShouldUnpauseTime.Equal(info.ShouldUnpauseTime))
+	updated, err := r.isUpdated(ctx, u, info.Object)
+	require.Nil(t, err)
+	require.False(t, updated)
+
+	// reconcile again won't touch it.
+	rv := u.GetResourceVersion()
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.Nil(t, err)
+	u, _ = getPauseInfo(t, cli, key)
+	require.Equal(t, rv, u.GetResourceVersion())
+}
+
+func TestMigratePauseInfo(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = ec2v1beta1.SchemeBuilder.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		createLegacyPaused(t, cli, fmt.Sprintf("test-subnet-%d", i))
+	}
+	notPaused := &ec2v1beta1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "not-paused",
+		},
+	}
+	err := cli.Create(ctx, notPaused)
+	require.Nil(t, err)
+
+	r := &Reconciler{
+		Client:           cli,
+		GroupVersionKind: ec2v1beta1.SubnetGroupVersionKind,
+	}
+	migrated, err := r.MigratePauseInfo(ctx)
+	require.Nil(t, err)
+	require.Equal(t, 3, migrated)
+
+	for i := 0; i < 3; i++ {
+		u, info := getPauseInfo(t, cli, client.ObjectKey{Name: fmt.Sprintf("test-subnet-%d", i)})
+		require.True(t, info.Pause)
+		require.False(t, r.isLegacyPauseInfo(info))
+		require.Equal(t, "true", u.GetAnnotations()[AnnotationKeyReconciliationPaused])
+	}
+
+	// it's idempotent.
+	migrated, err = r.MigratePauseInfo(ctx)
+	require.Nil(t, err)
+	require.Equal(t, 0, migrated)
+}
+
+func TestLegacyPauseInfoAnnotationKeys(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+	legacyKey := "legacy.io/pause-info"
+	newKey := "new.io/pause-info"
+
+	// paused with the legacy key.
+	paused := newThing(t, "paused")
+	legacyR := newThingReconciler(cli)
+	legacyR.PauseInfoAnnotationKey = legacyKey
+	err := cli.Create(ctx, paused)
+	require.Nil(t, err)
+	_, err = legacyR.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(paused)})
+	require.Nil(t, err)
+	paused = getThing(t, cli, "paused")
+	require.Contains(t, paused.GetAnnotations(), legacyKey)
+
+	// unpaused with the legacy key.
+	unpaused := newThing(t, "unpaused")
+	lastUnPauseTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	err = legacyR.setPauseInfo(unpaused, &PauseInfo{LastUnPauseTime: &lastUnPauseTime})
+	require.Nil(t, err)
+	err = cli.Create(ctx, unpaused)
+	require.Nil(t, err)
+
+	r := newThingReconciler(cli)
+	r.UnPausePollInterval = pointer.Duration(time.Hour)
+	r.PauseInfoAnnotationKey = newKey
+	r.LegacyPauseInfoAnnotationKeys = []string{legacyKey}
+
+	// read from the legacy key.
+	info, err := r.parsePauseInfo(paused)
+	require.Nil(t, err)
+	require.True(t, info.Pause)
+	updated, err := r.isUpdated(ctx, paused, info.Object)
+	require.Nil(t, err)
+	require.False(t, updated)
+
+	for _, name := range []string{"paused", "unpaused"} {
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: name}})
+		require.Nil(t, err)
+		thing := getThing(t, cli, name)
+		require.NotContains(t, thing.GetAnnotations(), legacyKey)
+		require.Contains(t, thing.GetAnnotations(), newKey)
+		require.Equal(t, "true", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+		info, err := r.parsePauseInfo(thing)
+		require.Nil(t, err)
+		require.True(t, info.Pause)
+	}
+	// the previous unpause time is kept.
+	info, err = r.parsePauseInfo(getThing(t, cli, "unpaused"))
+	require.Nil(t, err)
+	require.True(t, lastUnPauseTime.Equal(info.LastUnPauseTime))
+}
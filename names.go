@@ -0,0 +1,39 @@
+package crossplanepause
+
+import (
+	"fmt"
+	"path"
+)
+
+// validateNames returns an error if any pattern of the IncludeNames and ExcludeNames is malformed.
+func (r *Reconciler) validateNames() error {
+	for _, patterns := range [][]string{r.IncludeNames, r.ExcludeNames} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// nameAllowed returns true if the resource of name should be reconciled by the IncludeNames and ExcludeNames.
+func (r *Reconciler) nameAllowed(name string) bool {
+	if matchAnyName(r.ExcludeNames, name) {
+		return false
+	}
+
+	return len(r.IncludeNames) == 0 || matchAnyName(r.IncludeNames, name)
+}
+
+// matchAnyName returns true if name matches any of the glob patterns, the malformed patterns match nothing.
+func matchAnyName(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
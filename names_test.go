@@ -0,0 +1,58 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestNames(t *testing.T) {
+	names := []string{"prod-a", "prod-b", "dev-a", "legacy"}
+	for _, tc := range []struct {
+		name    string
+		include []string
+		exclude []string
+		paused  []string
+	}{
+		{name: "all", paused: names},
+		{name: "include only", include: []string{"legacy"}, paused: []string{"legacy"}},
+		{name: "exclude", exclude: []string{"legacy"}, paused: []string{"prod-a", "prod-b", "dev-a"}},
+		{name: "glob", include: []string{"prod-*", "*-a"}, exclude: []string{"prod-b"}, paused: []string{"prod-a", "dev-a"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().Build()
+			ctx := context.Background()
+			r := newThingReconciler(cli)
+			r.IncludeNames = tc.include
+			r.ExcludeNames = tc.exclude
+			require.Nil(t, r.validateNames())
+			pd := r.predicate()
+
+			var paused, passed []string
+			for _, name := range names {
+				thing := newThing(t, name)
+				err := cli.Create(ctx, thing)
+				require.Nil(t, err)
+				if pd.Create(event.CreateEvent{Object: thing}) {
+					passed = append(passed, name)
+				}
+
+				_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: name}})
+				require.Nil(t, err)
+				if isPaused(getThing(t, cli, name).GetAnnotations()[AnnotationKeyReconciliationPaused]) {
+					paused = append(paused, name)
+				}
+			}
+			require.Equal(t, tc.paused, paused)
+			require.Equal(t, tc.paused, passed)
+		})
+	}
+
+	r := &Reconciler{ExcludeNames: []string{"[a-"}}
+	require.NotNil(t, r.validateNames())
+}
@@ -0,0 +1,159 @@
+package crossplanepause
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// normalize returns a copy of v with all the nil values and empty maps and slices removed from maps recursively,
+// so nil, empty and absent collections are treated as equal.
+// Elements of slices are normalized but kept, since the position of them matters.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			value = normalize(value)
+			if isEmpty(value) {
+				continue
+			}
+			res[key] = value
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			res = append(res, normalize(value))
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// CrossplaneSpecDefaults the defaults of the spec fields stamped by crossplane-runtime on every managed resource,
+// they're added to the SpecDefaults unless DisableCrossplaneSpecDefaults is set.
+var CrossplaneSpecDefaults = map[string]interface{}{
+	"deletionPolicy":     "Delete",
+	"managementPolicy":   "FullControl",
+	"managementPolicies": []interface{}{"*"},
+	"providerConfigRef":  map[string]interface{}{"name": "default"},
+}
+
+// specDefaults returns the SpecDefaults in effect, the CrossplaneSpecDefaults are included unless
+// DisableCrossplaneSpecDefaults is set, the SpecDefaults take precedence over them.
+func (r *Reconciler) specDefaults() map[string]interface{} {
+	if r.DisableCrossplaneSpecDefaults {
+		return r.SpecDefaults
+	}
+
+	defaults := make(map[string]interface{}, len(CrossplaneSpecDefaults)+len(r.SpecDefaults))
+	for path, def := range CrossplaneSpecDefaults {
+		defaults[path] = def
+	}
+	for path, def := range r.SpecDefaults {
+		defaults[path] = def
+	}
+	return defaults
+}
+
+// removeSpecDefaults removes the fields of spec in obj which have the default value or the zero value.
+// The defaults are keyed by the dot separated path relative to spec.
+func removeSpecDefaults(obj *unstructured.Unstructured, defaults map[string]interface{}) {
+	for path, def := range defaults {
+		fields := append([]string{"spec"}, strings.Split(path, ".")...)
+		v, ok, err := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+		if err != nil || !ok {
+			continue
+		}
+
+		if v == nil || reflect.ValueOf(v).IsZero() || reflect.DeepEqual(v, def) {
+			unstructured.RemoveNestedField(obj.Object, fields...)
+		}
+	}
+}
+
+// sortSetLikeLists sorts the lists of spec in obj at the paths into the canonical order, so the lists which are
+// semantically sets are treated as equal regardless of the order. The paths are dot separated relative to spec.
+func sortSetLikeLists(obj *unstructured.Unstructured, paths []string) {
+	for _, path := range paths {
+		fields := append([]string{"spec"}, strings.Split(path, ".")...)
+		v, ok, err := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+		if err != nil || !ok {
+			continue
+		}
+		list, ok := v.([]interface{})
+		if !ok {
+			continue
+		}
+
+		keys := make([]string, len(list))
+		for i := range list {
+			// The keys of the maps are sorted once marshaled.
+			data, _ := json.Marshal(normalize(list[i]))
+			keys[i] = string(data)
+		}
+		sorted := make([]interface{}, len(list))
+		copy(sorted, list)
+		sort.Sort(&byKeys{values: sorted, keys: keys})
+		_ = unstructured.SetNestedField(obj.Object, sorted, fields...)
+	}
+}
+
+// byKeys sorts the values by the keys of them.
+type byKeys struct {
+	values []interface{}
+	keys   []string
+}
+
+func (b *byKeys) Len() int           { return len(b.values) }
+func (b *byKeys) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b *byKeys) Swap(i, j int) {
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
+
+// normalizeJSONAnnotations rewrites the JSON values of the annotations keys in obj into the canonical form,
+// so the values differ only in key order or whitespace are treated as equal. The invalid JSON values are kept as is.
+func normalizeJSONAnnotations(obj *unstructured.Unstructured, keys []string) {
+	ann := obj.GetAnnotations()
+	changed := false
+	for _, key := range keys {
+		v, ok := ann[key]
+		if !ok {
+			continue
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(v), &value); err != nil {
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		ann[key] = string(data)
+		changed = true
+	}
+
+	if changed {
+		obj.SetAnnotations(ann)
+	}
+}
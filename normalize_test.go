@@ -0,0 +1,229 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNormalize(t *testing.T) {
+	v := map[string]interface{}{
+		"a": nil,
+		"b": map[string]interface{}{},
+		"c": []interface{}{},
+		"d": map[string]interface{}{
+			"e": map[string]interface{}{},
+			"f": "",
+		},
+		"g": []interface{}{map[string]interface{}{"h": nil}, "i"},
+		"j": map[string]interface{}{
+			"k": map[string]interface{}{"l": []interface{}{}},
+		},
+	}
+	expected := map[string]interface{}{
+		"d": map[string]interface{}{
+			"f": "",
+		},
+		"g": []interface{}{map[string]interface{}{}, "i"},
+	}
+	require.Equal(t, expected, normalize(v))
+}
+
+func TestIsUpdatedNormalize(t *testing.T) {
+	ctx := context.Background()
+	r := &Reconciler{}
+
+	newObj := func(forProvider map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"forProvider": forProvider,
+			},
+		}}
+		u.SetGroupVersionKind(testGVK)
+		u.SetName("thing")
+		return u
+	}
+
+	// tags: {} vs absent
+	old := newObj(map[string]interface{}{"cidrBlock": "a"})
+	now := newObj(map[string]interface{}{"cidrBlock": "a", "tags": map[string]interface{}{}})
+	updated, err := r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.False(t, updated)
+
+	// tags: null vs absent
+	now = newObj(map[string]interface{}{"cidrBlock": "a", "tags": nil})
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.False(t, updated)
+
+	// tags: {} vs {"a": "b"}
+	old = newObj(map[string]interface{}{"cidrBlock": "a", "tags": map[string]interface{}{"a": "b"}})
+	now = newObj(map[string]interface{}{"cidrBlock": "a", "tags": map[string]interface{}{}})
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.True(t, updated)
+
+	// enum defaulted from empty
+	old = newObj(map[string]interface{}{"cidrBlock": "a", "tenancy": ""})
+	now = newObj(map[string]interface{}{"cidrBlock": "a", "tenancy": "default"})
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.True(t, updated)
+	r.SpecDefaults = map[string]interface{}{"forProvider.tenancy": "default"}
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.False(t, updated)
+
+	// enum defaulted from absent
+	old = newObj(map[string]interface{}{"cidrBlock": "a"})
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.False(t, updated)
+
+	// enum changed to a non-default value
+	now = newObj(map[string]interface{}{"cidrBlock": "a", "tenancy": "dedicated"})
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.True(t, updated)
+}
+
+func TestIsUpdatedCrossplaneSpecDefaults(t *testing.T) {
+	ctx := context.Background()
+	r := &Reconciler{}
+
+	old := newThing(t, "thing")
+	err := unstructured.SetNestedField(old.Object, "a", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		path    []string
+		value   interface{}
+		updated bool
+	}{
+		{name: "deletionPolicy defaulted", path: []string{"spec", "deletionPolicy"}, value: "Delete"},
+		{name: "deletionPolicy changed", path: []string{"spec", "deletionPolicy"}, value: "Orphan", updated: true},
+		{name: "managementPolicy defaulted", path: []string{"spec", "managementPolicy"}, value: "FullControl"},
+		{name: "managementPolicies defaulted", path: []string{"spec", "managementPolicies"}, value: []interface{}{"*"}},
+		{name: "managementPolicies changed", path: []string{"spec", "managementPolicies"}, value: []interface{}{"Observe"}, updated: true},
+		{name: "providerConfigRef defaulted", path: []string{"spec", "providerConfigRef"}, value: map[string]interface{}{"name": "default"}},
+		{name: "providerConfigRef changed", path: []string{"spec", "providerConfigRef"}, value: map[string]interface{}{"name": "other"}, updated: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			now := old.DeepCopy()
+			err := unstructured.SetNestedField(now.Object, tc.value, tc.path...)
+			require.Nil(t, err)
+
+			r.DisableCrossplaneSpecDefaults = false
+			updated, err := r.isUpdated(ctx, old, now)
+			require.Nil(t, err)
+			require.Equal(t, tc.updated, updated)
+
+			r.DisableCrossplaneSpecDefaults = true
+			updated, err = r.isUpdated(ctx, old, now)
+			require.Nil(t, err)
+			require.True(t, updated)
+		})
+	}
+
+	// the SpecDefaults take precedence.
+	r = &Reconciler{SpecDefaults: map[string]interface{}{"deletionPolicy": "Orphan"}}
+	require.Equal(t, "Orphan", r.specDefaults()["deletionPolicy"])
+	require.Equal(t, CrossplaneSpecDefaults["providerConfigRef"], r.specDefaults()["providerConfigRef"])
+}
+
+func TestIsUpdatedJSONAnnotations(t *testing.T) {
+	ctx := context.Background()
+	key := "kubectl.kubernetes.io/last-applied-configuration"
+	r := &Reconciler{JSONAnnotationKeys: []string{key}}
+
+	newObj := func(value string) *unstructured.Unstructured {
+		u := newThing(t, "thing")
+		u.SetAnnotations(map[string]string{key: value})
+		return u
+	}
+
+	old := newObj(`{"spec":{"cidrBlock":"a","tags":{"a":"b"}}}`)
+
+	// reformatted
+	updated, err := r.isUpdated(ctx, old, newObj(`{ "spec": { "tags": { "a": "b" }, "cidrBlock": "a" } }`))
+	require.Nil(t, err)
+	require.False(t, updated)
+	updated, err = (&Reconciler{}).isUpdated(ctx, old, newObj(`{ "spec": { "tags": { "a": "b" }, "cidrBlock": "a" } }`))
+	require.Nil(t, err)
+	require.True(t, updated)
+
+	// changed
+	updated, err = r.isUpdated(ctx, old, newObj(`{"spec":{"cidrBlock":"b","tags":{"a":"b"}}}`))
+	require.Nil(t, err)
+	require.True(t, updated)
+
+	// invalid JSON is compared as is
+	updated, err = r.isUpdated(ctx, newObj(`{invalid`), newObj(`{invalid`))
+	require.Nil(t, err)
+	require.False(t, updated)
+	updated, err = r.isUpdated(ctx, old, newObj(`{invalid`))
+	require.Nil(t, err)
+	require.True(t, updated)
+}
+
+func TestIsUpdatedSetLikeSpecPaths(t *testing.T) {
+	ctx := context.Background()
+	r := &Reconciler{SetLikeSpecPaths: []string{"forProvider.securityGroupRefs", "forProvider.tags"}}
+
+	newObj := func(refs []interface{}, tags []interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"forProvider": map[string]interface{}{
+					"securityGroupRefs": refs,
+					"tags":              tags,
+					"cidrBlocks":        []interface{}{"a", "b"},
+				},
+			},
+		}}
+		u.SetGroupVersionKind(testGVK)
+		u.SetName("thing")
+		return u
+	}
+	ref := func(name string) interface{} {
+		return map[string]interface{}{"name": name}
+	}
+	tag := func(key, value string) interface{} {
+		return map[string]interface{}{"key": key, "value": value}
+	}
+
+	old := newObj([]interface{}{ref("a"), ref("b")}, []interface{}{tag("k1", "v1"), tag("k2", "v2")})
+
+	// reordered is not updated.
+	now := newObj([]interface{}{ref("b"), ref("a")}, []interface{}{tag("k2", "v2"), tag("k1", "v1")})
+	updated, err := r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.False(t, updated)
+
+	// the object itself is not modified.
+	refs, _, _ := unstructured.NestedSlice(now.Object, "spec", "forProvider", "securityGroupRefs")
+	require.Equal(t, []interface{}{ref("b"), ref("a")}, refs)
+
+	// an element added is updated.
+	now = newObj([]interface{}{ref("b"), ref("a"), ref("c")}, []interface{}{tag("k2", "v2"), tag("k1", "v1")})
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.True(t, updated)
+
+	// an element changed is updated.
+	now = newObj([]interface{}{ref("b"), ref("a")}, []interface{}{tag("k2", "v2"), tag("k1", "v3")})
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.True(t, updated)
+
+	// the order of the other lists still matters.
+	now = newObj([]interface{}{ref("a"), ref("b")}, []interface{}{tag("k1", "v1"), tag("k2", "v2")})
+	err = unstructured.SetNestedStringSlice(now.Object, []string{"b", "a"}, "spec", "forProvider", "cidrBlocks")
+	require.Nil(t, err)
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.True(t, updated)
+}
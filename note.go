@@ -0,0 +1,28 @@
+package crossplanepause
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// AnnotationKeyPauseNote is the annotation key for operators to leave a note like "paused for incident X" on a resource,
+// the note is recorded into the pause info, the history and the events on the next pause or unpause. Changing it
+// never unpauses the resource.
+const AnnotationKeyPauseNote = "cloud.pingcap.com/pause-note"
+
+// EventReasonPaused the reason of the event emitted once we pause a resource with a note.
+const EventReasonPaused = "Paused"
+
+// pauseNote returns the note of obj in the AnnotationKeyPauseNote annotation, or empty if there is none.
+func pauseNote(obj *unstructured.Unstructured) string {
+	return strings.TrimSpace(obj.GetAnnotations()[AnnotationKeyPauseNote])
+}
+
+// withNote appends the note to msg if it's not empty.
+func withNote(msg, note string) string {
+	if note == "" {
+		return msg
+	}
+	return msg + ", note: " + note
+}
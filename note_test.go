@@ -0,0 +1,70 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+)
+
+func TestPauseNote(t *testing.T) {
+	h := newHarness(t)
+	recorder := record.NewFakeRecorder(10)
+	h.r.Recorder = recorder
+	h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+	h.r.UnPausePollJitter = pointer.Float64(0)
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	setNote := func(note string) {
+		h.mutate(func(thing *unstructured.Unstructured) {
+			ann := thing.GetAnnotations()
+			if ann == nil {
+				ann = make(map[string]string)
+			}
+			ann[AnnotationKeyPauseNote] = note
+			thing.SetAnnotations(ann)
+		})
+	}
+
+	// no event for the pause without a note.
+	h.reconcile()
+	paused, info := h.state()
+	require.True(t, paused)
+	require.Empty(t, info.Note)
+	require.Empty(t, recorder.Events)
+
+	// the note is not an update.
+	setNote("investigating incident X")
+	h.reconcile()
+	paused, info = h.state()
+	require.True(t, paused)
+	require.Empty(t, info.Note)
+
+	// recorded on the next transition.
+	h.advance(time.Hour)
+	h.reconcile()
+	paused, info = h.state()
+	require.False(t, paused)
+	require.Equal(t, "investigating incident X", info.Note)
+	require.Equal(t, "investigating incident X", info.History[len(info.History)-1].Note)
+	require.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	require.Contains(t, event, UnpauseReasonPollInterval.EventReason())
+	require.Contains(t, event, "note: investigating incident X")
+
+	// survives the cycle.
+	h.advance(DefaultFrozenTimeDuration)
+	h.reconcile()
+	paused, info = h.state()
+	require.True(t, paused)
+	require.Equal(t, "investigating incident X", info.Note)
+	require.Len(t, recorder.Events, 1)
+	event = <-recorder.Events
+	require.Contains(t, event, EventReasonPaused)
+	require.Contains(t, event, "note: investigating incident X")
+}
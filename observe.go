@@ -0,0 +1,120 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Mode the operating mode of the reconciler.
+type Mode string
+
+// The Modes.
+const (
+	// ModeEnforce pauses and unpauses the resources, it's the default.
+	ModeEnforce Mode = "Enforce"
+	// ModeObserve evaluates the decisions and reports the resources which would be paused by the metrics, the events and
+	// optionally the AnnotationKeyWouldPause annotation, but never pauses them. The resources paused before are still
+	// unpaused as usual.
+	ModeObserve Mode = "Observe"
+)
+
+// AnnotationKeyWouldPause is the annotation key to mark the resource which would be paused in ModeObserve if
+// RecordWouldPause is set, the value is the reason. It's removed once the resource is not a candidate anymore.
+const AnnotationKeyWouldPause = "cloud.pingcap.com/would-pause"
+
+// EventReasonWouldPause the reason of the event emitted once a resource becomes a candidate to pause in ModeObserve.
+const EventReasonWouldPause = "WouldPause"
+
+func (r *Reconciler) mode() Mode {
+	if r.Mode == "" {
+		return ModeEnforce
+	}
+
+	return r.Mode
+}
+
+// observing returns true if the reconciler runs in ModeObserve.
+func (r *Reconciler) observing() bool {
+	return r.mode() == ModeObserve
+}
+
+// observePause records obj would be paused for the reason instead of pausing it.
+func (r *Reconciler) observePause(ctx context.Context, obj *unstructured.Unstructured, reason string) error {
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	if r.metrics().add(wouldPauseResources, key) {
+		r.event(obj, corev1.EventTypeNormal, EventReasonWouldPause, "Would pause resource: %s", reason)
+	}
+
+	if !r.RecordWouldPause || obj.GetAnnotations()[AnnotationKeyWouldPause] == reason {
+		return nil
+	}
+
+	err := r.update(ctx, obj, func(obj *unstructured.Unstructured, _ bool) (bool, error) {
+		ann := obj.GetAnnotations()
+		if ann == nil {
+			ann = make(map[string]string)
+		}
+		ann[AnnotationKeyWouldPause] = reason
+		obj.SetAnnotations(ann)
+		return true, nil
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to update object: %w", err)
+	}
+	return nil
+}
+
+// forgetWouldPause forgets the resource of key is a candidate to pause, and removes its AnnotationKeyWouldPause
+// annotation if RecordWouldPause is set.
+func (r *Reconciler) forgetWouldPause(ctx context.Context, key types.NamespacedName) error {
+	r.metrics().remove(wouldPauseResources, key)
+
+	if !r.RecordWouldPause {
+		return nil
+	}
+
+	obj := new(unstructured.Unstructured)
+	obj.SetGroupVersionKind(r.GroupVersionKind)
+	err := r.Client.Get(ctx, key, obj)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get object %s: %w", key, err)
+	}
+	if _, ok := obj.GetAnnotations()[AnnotationKeyWouldPause]; !ok {
+		return nil
+	}
+
+	err = r.update(ctx, obj, func(obj *unstructured.Unstructured, _ bool) (bool, error) {
+		ann := obj.GetAnnotations()
+		if _, ok := ann[AnnotationKeyWouldPause]; !ok {
+			return false, nil
+		}
+		delete(ann, AnnotationKeyWouldPause)
+		obj.SetAnnotations(ann)
+		return true, nil
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to update object: %w", err)
+	}
+	return nil
+}
+
+// observeDecision keeps the candidates to pause in ModeObserve up to date with the decision d of the resource of key.
+func (r *Reconciler) observeDecision(ctx context.Context, key types.NamespacedName, d decision) {
+	if d.action == ActionWouldPause {
+		return
+	}
+
+	err := r.forgetWouldPause(ctx, key)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "unable to forget the candidate to pause")
+	}
+}
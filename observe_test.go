@@ -0,0 +1,68 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestModeObserve(t *testing.T) {
+	h := newHarness(t)
+	recorder := record.NewFakeRecorder(10)
+	h.r.Recorder = recorder
+	h.r.Mode = ModeObserve
+	h.r.RecordWouldPause = true
+	ctx := context.Background()
+	gauge := wouldPauseResources.plain.WithLabelValues(testGVK.String())
+
+	err := h.cli.Create(ctx, newThing(t, "thing"))
+	require.Nil(t, err)
+	notReady := newThing(t, "not-ready")
+	setConditions(t, notReady, xpv1.Creating(), xpv1.ReconcileSuccess())
+	err = h.cli.Create(ctx, notReady)
+	require.Nil(t, err)
+
+	var actions []Action
+	h.r.OnReconcile = func(req ctrl.Request, action Action, res ctrl.Result, err error) {
+		actions = append(actions, action)
+	}
+	for i := 0; i < 2; i++ {
+		h.reconcile()
+		_, err := h.r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "not-ready"}})
+		require.Nil(t, err)
+	}
+	require.Equal(t, []Action{ActionWouldPause, ActionKeepUnpaused, ActionWouldPause, ActionKeepUnpaused}, actions)
+
+	// reported but not paused.
+	thing := getThing(t, h.cli, "thing")
+	require.Empty(t, thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+	require.Equal(t, "Ready=Available, Synced=ReconcileSuccess", thing.GetAnnotations()[AnnotationKeyWouldPause])
+	require.Empty(t, getThing(t, h.cli, "not-ready").GetAnnotations())
+	require.Equal(t, 1.0, testutil.ToFloat64(gauge))
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, EventReasonWouldPause)
+
+	// not a candidate anymore.
+	h.mutate(func(thing *unstructured.Unstructured) {
+		setConditions(t, thing, xpv1.Unavailable(), xpv1.ReconcileSuccess())
+	})
+	h.reconcile()
+	require.NotContains(t, getThing(t, h.cli, "thing").GetAnnotations(), AnnotationKeyWouldPause)
+	require.Equal(t, 0.0, testutil.ToFloat64(gauge))
+
+	// enforced.
+	h.mutate(func(thing *unstructured.Unstructured) {
+		setConditions(t, thing, xpv1.Available(), xpv1.ReconcileSuccess())
+	})
+	h.r.Mode = ModeEnforce
+	h.reconcile()
+	paused, _ := h.state()
+	require.True(t, paused)
+}
@@ -0,0 +1,296 @@
+package crossplanepause
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AnnotationKeyOwnedPauseInfo is the annotation key of the owner to store the pause info of the resources it controls
+// if PauseInfoOnOwner is set. The value is a JSON object of the pause info keyed by ownedPauseInfoKey.
+const AnnotationKeyOwnedPauseInfo = "cloud.pingcap.com/owned-pause-info"
+
+// kube returns the client to read and write the resources, it moves the pause info to the owner if PauseInfoOnOwner is set.
+func (r *Reconciler) kube() client.Client {
+	if !r.PauseInfoOnOwner {
+		return r.Client
+	}
+
+	return &ownerInfoClient{Client: r.Client, r: r}
+}
+
+// ownerInfoClient stores the pause info of the paused resources of the GroupVersionKind in the AnnotationKeyOwnedPauseInfo
+// annotation of their controller owner. The pause info is moved into the annotation of the resource once read,
+// and moved back to the owner after the resource is written, so the rest of the reconciler is not aware of it.
+type ownerInfoClient struct {
+	client.Client
+	r *Reconciler
+}
+
+func (c *ownerInfoClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	err := c.Client.Get(ctx, key, obj, opts...)
+	if err != nil {
+		return err
+	}
+
+	if u, ok := obj.(*unstructured.Unstructured); ok && u.GroupVersionKind() == c.r.GroupVersionKind {
+		return c.load(ctx, u)
+	}
+	return nil
+}
+
+func (c *ownerInfoClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	err := c.Client.List(ctx, list, opts...)
+	if err != nil {
+		return err
+	}
+
+	if u, ok := list.(*unstructured.UnstructuredList); ok {
+		for i := range u.Items {
+			if u.Items[i].GroupVersionKind() != c.r.GroupVersionKind {
+				continue
+			}
+			err := c.load(ctx, &u.Items[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *ownerInfoClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok || u.GroupVersionKind() != c.r.GroupVersionKind {
+		return c.Client.Update(ctx, obj, opts...)
+	}
+
+	key := c.r.pauseInfoAnnotationKey()
+	info, err := pauseInfoFromAnnotations(u.GetAnnotations(), key)
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		return c.Client.Update(ctx, obj, opts...)
+	}
+
+	owner, err := c.owner(ctx, u)
+	if err != nil {
+		return err
+	}
+	if owner == nil {
+		return c.Client.Update(ctx, obj, opts...)
+	}
+
+	// The resource is written before the owner, so the owner never holds the pause info of a write which failed.
+	// The pause info of the unpaused resource stays on itself and is pruned from the owner.
+	if !info.Pause {
+		err := c.Client.Update(ctx, obj, opts...)
+		if err != nil {
+			return err
+		}
+		return c.store(ctx, u, "")
+	}
+
+	ann := u.GetAnnotations()
+	data := ann[key]
+	delete(ann, key)
+	u.SetAnnotations(ann)
+	err = c.Client.Update(ctx, obj, opts...)
+
+	// Keep it in memory as if it's read back.
+	ann = u.GetAnnotations()
+	if ann == nil {
+		ann = make(map[string]string)
+	}
+	ann[key] = data
+	u.SetAnnotations(ann)
+	if err != nil {
+		return err
+	}
+	return c.store(ctx, u, data)
+}
+
+// owner returns the controller owner of obj, or nil if there is none or it's gone.
+func (c *ownerInfoClient) owner(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	ref := metav1.GetControllerOf(obj)
+	if ref == nil {
+		return nil, nil
+	}
+
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner reference %s: %w", ref.Name, err)
+	}
+
+	owner := new(unstructured.Unstructured)
+	owner.SetGroupVersionKind(gv.WithKind(ref.Kind))
+	err = c.Client.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: ref.Name}, owner)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get owner %s %s: %w", owner.GroupVersionKind(), ref.Name, err)
+	}
+
+	return owner, nil
+}
+
+// load moves the pause info of obj from its owner into its annotation, the one of obj itself wins since it's written
+// after the owner, e.g. it's unpaused, or it's paused before PauseInfoOnOwner is set.
+func (c *ownerInfoClient) load(ctx context.Context, obj *unstructured.Unstructured) error {
+	info, err := c.r.parsePauseInfo(obj)
+	if err != nil || info != nil {
+		return err
+	}
+
+	owner, err := c.owner(ctx, obj)
+	if err != nil || owner == nil {
+		return err
+	}
+
+	infos, err := ownedPauseInfos(owner)
+	if err != nil {
+		return err
+	}
+
+	data, ok := infos[ownedPauseInfoKey(obj)]
+	if !ok {
+		return nil
+	}
+
+	ann := obj.GetAnnotations()
+	if ann == nil {
+		ann = make(map[string]string)
+	}
+	ann[c.r.pauseInfoAnnotationKey()] = string(data)
+	obj.SetAnnotations(ann)
+	return nil
+}
+
+// store stores the pause info data of obj to its owner, or removes it if data is empty. The entries of the resources
+// which are gone are pruned along, or the annotation keeps growing.
+func (c *ownerInfoClient) store(ctx context.Context, obj *unstructured.Unstructured, data string) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		owner, err := c.owner(ctx, obj)
+		if err != nil || owner == nil {
+			return err
+		}
+
+		infos, err := ownedPauseInfos(owner)
+		if err != nil {
+			return err
+		}
+
+		changed, err := c.pruneGone(ctx, obj, infos)
+		if err != nil {
+			return err
+		}
+		key := ownedPauseInfoKey(obj)
+		if data == "" {
+			if _, ok := infos[key]; ok {
+				delete(infos, key)
+				changed = true
+			}
+		} else if !bytes.Equal(infos[key], []byte(data)) {
+			infos[key] = json.RawMessage(data)
+			changed = true
+		}
+		if !changed {
+			return nil
+		}
+
+		ann := owner.GetAnnotations()
+		if ann == nil {
+			ann = make(map[string]string)
+		}
+		if len(infos) == 0 {
+			delete(ann, AnnotationKeyOwnedPauseInfo)
+		} else {
+			v, err := json.Marshal(infos)
+			if err != nil {
+				return fmt.Errorf("unable to marshal owned pause info: %w", err)
+			}
+			ann[AnnotationKeyOwnedPauseInfo] = string(v)
+		}
+		owner.SetAnnotations(ann)
+		return c.Client.Update(ctx, owner)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to store pause info to owner: %w", err)
+	}
+
+	return nil
+}
+
+// pruneGone removes the pause info of the resources of the GroupVersionKind other than obj which are gone from infos,
+// it returns true if any is removed.
+func (c *ownerInfoClient) pruneGone(ctx context.Context, obj *unstructured.Unstructured, infos map[string]json.RawMessage) (bool, error) {
+	prefix := obj.GroupVersionKind().GroupKind().String() + "/"
+	pruned := false
+	for key := range infos {
+		if !strings.HasPrefix(key, prefix) || key == ownedPauseInfoKey(obj) {
+			continue
+		}
+
+		var name types.NamespacedName
+		parts := strings.Split(strings.TrimPrefix(key, prefix), "/")
+		switch len(parts) {
+		case 1:
+			name.Name = parts[0]
+		case 2:
+			name.Namespace, name.Name = parts[0], parts[1]
+		default:
+			continue
+		}
+
+		sibling := new(unstructured.Unstructured)
+		sibling.SetGroupVersionKind(c.r.GroupVersionKind)
+		err := c.Client.Get(ctx, name, sibling)
+		if apierrors.IsNotFound(err) {
+			delete(infos, key)
+			pruned = true
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("unable to get %s: %w", name, err)
+		}
+	}
+
+	return pruned, nil
+}
+
+// ownedPauseInfoKey returns the key of the pause info of obj in the AnnotationKeyOwnedPauseInfo annotation,
+// like "Subnet.ec2.aws.crossplane.io/name", the namespace is in between if it's namespaced.
+func ownedPauseInfoKey(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GroupVersionKind().GroupKind().String() + "/" + obj.GetName()
+	}
+	return obj.GroupVersionKind().GroupKind().String() + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// ownedPauseInfos returns the pause info stored in the AnnotationKeyOwnedPauseInfo annotation of owner.
+func ownedPauseInfos(owner *unstructured.Unstructured) (map[string]json.RawMessage, error) {
+	infos := make(map[string]json.RawMessage)
+	v, ok := owner.GetAnnotations()[AnnotationKeyOwnedPauseInfo]
+	if !ok {
+		return infos, nil
+	}
+
+	err := json.Unmarshal([]byte(v), &infos)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal annotation %s of owner %s: %w", AnnotationKeyOwnedPauseInfo, owner.GetName(), err)
+	}
+
+	return infos, nil
+}
@@ -0,0 +1,155 @@
+package crossplanepause
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPauseInfoOnOwner(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	owner := new(unstructured.Unstructured)
+	owner.SetGroupVersionKind(testCompositeGVK)
+	owner.SetName("owner")
+	owner.SetUID(types.UID("owner-uid"))
+	err := cli.Create(ctx, owner)
+	require.Nil(t, err)
+
+	thing := newThing(t, "thing")
+	thing.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: testCompositeGVK.GroupVersion().String(),
+		Kind:       testCompositeGVK.Kind,
+		Name:       "owner",
+		UID:        owner.GetUID(),
+		Controller: pointer.Bool(true),
+	}})
+	err = cli.Create(ctx, thing)
+	require.Nil(t, err)
+	err = cli.Create(ctx, newThing(t, "orphan"))
+	require.Nil(t, err)
+
+	r := newThingReconciler(cli)
+	r.PauseInfoOnOwner = true
+	r.FrozenTimeDuration = pointer.Duration(0)
+
+	ownedInfo := func(t *testing.T) *PauseInfo {
+		t.Helper()
+		owner := new(unstructured.Unstructured)
+		owner.SetGroupVersionKind(testCompositeGVK)
+		err := cli.Get(ctx, client.ObjectKey{Name: "owner"}, owner)
+		require.Nil(t, err)
+		infos, err := ownedPauseInfos(owner)
+		require.Nil(t, err)
+		data, ok := infos["Thing.test.crossplane.io/thing"]
+		require.True(t, ok)
+		info := new(PauseInfo)
+		err = json.Unmarshal(data, info)
+		require.Nil(t, err)
+		return info
+	}
+
+	for _, name := range []string{"thing", "orphan"} {
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: name}})
+		require.Nil(t, err)
+	}
+
+	// the paused annotation lands on the resource while the pause info lands on the owner.
+	thing = getThing(t, cli, "thing")
+	require.True(t, isPaused(thing.GetAnnotations()[AnnotationKeyReconciliationPaused]))
+	require.NotContains(t, thing.GetAnnotations(), AnnotationKeyPauseInfo)
+	require.True(t, ownedInfo(t).Pause)
+
+	// the resource without the owner keeps the pause info itself.
+	orphan := getThing(t, cli, "orphan")
+	require.True(t, isPaused(orphan.GetAnnotations()[AnnotationKeyReconciliationPaused]))
+	require.Contains(t, orphan.GetAnnotations(), AnnotationKeyPauseInfo)
+
+	// unpause it once updated, the snapshot stored on the owner is compared.
+	err = unstructured.SetNestedField(thing.Object, "b", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}})
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	require.False(t, isPaused(thing.GetAnnotations()[AnnotationKeyReconciliationPaused]))
+
+	// the pause info of the unpaused one moves back to itself and is pruned from the owner.
+	info, err := r.parsePauseInfo(thing)
+	require.Nil(t, err)
+	require.False(t, info.Pause)
+	require.NotNil(t, info.LastUnPauseTime)
+	owner = new(unstructured.Unstructured)
+	owner.SetGroupVersionKind(testCompositeGVK)
+	err = cli.Get(ctx, client.ObjectKey{Name: "owner"}, owner)
+	require.Nil(t, err)
+	require.NotContains(t, owner.GetAnnotations(), AnnotationKeyOwnedPauseInfo)
+}
+
+func TestPauseInfoOnOwnerConsistency(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	owner := new(unstructured.Unstructured)
+	owner.SetGroupVersionKind(testCompositeGVK)
+	owner.SetName("owner")
+	owner.SetUID(types.UID("owner-uid"))
+	err := cli.Create(ctx, owner)
+	require.Nil(t, err)
+
+	for _, name := range []string{"gone", "broken", "thing"} {
+		thing := newThing(t, name)
+		thing.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: testCompositeGVK.GroupVersion().String(),
+			Kind:       testCompositeGVK.Kind,
+			Name:       "owner",
+			UID:        owner.GetUID(),
+			Controller: pointer.Bool(true),
+		}})
+		err = cli.Create(ctx, thing)
+		require.Nil(t, err)
+	}
+
+	r := newThingReconciler(&nameUpdateErrorClient{Client: cli, name: "broken", err: errors.New("boom")})
+	r.PauseInfoOnOwner = true
+	owned := func(t *testing.T) map[string]json.RawMessage {
+		t.Helper()
+		owner := new(unstructured.Unstructured)
+		owner.SetGroupVersionKind(testCompositeGVK)
+		err := cli.Get(ctx, client.ObjectKey{Name: "owner"}, owner)
+		require.Nil(t, err)
+		infos, err := ownedPauseInfos(owner)
+		require.Nil(t, err)
+		return infos
+	}
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "gone"}})
+	require.Nil(t, err)
+	require.Contains(t, owned(t), "Thing.test.crossplane.io/gone")
+
+	// the owner is untouched if the resource fails to be written.
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "broken"}})
+	require.NotNil(t, err)
+	require.NotContains(t, owned(t), "Thing.test.crossplane.io/broken")
+
+	// the entry of the gone one is pruned once the owner is written.
+	err = cli.Delete(ctx, getThing(t, cli, "gone"))
+	require.Nil(t, err)
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}})
+	require.Nil(t, err)
+	infos := owned(t)
+	require.Contains(t, infos, "Thing.test.crossplane.io/thing")
+	require.NotContains(t, infos, "Thing.test.crossplane.io/gone")
+}
@@ -0,0 +1,65 @@
+package crossplanepause
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// PauseInfoFromObject returns the pause info of obj in the AnnotationKeyPauseInfo annotation, or nil if we never
+// paused it. Use it to read the pause state of the resources paused by a reconciler with the default PauseInfoAnnotationKey.
+func PauseInfoFromObject(obj *unstructured.Unstructured) (*PauseInfo, error) {
+	return pauseInfoFromAnnotations(obj.GetAnnotations(), AnnotationKeyPauseInfo)
+}
+
+// pauseInfoFromAnnotations parses the pause info from the first of the keys existing in ann, or returns nil if none of them exists.
+func pauseInfoFromAnnotations(ann map[string]string, keys ...string) (*PauseInfo, error) {
+	for _, key := range keys {
+		v, ok := ann[key]
+		if !ok {
+			continue
+		}
+
+		info := new(PauseInfo)
+		err := json.Unmarshal([]byte(v), info)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal annotation %s: %w", key, err)
+		}
+
+		return info, nil
+	}
+
+	return nil, nil
+}
+
+// IsActive returns true if the resource is paused by us, it's safe to call on nil.
+func (i *PauseInfo) IsActive() bool {
+	return i != nil && i.Pause
+}
+
+// PausedAt returns the time we paused the resource last time, or the zero time if we never did.
+func (i *PauseInfo) PausedAt() time.Time {
+	if i == nil || i.LastPauseTime == nil {
+		return time.Time{}
+	}
+	return i.LastPauseTime.Time
+}
+
+// UnpausedAt returns the time we unpaused the resource last time, or the zero time if we never did.
+func (i *PauseInfo) UnpausedAt() time.Time {
+	if i == nil || i.LastUnPauseTime == nil {
+		return time.Time{}
+	}
+	return i.LastUnPauseTime.Time
+}
+
+// ShouldUnpauseAt returns the time the UnPausePollInterval is due for the active pause, or the zero time if it's not
+// scheduled. The pinning and the SoftUnpause are not considered, see Reconciler.NextUnpauseTime for them.
+func (i *PauseInfo) ShouldUnpauseAt() time.Time {
+	if i == nil || i.ShouldUnpauseTime == nil {
+		return time.Time{}
+	}
+	return i.ShouldUnpauseTime.Time
+}
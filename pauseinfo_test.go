@@ -0,0 +1,56 @@
+package crossplanepause
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPauseInfoFromObject(t *testing.T) {
+	// missing
+	thing := newThing(t, "thing")
+	info, err := PauseInfoFromObject(thing)
+	require.Nil(t, err)
+	require.Nil(t, info)
+	require.False(t, info.IsActive())
+	require.True(t, info.PausedAt().IsZero())
+	require.True(t, info.UnpausedAt().IsZero())
+	require.True(t, info.ShouldUnpauseAt().IsZero())
+
+	// valid
+	pausedAt := metav1.NewTime(time.Date(2022, 7, 22, 10, 54, 18, 0, time.UTC))
+	unpausedAt := metav1.NewTime(pausedAt.Add(-time.Hour))
+	shouldUnpauseAt := metav1.NewTime(pausedAt.Add(5 * time.Hour))
+	r := newThingReconciler(nil)
+	err = r.setPauseInfo(thing, &PauseInfo{
+		Pause:             true,
+		LastPauseTime:     &pausedAt,
+		LastUnPauseTime:   &unpausedAt,
+		ShouldUnpauseTime: &shouldUnpauseAt,
+	})
+	require.Nil(t, err)
+	info, err = PauseInfoFromObject(thing)
+	require.Nil(t, err)
+	require.True(t, info.IsActive())
+	require.True(t, pausedAt.Time.Equal(info.PausedAt()))
+	require.True(t, unpausedAt.Time.Equal(info.UnpausedAt()))
+	require.True(t, shouldUnpauseAt.Time.Equal(info.ShouldUnpauseAt()))
+
+	// unpaused
+	info.Pause = false
+	require.False(t, info.IsActive())
+
+	// malformed
+	thing.SetAnnotations(map[string]string{AnnotationKeyPauseInfo: "{"})
+	info, err = PauseInfoFromObject(thing)
+	require.NotNil(t, err)
+	require.Nil(t, info)
+
+	// only the default key is read.
+	thing.SetAnnotations(map[string]string{"example.com/pause-info": `{"pause":true}`})
+	info, err = PauseInfoFromObject(thing)
+	require.Nil(t, err)
+	require.Nil(t, info)
+}
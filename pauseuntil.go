@@ -0,0 +1,58 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AnnotationKeyPauseUntil is the annotation key for operators to set when the resource we pause is unpaused, the value
+// is a RFC3339 timestamp like "2022-07-22T18:00:00Z". It takes effect on the next pause, overrides the
+// UnPausePollInterval, and is removed once we unpause the resource for any reason, so set it on an unpaused resource.
+// Changing it never unpauses the resource. The one passed already is removed without pausing the resource, which is
+// paused by the UnPausePollInterval as usual then.
+const AnnotationKeyPauseUntil = "cloud.pingcap.com/pause-until"
+
+// EventReasonInvalidPauseUntil the reason of the warning event emitted once the AnnotationKeyPauseUntil annotation is malformed.
+const EventReasonInvalidPauseUntil = "InvalidPauseUntil"
+
+// parsePauseUntil returns the time in the AnnotationKeyPauseUntil annotation of obj, or nil if there is none.
+func parsePauseUntil(obj *unstructured.Unstructured) (*metav1.Time, error) {
+	v, ok := obj.GetAnnotations()[AnnotationKeyPauseUntil]
+	if !ok {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid annotation %s %q: %w", AnnotationKeyPauseUntil, v, err)
+	}
+	return &metav1.Time{Time: t}, nil
+}
+
+// clearPauseUntil removes the AnnotationKeyPauseUntil annotation of obj.
+func (r *Reconciler) clearPauseUntil(ctx context.Context, obj *unstructured.Unstructured) error {
+	err := r.update(ctx, obj, func(obj *unstructured.Unstructured, _ bool) (bool, error) {
+		ann := obj.GetAnnotations()
+		if _, ok := ann[AnnotationKeyPauseUntil]; !ok {
+			return false, nil
+		}
+		delete(ann, AnnotationKeyPauseUntil)
+		obj.SetAnnotations(ann)
+		return true, nil
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update object: %w", err)
+	}
+
+	log.FromContext(ctx).Info("remove the passed pause until")
+	return nil
+}
@@ -0,0 +1,129 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+)
+
+func TestPauseUntil(t *testing.T) {
+	h := newHarness(t)
+	h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+	h.r.UnPausePollJitter = pointer.Float64(0)
+	thing := newThing(t, "thing")
+	thing.SetAnnotations(map[string]string{AnnotationKeyPauseUntil: "2022-07-22T18:00:00Z"})
+	err := h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+
+	h.reconcile()
+	paused, info := h.state()
+	require.True(t, paused)
+	until := time.Date(2022, 7, 22, 18, 0, 0, 0, time.UTC)
+	require.True(t, info.PauseUntil.Time.Equal(until))
+	require.True(t, info.ShouldUnpauseTime.Time.Equal(until))
+	require.True(t, h.r.NextUnpauseTime(getThing(t, h.cli, "thing"), info).Equal(until))
+
+	// the UnPausePollInterval is overridden.
+	h.advance(2 * time.Hour)
+	result := h.reconcile()
+	require.Equal(t, until.Sub(h.clock.Now()), result.RequeueAfter)
+	paused, _ = h.state()
+	require.True(t, paused)
+
+	h.advance(result.RequeueAfter)
+	h.reconcile()
+	paused, info = h.state()
+	require.False(t, paused)
+	require.Nil(t, info.PauseUntil)
+	require.Equal(t, UnpauseReasonPauseUntil, info.History[len(info.History)-1].Reason)
+	require.NotContains(t, getThing(t, h.cli, "thing").GetAnnotations(), AnnotationKeyPauseUntil)
+}
+
+func TestPauseUntilPassed(t *testing.T) {
+	h := newHarness(t)
+	h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+	h.r.UnPausePollJitter = pointer.Float64(0)
+	thing := newThing(t, "thing")
+	thing.SetAnnotations(map[string]string{AnnotationKeyPauseUntil: "2022-07-22T09:00:00Z"})
+	err := h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+
+	// it's not paused only to be unpaused at once, the stale annotation is removed instead.
+	h.reconcile()
+	paused, info := h.state()
+	require.False(t, paused)
+	require.Nil(t, info)
+	require.NotContains(t, getThing(t, h.cli, "thing").GetAnnotations(), AnnotationKeyPauseUntil)
+
+	// then paused by the UnPausePollInterval as usual.
+	h.reconcile()
+	paused, info = h.state()
+	require.True(t, paused)
+	require.Nil(t, info.PauseUntil)
+	require.True(t, info.ShouldUnpauseTime.Time.Equal(h.clock.Now().Add(time.Hour)))
+}
+
+func TestPauseUntilClearedOnUnpause(t *testing.T) {
+	h := newHarness(t)
+	thing := newThing(t, "thing")
+	thing.SetAnnotations(map[string]string{AnnotationKeyPauseUntil: "2022-07-22T18:00:00Z"})
+	err := h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+
+	h.reconcile()
+	paused, _ := h.state()
+	require.True(t, paused)
+
+	// unpaused for another reason, the pause until doesn't outlive the pause it's taken by.
+	h.mutate(func(thing *unstructured.Unstructured) {
+		err := unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+		require.Nil(t, err)
+	})
+	h.reconcile()
+	paused, info := h.state()
+	require.False(t, paused)
+	require.Equal(t, UnpauseReasonUpdated, info.History[len(info.History)-1].Reason)
+	require.NotContains(t, getThing(t, h.cli, "thing").GetAnnotations(), AnnotationKeyPauseUntil)
+}
+
+func TestInvalidPauseUntil(t *testing.T) {
+	h := newHarness(t)
+	recorder := record.NewFakeRecorder(10)
+	h.r.Recorder = recorder
+	h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+	h.r.UnPausePollJitter = pointer.Float64(0)
+	thing := newThing(t, "thing")
+	thing.SetAnnotations(map[string]string{AnnotationKeyPauseUntil: "tomorrow 6pm"})
+	err := h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+
+	// paused by the UnPausePollInterval as usual.
+	h.reconcile()
+	paused, info := h.state()
+	require.True(t, paused)
+	require.Nil(t, info.PauseUntil)
+	require.True(t, info.ShouldUnpauseTime.Time.Equal(h.clock.Now().Add(time.Hour)))
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, EventReasonInvalidPauseUntil)
+
+	// changing it is not an update.
+	h.mutate(func(thing *unstructured.Unstructured) {
+		ann := thing.GetAnnotations()
+		ann[AnnotationKeyPauseUntil] = "2022-07-22T18:00:00Z"
+		thing.SetAnnotations(ann)
+	})
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+
+	h.advance(time.Hour)
+	h.reconcile()
+	paused, info = h.state()
+	require.False(t, paused)
+	require.Equal(t, UnpauseReasonPollInterval, info.History[len(info.History)-1].Reason)
+}
@@ -0,0 +1,40 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DefaultPrePauseRequeue the default Duration we requeue the resource after once the PrePauseValidate vetoes or fails.
+const DefaultPrePauseRequeue = time.Minute
+
+func (r *Reconciler) prePauseRequeue() time.Duration {
+	if r.PrePauseRequeue > 0 {
+		return r.PrePauseRequeue
+	}
+
+	return DefaultPrePauseRequeue
+}
+
+// prePauseValidate returns true if obj can be paused by the PrePauseValidate, the reason is set if it can't.
+// The error of the PrePauseValidate is logged and vetoes pausing.
+func (r *Reconciler) prePauseValidate(ctx context.Context, obj *unstructured.Unstructured) (bool, string) {
+	if r.PrePauseValidate == nil {
+		return true, ""
+	}
+
+	ok, reason, err := r.PrePauseValidate(ctx, obj)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "pre-pause validation failed")
+		return false, fmt.Sprintf("pre-pause validation failed: %s", err)
+	}
+	if !ok {
+		return false, fmt.Sprintf("vetoed by pre-pause validation: %s", reason)
+	}
+
+	return true, ""
+}
@@ -0,0 +1,65 @@
+package crossplanepause
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPrePauseValidate(t *testing.T) {
+	h := newHarness(t)
+	h.r.PrePauseRequeue = 2 * time.Minute
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	var calls int
+	var validateErr error
+	settled := false
+	h.r.PrePauseValidate = func(ctx context.Context, obj *unstructured.Unstructured) (bool, string, error) {
+		calls++
+		require.Equal(t, "thing", obj.GetName())
+		if validateErr != nil {
+			return false, "", validateErr
+		}
+		if !settled {
+			return false, "still modifying", nil
+		}
+		return true, "", nil
+	}
+
+	// vetoed.
+	result := h.reconcile()
+	require.Equal(t, 2*time.Minute, result.RequeueAfter)
+	paused, _ := h.state()
+	require.False(t, paused)
+	require.Equal(t, 1, calls)
+
+	// failed, it's requeued as well.
+	validateErr = errors.New("throttled")
+	result = h.reconcile()
+	require.Equal(t, 2*time.Minute, result.RequeueAfter)
+	paused, _ = h.state()
+	require.False(t, paused)
+	require.Equal(t, 2, calls)
+
+	// approved.
+	validateErr = nil
+	settled = true
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+	require.Equal(t, 3, calls)
+
+	// not called once paused.
+	h.reconcile()
+	require.Equal(t, 3, calls)
+}
+
+func TestPrePauseRequeueDefault(t *testing.T) {
+	r := newThingReconciler(nil)
+	require.Equal(t, DefaultPrePauseRequeue, r.prePauseRequeue())
+}
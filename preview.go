@@ -0,0 +1,137 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// PauseReportSampleSize the max number of the sample names of every kind in PauseReport.
+const PauseReportSampleSize = 10
+
+// PauseReport the report of what the reconciler would do to the existing resources of a GroupVersionKind.
+type PauseReport struct {
+	// Total the number of the resources.
+	Total int
+	// Candidates the number of the resources which would be paused.
+	Candidates int
+	// Paused the number of the resources already paused by us.
+	Paused int
+	// NotReady the number of the resources which are not ready to pause yet, like the ones not Ready and Synced, the ones
+	// whose spec change is not observed, and the ones still settling.
+	NotReady int
+	// Frozen the number of the resources in the frozen window after we unpaused them.
+	Frozen int
+	// OutOfRollout the number of the resources which would be paused but are out of the RolloutPercentage or
+	// younger than the OnboardAfterAge.
+	OutOfRollout int
+	// Ignored the number of the resources we leave alone, like the deleted ones and the ones paused by others.
+	Ignored int
+
+	// SampleCandidates at most PauseReportSampleSize names of the resources which would be paused.
+	SampleCandidates []types.NamespacedName
+	// SampleNotReady at most PauseReportSampleSize names of the resources which are not ready.
+	SampleNotReady []types.NamespacedName
+}
+
+// PreviewPauseCandidates evaluates the pause decision of all the resources of the GroupVersionKind with the configuration
+// of the reconciler without writing anything, it's to preview the effect before enabling the reconciler on a new GroupVersionKind.
+func (r *Reconciler) PreviewPauseCandidates(ctx context.Context) (*PauseReport, error) {
+	list := new(unstructured.UnstructuredList)
+	list.SetGroupVersionKind(r.GroupVersionKind.GroupVersion().WithKind(r.GroupVersionKind.Kind + "List"))
+	err := r.kube().List(ctx, list)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %s: %w", r.GroupVersionKind, err)
+	}
+
+	report := &PauseReport{Total: len(list.Items)}
+	now := r.now()
+	for i := range list.Items {
+		obj := &list.Items[i]
+		key := client.ObjectKeyFromObject(obj)
+		d, info, err := r.previewDecision(ctx, obj, now)
+		if err != nil {
+			return nil, fmt.Errorf("unable to preview %s: %w", key, err)
+		}
+
+		switch {
+		case d.action == ActionPause, d.action == ActionWouldPause, d.reason == reasonPauseUntilPassed:
+			report.Candidates++
+			if len(report.SampleCandidates) < PauseReportSampleSize {
+				report.SampleCandidates = append(report.SampleCandidates, key)
+			}
+		case d.action == ActionIgnore, d.reason == reasonDeleting, d.reason == reasonDeletionFailing,
+			d.reason == string(UnpauseReasonDeleted), d.reason == string(UnpauseReasonSpecNotMatched),
+			d.reason == string(UnpauseReasonPauseInfoLost):
+			report.Ignored++
+		case info != nil && info.Pause:
+			report.Paused++
+		case d.reason == reasonFrozenWindow, d.reason == reasonReconcileOnceWait:
+			report.Frozen++
+		case d.reason == reasonOutOfRollout, d.reason == reasonNotOnboarded:
+			report.OutOfRollout++
+		default:
+			report.NotReady++
+			if len(report.SampleNotReady) < PauseReportSampleSize {
+				report.SampleNotReady = append(report.SampleNotReady, key)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// PreviewDecision the decision the reconciler would make for a resource, see PreviewDecisions.
+type PreviewDecision struct {
+	Key    types.NamespacedName
+	Action Action
+	Reason string
+}
+
+// PreviewDecisions evaluates what the reconciler would do to every resource of the GroupVersionKind now without
+// writing anything, e.g. for a dry run before enabling the reconciler. It makes the same decision as the reconcile, except
+// that the PauseBudget, the PrePauseValidate and the ReadinessProbe are not consulted.
+func (r *Reconciler) PreviewDecisions(ctx context.Context) ([]PreviewDecision, error) {
+	list := new(unstructured.UnstructuredList)
+	list.SetGroupVersionKind(r.GroupVersionKind.GroupVersion().WithKind(r.GroupVersionKind.Kind + "List"))
+	err := r.kube().List(ctx, list)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %s: %w", r.GroupVersionKind, err)
+	}
+
+	decisions := make([]PreviewDecision, 0, len(list.Items))
+	now := r.now()
+	for i := range list.Items {
+		obj := &list.Items[i]
+		key := client.ObjectKeyFromObject(obj)
+		d, _, err := r.previewDecision(ctx, obj, now)
+		if err != nil {
+			return nil, fmt.Errorf("unable to preview %s: %w", key, err)
+		}
+		decisions = append(decisions, PreviewDecision{Key: key, Action: d.action, Reason: d.reason})
+	}
+
+	return decisions, nil
+}
+
+// previewDecision returns the decision the reconciler would make for obj at now and its pause info, it shares decide
+// with reconcile but takes none of the side effects.
+func (r *Reconciler) previewDecision(ctx context.Context, obj *unstructured.Unstructured, now time.Time) (decision, *PauseInfo, error) {
+	info, err := r.parsePauseInfo(obj)
+	if err != nil {
+		return decision{}, nil, fmt.Errorf("unable to parse pause info: %w", err)
+	}
+
+	// Not to flood the log with the decisions of every resource.
+	d, err := r.decide(log.IntoContext(ctx, logr.Discard()), obj, info, now)
+	if err != nil {
+		return decision{}, nil, err
+	}
+	return d, info, nil
+}
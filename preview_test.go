@@ -0,0 +1,145 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPreviewPauseCandidates(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+	r := newThingReconciler(cli)
+
+	for _, name := range []string{"ready-1", "ready-2", "paused"} {
+		err := cli.Create(ctx, newThing(t, name))
+		require.Nil(t, err)
+	}
+
+	notReady := newThing(t, "not-ready")
+	setConditions(t, notReady, xpv1.Creating(), xpv1.ReconcileSuccess())
+	err := cli.Create(ctx, notReady)
+	require.Nil(t, err)
+
+	others := newThing(t, "others")
+	others.SetAnnotations(map[string]string{AnnotationKeyReconciliationPaused: "true"})
+	err = cli.Create(ctx, others)
+	require.Nil(t, err)
+
+	err = r.ensurePause(ctx, getThing(t, cli, "paused"), nil, "test")
+	require.Nil(t, err)
+
+	versions := make(map[string]string)
+	for _, name := range []string{"ready-1", "ready-2", "paused", "not-ready", "others"} {
+		versions[name] = getThing(t, cli, name).GetResourceVersion()
+	}
+
+	report, err := r.PreviewPauseCandidates(ctx)
+	require.Nil(t, err)
+	require.Equal(t, &PauseReport{
+		Total:            5,
+		Candidates:       2,
+		Paused:           1,
+		NotReady:         1,
+		Ignored:          1,
+		SampleCandidates: []client.ObjectKey{{Name: "ready-1"}, {Name: "ready-2"}},
+		SampleNotReady:   []client.ObjectKey{{Name: "not-ready"}},
+	}, report)
+
+	// nothing is written.
+	for name, version := range versions {
+		require.Equal(t, version, getThing(t, cli, name).GetResourceVersion())
+	}
+}
+
+func TestPreviewDecisions(t *testing.T) {
+	h := newHarness(t)
+	h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+	h.r.UnPausePollJitter = pointer.Float64(0)
+	ctx := context.Background()
+
+	create := func(name string, mutate func(u *unstructured.Unstructured)) {
+		thing := newThing(t, name)
+		if mutate != nil {
+			mutate(thing)
+		}
+		err := h.cli.Create(ctx, thing)
+		require.Nil(t, err)
+	}
+	create("ready", nil)
+	create("not-ready", func(u *unstructured.Unstructured) {
+		setConditions(t, u, xpv1.Creating(), xpv1.ReconcileSuccess())
+	})
+	create("others", func(u *unstructured.Unstructured) {
+		u.SetAnnotations(map[string]string{AnnotationKeyReconciliationPaused: "true"})
+	})
+	for _, name := range []string{"paused", "updated", "due", "pinned"} {
+		create(name, nil)
+		err := h.r.ensurePause(ctx, getThing(t, h.cli, name), nil, "test")
+		require.Nil(t, err)
+	}
+	thing := getThing(t, h.cli, "updated")
+	err := unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+	err = h.cli.Update(ctx, thing)
+	require.Nil(t, err)
+	thing = getThing(t, h.cli, "pinned")
+	ann := thing.GetAnnotations()
+	ann[AnnotationKeyPausePinned] = "true"
+	thing.SetAnnotations(ann)
+	err = h.cli.Update(ctx, thing)
+	require.Nil(t, err)
+	h.advance(time.Hour)
+	// the others are paused later, so not due yet.
+	for _, name := range []string{"paused", "updated", "pinned"} {
+		thing := getThing(t, h.cli, name)
+		info, err := h.r.parsePauseInfo(thing)
+		require.Nil(t, err)
+		info.ShouldUnpauseTime = &metav1.Time{Time: h.clock.Now().Add(time.Hour)}
+		err = h.r.setPauseInfo(thing, info)
+		require.Nil(t, err)
+		err = h.cli.Update(ctx, thing)
+		require.Nil(t, err)
+	}
+
+	versions := make(map[string]string)
+	for _, name := range []string{"ready", "not-ready", "others", "paused", "updated", "due", "pinned"} {
+		versions[name] = getThing(t, h.cli, name).GetResourceVersion()
+	}
+	decisions, err := h.r.PreviewDecisions(ctx)
+	require.Nil(t, err)
+	actions := make(map[string]PreviewDecision)
+	for _, d := range decisions {
+		actions[d.Key.Name] = d
+	}
+	require.Equal(t, map[string]PreviewDecision{
+		"ready":     {Key: client.ObjectKey{Name: "ready"}, Action: ActionPause, Reason: "Ready=Available, Synced=ReconcileSuccess"},
+		"not-ready": {Key: client.ObjectKey{Name: "not-ready"}, Action: ActionKeepUnpaused, Reason: "Ready is False"},
+		"others":    {Key: client.ObjectKey{Name: "others"}, Action: ActionIgnore, Reason: "paused by others"},
+		"paused":    {Key: client.ObjectKey{Name: "paused"}, Action: ActionKeepPaused, Reason: "wait for the UnPausePollInterval"},
+		"updated":   {Key: client.ObjectKey{Name: "updated"}, Action: ActionUnpause, Reason: string(UnpauseReasonUpdated)},
+		"due":       {Key: client.ObjectKey{Name: "due"}, Action: ActionUnpause, Reason: string(UnpauseReasonPollInterval)},
+		"pinned":    {Key: client.ObjectKey{Name: "pinned"}, Action: ActionKeepPaused, Reason: "pinned"},
+	}, actions)
+
+	// nothing is written, and the reconcile takes the previewed actions.
+	var action Action
+	h.r.OnReconcile = func(_ ctrl.Request, a Action, _ ctrl.Result, _ error) {
+		action = a
+	}
+	for name, d := range actions {
+		require.Equal(t, versions[name], getThing(t, h.cli, name).GetResourceVersion(), name)
+		_, err := h.r.Reconcile(ctx, ctrl.Request{NamespacedName: d.Key})
+		require.Nil(t, err)
+		require.Equal(t, d.Action, action, name)
+	}
+}
@@ -0,0 +1,74 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DefaultReadinessProbeRequeue the default Duration we requeue the resource after once the ReadinessProbe reports
+// it's not ready or fails.
+const DefaultReadinessProbeRequeue = time.Minute
+
+// ReadinessProbe an external check of whether a resource is ready to pause, e.g. querying the cloud provider or a
+// health endpoint of the service running on it, see Reconciler.ReadinessProbe.
+type ReadinessProbe interface {
+	Probe(ctx context.Context, obj *unstructured.Unstructured) (ready bool, err error)
+}
+
+// ReadinessProbeFunc adapts a function to a ReadinessProbe.
+type ReadinessProbeFunc func(ctx context.Context, obj *unstructured.Unstructured) (bool, error)
+
+// Probe calls f(ctx, obj).
+func (f ReadinessProbeFunc) Probe(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+	return f(ctx, obj)
+}
+
+func (r *Reconciler) readinessProbeRequeue() time.Duration {
+	if r.ReadinessProbeRequeue > 0 {
+		return r.ReadinessProbeRequeue
+	}
+
+	return DefaultReadinessProbeRequeue
+}
+
+// readinessProbeOnly returns true if the ReadinessProbe replaces the condition checks.
+func (r *Reconciler) readinessProbeOnly() bool {
+	return r.ReadinessProbe != nil && r.ReadinessProbeOnly
+}
+
+// probeReady returns true if obj is ready by the ReadinessProbe, the reason is set if it's not.
+// The error of the ReadinessProbe is logged and treated as not ready.
+func (r *Reconciler) probeReady(ctx context.Context, obj *unstructured.Unstructured) (bool, string) {
+	if r.ReadinessProbe == nil {
+		return true, ""
+	}
+
+	ready, err := r.ReadinessProbe.Probe(ctx, obj)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "readiness probe failed")
+		return false, fmt.Sprintf("readiness probe failed: %s", err)
+	}
+	if !ready {
+		return false, "not ready by readiness probe"
+	}
+
+	return true, ""
+}
+
+// readyByProbe returns false if the ReadinessProbe replaces the condition checks and obj is not ready by it, it's
+// checked where the conditions are checked to keep or take the pause besides the fresh pause, e.g. the restore.
+func (r *Reconciler) readyByProbe(ctx context.Context, obj *unstructured.Unstructured) bool {
+	if !r.readinessProbeOnly() {
+		return true
+	}
+
+	ready, reason := r.probeReady(ctx, obj)
+	if !ready {
+		log.FromContext(ctx).V(1).Info("not ready by the readiness probe", "reason", reason)
+	}
+	return ready
+}
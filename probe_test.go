@@ -0,0 +1,157 @@
+package crossplanepause
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+)
+
+func TestReadinessProbe(t *testing.T) {
+	h := newHarness(t)
+	h.r.ReadinessProbeRequeue = 2 * time.Minute
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	var calls int
+	var probeErr error
+	ready := false
+	h.r.ReadinessProbe = ReadinessProbeFunc(func(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+		calls++
+		require.Equal(t, "thing", obj.GetName())
+		return ready, probeErr
+	})
+
+	// not ready by the probe though Ready and Synced.
+	result := h.reconcile()
+	require.Equal(t, 2*time.Minute, result.RequeueAfter)
+	paused, _ := h.state()
+	require.False(t, paused)
+	require.Equal(t, 1, calls)
+
+	// failed, it's requeued as well.
+	probeErr = errors.New("connection refused")
+	result = h.reconcile()
+	require.Equal(t, 2*time.Minute, result.RequeueAfter)
+	paused, _ = h.state()
+	require.False(t, paused)
+	require.Equal(t, 2, calls)
+
+	// the conditions are still checked before the probe.
+	probeErr = nil
+	ready = true
+	h.mutate(func(thing *unstructured.Unstructured) {
+		setConditions(t, thing, xpv1.Creating(), xpv1.ReconcileSuccess())
+	})
+	h.reconcile()
+	paused, _ = h.state()
+	require.False(t, paused)
+	require.Equal(t, 2, calls)
+
+	h.mutate(func(thing *unstructured.Unstructured) {
+		setConditions(t, thing, xpv1.Available(), xpv1.ReconcileSuccess())
+	})
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+	require.Equal(t, 3, calls)
+}
+
+func TestReadinessProbeOnly(t *testing.T) {
+	h := newHarness(t)
+	h.r.ReadinessProbeOnly = true
+	thing := newThing(t, "thing")
+	setConditions(t, thing, xpv1.Creating())
+	err := h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+
+	// ignored without the probe.
+	result := h.reconcile()
+	require.Equal(t, h.r.NotReadyRequeue, result.RequeueAfter)
+	paused, _ := h.state()
+	require.False(t, paused)
+
+	ready := false
+	h.r.ReadinessProbe = ReadinessProbeFunc(func(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+		return ready, nil
+	})
+	result = h.reconcile()
+	require.Equal(t, DefaultReadinessProbeRequeue, result.RequeueAfter)
+	paused, _ = h.state()
+	require.False(t, paused)
+
+	// paused by the probe alone though not Ready.
+	ready = true
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+	reason, err := h.r.pauseReason(context.Background(), getThing(t, h.cli, "thing"))
+	require.Nil(t, err)
+	require.Equal(t, "ready by readiness probe", reason)
+}
+
+func TestReadinessProbeOnlyPaused(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		setup  func(h *harness)
+		change func(h *harness)
+		reason UnpauseReason
+	}{
+		{
+			name: "restore",
+			setup: func(h *harness) {
+				h.r.RestoreStrippedPause = true
+			},
+			change: func(h *harness) {
+				h.mutate(func(thing *unstructured.Unstructured) {
+					ann := thing.GetAnnotations()
+					delete(ann, AnnotationKeyReconciliationPaused)
+					thing.SetAnnotations(ann)
+				})
+			},
+			reason: UnpauseReasonPauseStripped,
+		},
+		{
+			name: "soft unpause",
+			setup: func(h *harness) {
+				h.r.SoftUnpause = true
+				h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+				h.r.UnPausePollJitter = pointer.Float64(0)
+			},
+			change: func(h *harness) {
+				h.advance(time.Hour)
+			},
+			reason: UnpauseReasonPollInterval,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHarness(t)
+			h.r.ReadinessProbeOnly = true
+			ready := true
+			h.r.ReadinessProbe = ReadinessProbeFunc(func(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+				return ready, nil
+			})
+			tc.setup(h)
+			err := h.cli.Create(context.Background(), newThing(t, "thing"))
+			require.Nil(t, err)
+
+			h.reconcile()
+			paused, _ := h.state()
+			require.True(t, paused)
+
+			// not ready by the probe any more, not kept paused though the conditions are intact.
+			ready = false
+			tc.change(h)
+			h.reconcile()
+			paused, info := h.state()
+			require.False(t, paused)
+			require.False(t, info.Pause)
+			require.Equal(t, tc.reason, info.History[len(info.History)-1].Reason)
+		})
+	}
+}
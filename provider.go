@@ -0,0 +1,129 @@
+package crossplanepause
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// DefaultProviderRestartCooldown the default Duration we don't pause any resource after the provider restarted.
+const DefaultProviderRestartCooldown = 5 * time.Minute
+
+func (r *Reconciler) providerRestartCooldown() time.Duration {
+	if r.ProviderRestartCooldown > 0 {
+		return r.ProviderRestartCooldown
+	}
+
+	return DefaultProviderRestartCooldown
+}
+
+// ProviderRestarted tells the provider restarted at, no resource is paused in the ProviderRestartCooldown after it,
+// since the provider may report the stale Ready and Synced before reconciling the resources again. Call it from
+// a heartbeat of the provider, the rollouts of the ProviderDeployment are detected by the reconciler itself.
+func (r *Reconciler) ProviderRestarted(at time.Time) {
+	if last, ok := r.providerRestart.Load().(time.Time); ok && !at.After(last) {
+		return
+	}
+	r.providerRestart.Store(at)
+}
+
+// providerCooldownDelay returns how long to wait at now before pausing after the provider restarted, or 0 if it's
+// out of the ProviderRestartCooldown. It's the whole cooldown while the ProviderDeployment is rolling out.
+func (r *Reconciler) providerCooldownDelay(now time.Time) time.Duration {
+	if r.providerRollingOut.Load() {
+		return r.providerRestartCooldown()
+	}
+
+	last, ok := r.providerRestart.Load().(time.Time)
+	if !ok {
+		return 0
+	}
+
+	if delay := last.Add(r.providerRestartCooldown()).Sub(now); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// providerRolloutStarted returns true if the pod template of the Deployment of the provider changed from old to new,
+// including the `kubectl rollout restart`. Scaling it changes the generation but not the pod template.
+func providerRolloutStarted(oldDeploy, newDeploy *appsv1.Deployment) bool {
+	return !equality.Semantic.DeepEqual(oldDeploy.Spec.Template, newDeploy.Spec.Template)
+}
+
+// providerRolloutComplete returns true if all the replicas of the Deployment of the provider are updated and available.
+func providerRolloutComplete(deploy *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+
+	status := deploy.Status
+	return status.ObservedGeneration >= deploy.Generation && status.UpdatedReplicas == replicas &&
+		status.Replicas == replicas && status.AvailableReplicas == replicas
+}
+
+// watchProviderDeployment makes c record the rollouts of the ProviderDeployment as restarts of the provider.
+// The Deployment is watched by a cache of its own limited to it, instead of caching all the Deployments in the cluster
+// by the cache of mgr.
+func (r *Reconciler) watchProviderDeployment(mgr ctrl.Manager, c controller.Controller) error {
+	scheme := runtime.NewScheme()
+	err := appsv1.AddToScheme(scheme)
+	if err != nil {
+		return err
+	}
+
+	deployments, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:    scheme,
+		Mapper:    mgr.GetRESTMapper(),
+		Namespace: r.ProviderDeployment.Namespace,
+		SelectorsByObject: cache.SelectorsByObject{
+			&appsv1.Deployment{}: {Field: fields.OneTermEqualSelector("metadata.name", r.ProviderDeployment.Name)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create cache: %w", err)
+	}
+	err = mgr.Add(deployments)
+	if err != nil {
+		return fmt.Errorf("unable to add cache: %w", err)
+	}
+
+	return c.Watch(source.NewKindWithCache(&appsv1.Deployment{}, deployments), r.providerDeploymentHandler(),
+		predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetNamespace() == r.ProviderDeployment.Namespace && obj.GetName() == r.ProviderDeployment.Name
+		}))
+}
+
+// providerDeploymentHandler records the rollouts of the ProviderDeployment without enqueuing anything, the resources
+// kept unpaused by the ProviderRestartCooldown are requeued by themselves.
+func (r *Reconciler) providerDeploymentHandler() handler.Funcs {
+	return handler.Funcs{
+		UpdateFunc: func(e event.UpdateEvent, _ workqueue.RateLimitingInterface) {
+			oldDeploy, ok1 := e.ObjectOld.(*appsv1.Deployment)
+			newDeploy, ok2 := e.ObjectNew.(*appsv1.Deployment)
+			if !ok1 || !ok2 {
+				return
+			}
+			if providerRolloutStarted(oldDeploy, newDeploy) {
+				r.providerRollingOut.Store(true)
+			}
+			if providerRolloutComplete(newDeploy) && r.providerRollingOut.CompareAndSwap(true, false) {
+				r.ProviderRestarted(r.now())
+			}
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestProviderRestartCooldown(t *testing.T) {
+	h := newHarness(t)
+	h.r.ProviderRestartCooldown = 10 * time.Minute
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	deploy := &appsv1.Deployment{}
+	deploy.Generation = 1
+	deploy.Spec.Replicas = pointer.Int32(1)
+	deploy.Status = appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	update := func(oldDeploy, newDeploy *appsv1.Deployment) {
+		h.r.providerDeploymentHandler().Update(event.UpdateEvent{ObjectOld: oldDeploy, ObjectNew: newDeploy}, nil)
+	}
+
+	// the status updates of the provider are not restarts.
+	update(deploy, deploy.DeepCopy())
+	require.Zero(t, h.r.providerCooldownDelay(h.clock.Now()))
+
+	// neither is scaling it.
+	scaled := deploy.DeepCopy()
+	scaled.Generation = 2
+	scaled.Spec.Replicas = pointer.Int32(2)
+	update(deploy, scaled)
+	scaledOut := scaled.DeepCopy()
+	scaledOut.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+	update(scaled, scaledOut)
+	require.Zero(t, h.r.providerCooldownDelay(h.clock.Now()))
+
+	// suppressed while it's rolling out.
+	rollingOut := scaledOut.DeepCopy()
+	rollingOut.Generation = 3
+	rollingOut.Spec.Template.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "now"}
+	update(scaledOut, rollingOut)
+	h.advance(time.Hour)
+	result := h.reconcile()
+	require.Equal(t, 10*time.Minute, result.RequeueAfter)
+	paused, _ := h.state()
+	require.False(t, paused)
+
+	// and in the cooldown once it completes.
+	rolledOut := rollingOut.DeepCopy()
+	rolledOut.Status = appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+	update(rollingOut, rolledOut)
+	h.advance(4 * time.Minute)
+	result = h.reconcile()
+	require.Equal(t, 6*time.Minute, result.RequeueAfter)
+	paused, _ = h.state()
+	require.False(t, paused)
+
+	// a heartbeat extends it, an older one doesn't.
+	h.r.ProviderRestarted(h.clock.Now())
+	h.r.ProviderRestarted(h.clock.Now().Add(-time.Hour))
+	result = h.reconcile()
+	require.Equal(t, 10*time.Minute, result.RequeueAfter)
+
+	h.advance(result.RequeueAfter)
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+}
+
+func TestWatchProviderDeployment(t *testing.T) {
+	mgr := newTestManager(t)
+	r := newThingReconciler(mgr.GetClient())
+	r.ProviderDeployment = &types.NamespacedName{Namespace: "crossplane-system", Name: "provider-aws"}
+	err := r.SetupWithManager(mgr)
+	require.Nil(t, err)
+}
@@ -0,0 +1,61 @@
+package crossplanepause
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// fingerprint returns a hash of the content of obj we care about for the QuiescencePeriod, which is the trimmed
+// object and the status. Our own pause info and pause state and the metadata maintained by the API server are not part of it.
+func (r *Reconciler) fingerprint(obj *unstructured.Unstructured) (string, error) {
+	content := r.trimObject(obj)
+	if status, ok := obj.Object["status"]; ok {
+		content.Object["status"] = runtime.DeepCopyJSONValue(status)
+		// Written by us, see StatusPauseState.
+		unstructured.RemoveNestedField(content.Object, "status", StatusFieldPauseState)
+	}
+
+	// The keys of the maps are sorted once marshaled.
+	data, err := json.Marshal(content.Object)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal object: %w", err)
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// checkQuiescence returns how long obj should stay unchanged before it's quiescent, it's 0 if it's quiescent already.
+// Once obj is changed, it returns the observation to record the fingerprint and the time into the pause info of obj.
+func (r *Reconciler) checkQuiescence(obj *unstructured.Unstructured, info *PauseInfo, now time.Time) (time.Duration, observation, error) {
+	fingerprint, err := r.fingerprint(obj)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if info.Fingerprint == fingerprint && info.LastChangeTime != nil {
+		if quiet := now.Sub(info.LastChangeTime.Time); quiet < r.QuiescencePeriod {
+			return r.QuiescencePeriod - quiet, nil, nil
+		}
+		return 0, nil, nil
+	}
+
+	return r.QuiescencePeriod, func(obj *unstructured.Unstructured, info *PauseInfo) error {
+		// The object may be refetched.
+		fingerprint, err := r.fingerprint(obj)
+		if err != nil {
+			return err
+		}
+		info.Fingerprint = fingerprint
+		info.LastChangeTime = &metav1.Time{Time: now}
+		return nil
+	}, nil
+}
@@ -0,0 +1,57 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestQuiescencePeriod(t *testing.T) {
+	h := newHarness(t)
+	h.r.QuiescencePeriod = 10 * time.Minute
+	// not ready, it's paused by quiescence only.
+	thing := newThing(t, "thing")
+	setConditions(t, thing, xpv1.Creating(), xpv1.ReconcileSuccess())
+	err := h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+
+	result := h.reconcile()
+	require.Equal(t, h.r.QuiescencePeriod, result.RequeueAfter)
+	paused, info := h.state()
+	require.False(t, paused)
+	require.NotEmpty(t, info.Fingerprint)
+	require.Equal(t, h.clock.Now().Unix(), info.LastChangeTime.Unix())
+
+	// it keeps changing, the period restarts every time.
+	for i := 0; i < 3; i++ {
+		h.advance(8 * time.Minute)
+		h.mutate(func(thing *unstructured.Unstructured) {
+			err := unstructured.SetNestedField(thing.Object, int64(i), "status", "atProvider", "counter")
+			require.Nil(t, err)
+		})
+		result = h.reconcile()
+		require.Equal(t, h.r.QuiescencePeriod, result.RequeueAfter)
+		paused, _ = h.state()
+		require.False(t, paused)
+	}
+
+	// our own write is not a change.
+	h.advance(4 * time.Minute)
+	result = h.reconcile()
+	require.Equal(t, 6*time.Minute, result.RequeueAfter)
+	paused, _ = h.state()
+	require.False(t, paused)
+
+	// it goes quiet.
+	h.advance(6 * time.Minute)
+	h.reconcile()
+	paused, info = h.state()
+	require.True(t, paused)
+	require.True(t, info.Pause)
+	require.Empty(t, info.Fingerprint)
+	require.Nil(t, info.LastChangeTime)
+}
@@ -0,0 +1,78 @@
+package crossplanepause
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UnpauseReason the stable code of why we unpause a resource, it's recorded in PauseInfo.History for auditing.
+type UnpauseReason string
+
+// The UnpauseReasons.
+const (
+	// UnpauseReasonUpdated the resource is updated since we paused it.
+	UnpauseReasonUpdated UnpauseReason = "Updated"
+	// UnpauseReasonPollInterval the resource is paused longer than the UnPausePollInterval.
+	UnpauseReasonPollInterval UnpauseReason = "PollInterval"
+	// UnpauseReasonDeleted the resource is deleted.
+	UnpauseReasonDeleted UnpauseReason = "Deleted"
+	// UnpauseReasonPauseStripped the pause annotation is stripped by others and the resource is not Ready and Synced anymore.
+	UnpauseReasonPauseStripped UnpauseReason = "PauseStripped"
+	// UnpauseReasonOrphaned the resource is paused by us but filtered out by the predicates now.
+	UnpauseReasonOrphaned UnpauseReason = "Orphaned"
+	// UnpauseReasonReconcileOnce the resource is requested to reconcile once by AnnotationKeyReconcileOnce.
+	UnpauseReasonReconcileOnce UnpauseReason = "ReconcileOnce"
+	// UnpauseReasonPauseInfoLost the resource is paused by us but the pause info is removed by others.
+	UnpauseReasonPauseInfoLost UnpauseReason = "PauseInfoLost"
+	// UnpauseReasonPauseRestoresExhausted the pause annotation keeps being stripped by others after MaxPauseRestores.
+	UnpauseReasonPauseRestoresExhausted UnpauseReason = "PauseRestoresExhausted"
+	// UnpauseReasonSpecNotMatched the spec of the resource is not matched by the SpecMatch now.
+	UnpauseReasonSpecNotMatched UnpauseReason = "SpecNotMatched"
+	// UnpauseReasonPauseUntil the time in the AnnotationKeyPauseUntil annotation is reached.
+	UnpauseReasonPauseUntil UnpauseReason = "PauseUntil"
+)
+
+// MaxPauseHistory the max number of the UnpauseRecords kept in PauseInfo.History, the oldest ones are dropped.
+const MaxPauseHistory = 10
+
+// UnpauseRecord records an unpause of a resource.
+type UnpauseRecord struct {
+	Time   metav1.Time   `json:"time"`
+	Reason UnpauseReason `json:"reason"`
+	// Note the note in the AnnotationKeyPauseNote annotation when it's unpaused.
+	Note string `json:"note,omitempty"`
+}
+
+var unpauseReasonMessages = map[UnpauseReason]string{
+	UnpauseReasonUpdated:                "resource updated",
+	UnpauseReasonPollInterval:           "resource trigger unPause poll interval",
+	UnpauseReasonDeleted:                "resource deleted",
+	UnpauseReasonPauseStripped:          "pause annotation stripped and not Ready and Synced",
+	UnpauseReasonOrphaned:               "resource filtered out by the predicates",
+	UnpauseReasonReconcileOnce:          "resource requested to reconcile once",
+	UnpauseReasonPauseInfoLost:          "pause info removed by others",
+	UnpauseReasonPauseRestoresExhausted: "pause annotation keeps being stripped by others",
+	UnpauseReasonSpecNotMatched:         "resource spec not matched",
+	UnpauseReasonPauseUntil:             "resource reached the pause until",
+}
+
+// Message returns the human readable message of the reason.
+func (r UnpauseReason) Message() string {
+	if msg, ok := unpauseReasonMessages[r]; ok {
+		return msg
+	}
+
+	return string(r)
+}
+
+// EventReason returns the reason of the event emitted once we unpause a resource for r.
+func (r UnpauseReason) EventReason() string {
+	return "Unpaused" + string(r)
+}
+
+// appendHistory appends an UnpauseRecord to the history of info, keeping at most MaxPauseHistory records.
+func appendHistory(info *PauseInfo, record UnpauseRecord) {
+	info.History = append(info.History, record)
+	if len(info.History) > MaxPauseHistory {
+		info.History = info.History[len(info.History)-MaxPauseHistory:]
+	}
+}
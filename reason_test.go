@@ -0,0 +1,190 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUnpauseReason(t *testing.T) {
+	tests := []struct {
+		name    string
+		reason  UnpauseReason
+		setup   func(r *Reconciler)
+		trigger func(t *testing.T, cli client.Client, thing *unstructured.Unstructured)
+	}{
+		{
+			name:   "updated",
+			reason: UnpauseReasonUpdated,
+			trigger: func(t *testing.T, cli client.Client, thing *unstructured.Unstructured) {
+				err := unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+				require.Nil(t, err)
+				err = cli.Update(context.Background(), thing)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:   "poll interval",
+			reason: UnpauseReasonPollInterval,
+			setup: func(r *Reconciler) {
+				r.UnPausePollInterval = pointer.Duration(time.Nanosecond)
+			},
+			trigger: func(t *testing.T, cli client.Client, thing *unstructured.Unstructured) {},
+		},
+		{
+			name:   "deleted",
+			reason: UnpauseReasonDeleted,
+			trigger: func(t *testing.T, cli client.Client, thing *unstructured.Unstructured) {
+				thing.SetFinalizers([]string{"test"})
+				err := cli.Update(context.Background(), thing)
+				require.Nil(t, err)
+				err = cli.Delete(context.Background(), thing)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:   "pause stripped",
+			reason: UnpauseReasonPauseStripped,
+			setup: func(r *Reconciler) {
+				r.VerifyPauseRequeue = time.Second
+			},
+			trigger: func(t *testing.T, cli client.Client, thing *unstructured.Unstructured) {
+				ann := thing.GetAnnotations()
+				delete(ann, AnnotationKeyReconciliationPaused)
+				thing.SetAnnotations(ann)
+				setConditions(t, thing, xpv1.Unavailable(), xpv1.ReconcileSuccess())
+				err := cli.Update(context.Background(), thing)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:   "spec not matched",
+			reason: UnpauseReasonSpecNotMatched,
+			setup: func(r *Reconciler) {
+				r.SpecMatch = func(spec map[string]interface{}) bool {
+					cidrBlock, _, _ := unstructured.NestedString(spec, "forProvider", "cidrBlock")
+					return cidrBlock != "excluded"
+				}
+			},
+			trigger: func(t *testing.T, cli client.Client, thing *unstructured.Unstructured) {
+				err := unstructured.SetNestedField(thing.Object, "excluded", "spec", "forProvider", "cidrBlock")
+				require.Nil(t, err)
+				err = cli.Update(context.Background(), thing)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:   "pause info lost",
+			reason: UnpauseReasonPauseInfoLost,
+			trigger: func(t *testing.T, cli client.Client, thing *unstructured.Unstructured) {
+				ann := thing.GetAnnotations()
+				delete(ann, AnnotationKeyPauseInfo)
+				thing.SetAnnotations(ann)
+				err := cli.Update(context.Background(), thing)
+				require.Nil(t, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().Build()
+			ctx := context.Background()
+
+			err := cli.Create(ctx, newThing(t, "thing"))
+			require.Nil(t, err)
+
+			recorder := record.NewFakeRecorder(10)
+			r := newThingReconciler(cli)
+			r.Recorder = recorder
+			if tt.setup != nil {
+				tt.setup(r)
+			}
+			req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}}
+
+			_, err = r.Reconcile(ctx, req)
+			require.Nil(t, err)
+			tt.trigger(t, cli, getThing(t, cli, "thing"))
+			_, err = r.Reconcile(ctx, req)
+			require.Nil(t, err)
+
+			info, err := r.parsePauseInfo(getThing(t, cli, "thing"))
+			require.Nil(t, err)
+			require.False(t, info.Pause)
+			require.Len(t, info.History, 1)
+			require.Equal(t, tt.reason, info.History[0].Reason)
+			require.True(t, info.LastUnPauseTime.Equal(&info.History[0].Time))
+			require.Contains(t, <-recorder.Events, tt.reason.EventReason())
+		})
+	}
+}
+
+func TestPausedBy(t *testing.T) {
+	h := newHarness(t)
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	h.reconcile()
+	thing := getThing(t, h.cli, "thing")
+	require.Equal(t, h.r.controllerName(), thing.GetAnnotations()[AnnotationKeyPausedBy])
+
+	// the pause info is removed by others, we reclaim it.
+	h.mutate(func(thing *unstructured.Unstructured) {
+		ann := thing.GetAnnotations()
+		delete(ann, AnnotationKeyPauseInfo)
+		thing.SetAnnotations(ann)
+	})
+	h.reconcile()
+	paused, info := h.state()
+	require.False(t, paused)
+	require.False(t, info.Pause)
+	require.NotContains(t, getThing(t, h.cli, "thing").GetAnnotations(), AnnotationKeyPausedBy)
+
+	// it's paused again once the frozen window passes.
+	h.advance(DefaultFrozenTimeDuration)
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+
+	// paused by others without the mark, it's left alone.
+	other := newThing(t, "other")
+	other.SetAnnotations(map[string]string{AnnotationKeyReconciliationPaused: "true"})
+	err = h.cli.Create(context.Background(), other)
+	require.Nil(t, err)
+	_, err = h.r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "other"}})
+	require.Nil(t, err)
+	other = getThing(t, h.cli, "other")
+	require.Equal(t, map[string]string{AnnotationKeyReconciliationPaused: "true"}, other.GetAnnotations())
+
+	// marked by another controller, it's left alone too.
+	marked := newThing(t, "marked")
+	markedAnnotations := map[string]string{AnnotationKeyReconciliationPaused: "true", AnnotationKeyPausedBy: "pause-other"}
+	marked.SetAnnotations(markedAnnotations)
+	err = h.cli.Create(context.Background(), marked)
+	require.Nil(t, err)
+	_, err = h.r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "marked"}})
+	require.Nil(t, err)
+	marked = getThing(t, h.cli, "marked")
+	require.Equal(t, markedAnnotations, marked.GetAnnotations())
+}
+
+func TestAppendHistory(t *testing.T) {
+	info := new(PauseInfo)
+	for i := 0; i < MaxPauseHistory+2; i++ {
+		appendHistory(info, UnpauseRecord{Time: metav1.Unix(int64(i), 0), Reason: UnpauseReasonUpdated})
+	}
+
+	require.Len(t, info.History, MaxPauseHistory)
+	require.Equal(t, int64(2), info.History[0].Time.Unix())
+	require.Equal(t, int64(MaxPauseHistory+1), info.History[MaxPauseHistory-1].Time.Unix())
+}
@@ -0,0 +1,550 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// harness drives Reconcile end to end with a fake client and a fake clock.
+type harness struct {
+	t     *testing.T
+	cli   client.Client
+	clock *clocktesting.FakePassiveClock
+	r     *Reconciler
+}
+
+func newHarness(t *testing.T) *harness {
+	cli := fake.NewClientBuilder().Build()
+	// The pause info keeps the time in seconds.
+	clock := clocktesting.NewFakePassiveClock(time.Date(2022, 7, 22, 10, 54, 18, 0, time.UTC))
+	r := newThingReconciler(cli)
+	r.Clock = clock
+	return &harness{t: t, cli: cli, clock: clock, r: r}
+}
+
+func (h *harness) advance(d time.Duration) {
+	h.clock.SetTime(h.clock.Now().Add(d))
+}
+
+func (h *harness) reconcile() ctrl.Result {
+	h.t.Helper()
+	result, err := h.r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}})
+	require.Nil(h.t, err)
+	return result
+}
+
+func (h *harness) mutate(fn func(thing *unstructured.Unstructured)) {
+	h.t.Helper()
+	thing := getThing(h.t, h.cli, "thing")
+	fn(thing)
+	err := h.cli.Update(context.Background(), thing)
+	require.Nil(h.t, err)
+}
+
+// state returns whether the thing is paused by the annotation and by the pause info.
+func (h *harness) state() (annotated bool, info *PauseInfo) {
+	h.t.Helper()
+	thing := getThing(h.t, h.cli, "thing")
+	info, err := h.r.parsePauseInfo(thing)
+	require.Nil(h.t, err)
+	return isPaused(thing.GetAnnotations()[AnnotationKeyReconciliationPaused]), info
+}
+
+type reconcileStep struct {
+	name    string
+	advance time.Duration
+	mutate  func(t *testing.T, thing *unstructured.Unstructured)
+
+	paused  bool
+	requeue time.Duration
+	// ignored if the thing is left alone, it's paused but without our pause info.
+	ignored bool
+}
+
+func TestReconcileDecisionTree(t *testing.T) {
+	notReady := func(t *testing.T, thing *unstructured.Unstructured) {
+		setConditions(t, thing, xpv1.Creating(), xpv1.ReconcileSuccess())
+	}
+	ready := func(t *testing.T, thing *unstructured.Unstructured) {
+		setConditions(t, thing, xpv1.Available(), xpv1.ReconcileSuccess())
+	}
+	updateSpec := func(t *testing.T, thing *unstructured.Unstructured) {
+		err := unstructured.SetNestedField(thing.Object, "b", "spec", "forProvider", "cidrBlock")
+		require.Nil(t, err)
+	}
+
+	tests := []struct {
+		name   string
+		config func(r *Reconciler)
+		seed   func(t *testing.T, thing *unstructured.Unstructured)
+		steps  []reconcileStep
+	}{
+		{
+			name: "not ready then ready",
+			config: func(r *Reconciler) {
+				r.NotReadyRequeue = time.Minute
+			},
+			seed: notReady,
+			steps: []reconcileStep{
+				{name: "not ready", requeue: time.Minute},
+				{name: "ready", mutate: ready, paused: true},
+			},
+		},
+		{
+			name: "ignore paused by other",
+			seed: func(t *testing.T, thing *unstructured.Unstructured) {
+				thing.SetAnnotations(map[string]string{AnnotationKeyReconciliationPaused: "true"})
+			},
+			steps: []reconcileStep{
+				{name: "ignored", ignored: true},
+				{name: "still ignored after updated", mutate: updateSpec, ignored: true},
+			},
+		},
+		{
+			name: "update triggers unpause then frozen window",
+			steps: []reconcileStep{
+				{name: "pause", paused: true},
+				{name: "not updated", advance: time.Minute, paused: true},
+				{name: "updated", mutate: updateSpec},
+				{name: "frozen", advance: time.Minute, requeue: DefaultFrozenTimeDuration - time.Minute},
+				{name: "still frozen", advance: 3 * time.Minute, requeue: time.Minute},
+				{name: "pause after frozen", advance: time.Minute, paused: true},
+			},
+		},
+		{
+			name: "poll interval",
+			config: func(r *Reconciler) {
+				r.UnPausePollInterval = pointer.Duration(time.Hour)
+				r.UnPausePollJitter = pointer.Float64(0)
+			},
+			steps: []reconcileStep{
+				{name: "pause", paused: true},
+				{name: "before interval", advance: 20 * time.Minute, paused: true, requeue: 40 * time.Minute},
+				{name: "interval reached", advance: 40 * time.Minute},
+				{name: "frozen", requeue: DefaultFrozenTimeDuration},
+				{name: "pause again", advance: DefaultFrozenTimeDuration, paused: true},
+				{name: "interval restarted", advance: 30 * time.Minute, paused: true, requeue: 30 * time.Minute},
+			},
+		},
+		{
+			name: "soft unpause",
+			config: func(r *Reconciler) {
+				r.UnPausePollInterval = pointer.Duration(time.Hour)
+				r.UnPausePollJitter = pointer.Float64(0)
+				r.SoftUnpause = true
+				r.ForceUnpauseEvery = 2
+			},
+			steps: []reconcileStep{
+				{name: "pause", paused: true},
+				{name: "extend", advance: time.Hour, paused: true, requeue: time.Hour},
+				{name: "force unpause", advance: time.Hour},
+			},
+		},
+		{
+			name: "pinned",
+			config: func(r *Reconciler) {
+				r.UnPausePollInterval = pointer.Duration(time.Hour)
+			},
+			seed: func(t *testing.T, thing *unstructured.Unstructured) {
+				thing.SetAnnotations(map[string]string{AnnotationKeyPausePinned: "true"})
+			},
+			steps: []reconcileStep{
+				{name: "pause", paused: true},
+				{name: "keep pinned", advance: 2 * time.Hour, paused: true},
+				{name: "updated", mutate: updateSpec},
+			},
+		},
+		{
+			name: "not ready while paused",
+			steps: []reconcileStep{
+				{name: "pause", paused: true},
+				// crossplane doesn't reconcile the paused resource, the conditions are kept as is usually.
+				{name: "keep pause", mutate: notReady, paused: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t)
+			if tt.config != nil {
+				tt.config(h.r)
+			}
+			thing := newThing(t, "thing")
+			err := unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+			require.Nil(t, err)
+			if tt.seed != nil {
+				tt.seed(t, thing)
+			}
+			err = h.cli.Create(context.Background(), thing)
+			require.Nil(t, err)
+
+			for _, step := range tt.steps {
+				h.advance(step.advance)
+				if step.mutate != nil {
+					h.mutate(func(thing *unstructured.Unstructured) {
+						step.mutate(t, thing)
+					})
+				}
+
+				result := h.reconcile()
+				require.Equal(t, step.requeue, result.RequeueAfter, step.name)
+				annotated, info := h.state()
+				if step.ignored {
+					require.True(t, annotated, step.name)
+					require.Nil(t, info, step.name)
+					continue
+				}
+				require.Equal(t, step.paused, annotated, step.name)
+				require.Equal(t, step.paused, info != nil && info.Pause, step.name)
+			}
+		})
+	}
+}
+
+func TestPausedDurationMetric(t *testing.T) {
+	h := newHarness(t)
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	observed := func(reason UnpauseReason) (uint64, float64) {
+		m := new(dto.Metric)
+		err := pausedDuration.plain.WithLabelValues(testGVK.String(), string(reason)).(prometheus.Metric).Write(m)
+		require.Nil(t, err)
+		return m.Histogram.GetSampleCount(), m.Histogram.GetSampleSum()
+	}
+	count, sum := observed(UnpauseReasonUpdated)
+
+	h.reconcile()
+	h.advance(2 * time.Hour)
+	h.mutate(func(thing *unstructured.Unstructured) {
+		err := unstructured.SetNestedField(thing.Object, "b", "spec", "forProvider", "cidrBlock")
+		require.Nil(t, err)
+	})
+	h.reconcile()
+
+	newCount, newSum := observed(UnpauseReasonUpdated)
+	require.Equal(t, count+1, newCount)
+	require.InDelta(t, (2 * time.Hour).Seconds(), newSum-sum, 1e-6)
+}
+
+func TestJitterRandSource(t *testing.T) {
+	offset := func(t *testing.T) time.Duration {
+		h := newHarness(t)
+		h.r.UnPausePollInterval = pointer.Duration(10 * time.Hour)
+		h.r.RandSource = rand.NewSource(42)
+		err := h.cli.Create(context.Background(), newThing(t, "thing"))
+		require.Nil(t, err)
+
+		h.reconcile()
+		_, info := h.state()
+		require.True(t, info.Pause)
+		return info.ShouldUnpauseTime.Sub(info.LastPauseTime.Time) - 10*time.Hour
+	}
+
+	// the pause info keeps the time in seconds.
+	expected := time.Duration(rand.New(rand.NewSource(42)).Float64() * DefaultUnPausePollJitter * float64(10*time.Hour)).Truncate(time.Second)
+	require.Greater(t, expected, time.Duration(0))
+	require.Equal(t, expected, offset(t))
+	require.Equal(t, expected, offset(t))
+}
+
+func TestJitterTinyInterval(t *testing.T) {
+	r := newThingReconciler(nil)
+	r.RandSource = rand.NewSource(42)
+	r.UnPausePollJitter = pointer.Float64(0.5)
+	from := time.Date(2022, 7, 22, 10, 54, 18, 0, time.UTC)
+
+	// no jitter and no panic.
+	for _, interval := range []time.Duration{time.Nanosecond, 500 * time.Millisecond} {
+		r.UnPausePollInterval = pointer.Duration(interval)
+		info := new(PauseInfo)
+		r.setShouldUnpauseTime(info, from)
+		require.Equal(t, from.Add(interval), info.ShouldUnpauseTime.Time, interval.String())
+	}
+	require.Zero(t, r.jitter(0, 0.5))
+	require.Zero(t, r.jitter(-time.Hour, 0.5))
+
+	// jittered.
+	r.UnPausePollInterval = pointer.Duration(time.Hour)
+	info := new(PauseInfo)
+	r.setShouldUnpauseTime(info, from)
+	offset := info.ShouldUnpauseTime.Sub(from) - time.Hour
+	require.Greater(t, offset, time.Duration(0))
+	require.Less(t, offset, 30*time.Minute)
+
+	// a zero interval is rejected, nil disables it.
+	r.UnPausePollInterval = pointer.Duration(0)
+	require.NotNil(t, r.validateUnPausePollInterval())
+	r.UnPausePollInterval = pointer.Duration(-time.Hour)
+	require.NotNil(t, r.validateUnPausePollInterval())
+	r.UnPausePollInterval = nil
+	require.Nil(t, r.validateUnPausePollInterval())
+	r.UnPausePollInterval = pointer.Duration(time.Nanosecond)
+	require.Nil(t, r.validateUnPausePollInterval())
+}
+
+func TestNextUnpauseTime(t *testing.T) {
+	h := newHarness(t)
+	h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	h.reconcile()
+	thing := getThing(t, h.cli, "thing")
+	info, err := h.r.parsePauseInfo(thing)
+	require.Nil(t, err)
+	require.True(t, info.Pause)
+
+	// paused, it's the time the reconcile would unpause it.
+	next := h.r.NextUnpauseTime(thing, info)
+	require.NotNil(t, next)
+	require.Equal(t, info.ShouldUnpauseTime.Time, *next)
+	h.clock.SetTime(*next)
+	h.reconcile()
+	paused, _ := h.state()
+	require.False(t, paused)
+
+	// recomputed from the LastPauseTime for the pause info without ShouldUnpauseTime.
+	legacy := *info
+	legacy.ShouldUnpauseTime = nil
+	require.Equal(t, info.LastPauseTime.Add(time.Hour), *h.r.NextUnpauseTime(thing, &legacy))
+
+	// unpaused.
+	thing = getThing(t, h.cli, "thing")
+	unpaused, err := h.r.parsePauseInfo(thing)
+	require.Nil(t, err)
+	require.Nil(t, h.r.NextUnpauseTime(thing, unpaused))
+	require.Nil(t, h.r.NextUnpauseTime(thing, nil))
+
+	// pinned.
+	pinned := thing.DeepCopy()
+	pinned.SetAnnotations(map[string]string{AnnotationKeyPausePinned: "true"})
+	require.Nil(t, h.r.NextUnpauseTime(pinned, info))
+
+	// the UnPausePollInterval is disabled.
+	h.r.UnPausePollInterval = nil
+	require.Nil(t, h.r.NextUnpauseTime(thing, info))
+}
+
+func TestMaxRequeueAfter(t *testing.T) {
+	h := newHarness(t)
+	h.r.UnPausePollInterval = pointer.Duration(5 * time.Hour)
+	h.r.UnPausePollJitter = pointer.Float64(0)
+	h.r.MaxRequeueAfter = time.Hour
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	h.reconcile()
+	paused, _ := h.state()
+	require.True(t, paused)
+
+	// chunked into the re-checks.
+	for i := 0; i < 4; i++ {
+		result := h.reconcile()
+		require.Equal(t, time.Hour, result.RequeueAfter)
+		h.advance(result.RequeueAfter)
+		paused, _ = h.state()
+		require.True(t, paused)
+	}
+
+	// the shorter one is kept.
+	h.advance(30 * time.Minute)
+	result := h.reconcile()
+	require.Equal(t, 30*time.Minute, result.RequeueAfter)
+
+	// the shortened UnPausePollInterval takes effect at the next re-check.
+	h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+	h.reconcile()
+	paused, _ = h.state()
+	require.False(t, paused)
+
+	// the frozen window is capped too.
+	h.r.MaxRequeueAfter = time.Minute
+	result = h.reconcile()
+	require.Equal(t, time.Minute, result.RequeueAfter)
+}
+
+func TestRequeueJitter(t *testing.T) {
+	h := newHarness(t)
+	h.r.NotReadyRequeue = time.Minute
+	h.r.RequeueJitter = 0.2
+	h.r.RandSource = rand.NewSource(1)
+	ctx := context.Background()
+
+	// a batch of not ready ones created together.
+	requeues := make(map[time.Duration]bool)
+	for i := 0; i < 20; i++ {
+		thing := newThing(t, fmt.Sprintf("thing-%d", i))
+		setConditions(t, thing, xpv1.Creating(), xpv1.ReconcileSuccess())
+		err := h.cli.Create(ctx, thing)
+		require.Nil(t, err)
+
+		result, err := h.r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(thing)})
+		require.Nil(t, err)
+		require.GreaterOrEqual(t, result.RequeueAfter, time.Minute)
+		require.LessOrEqual(t, result.RequeueAfter, time.Minute+12*time.Second)
+		requeues[result.RequeueAfter] = true
+	}
+	// spread out.
+	require.Greater(t, len(requeues), 10)
+
+	// the capped ones are spread out below the cap.
+	h.r.MaxRequeueAfter = 30 * time.Second
+	requeues = make(map[time.Duration]bool)
+	for i := 0; i < 20; i++ {
+		result, err := h.r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: fmt.Sprintf("thing-%d", i)}})
+		require.Nil(t, err)
+		require.GreaterOrEqual(t, result.RequeueAfter, 24*time.Second)
+		require.LessOrEqual(t, result.RequeueAfter, h.r.MaxRequeueAfter)
+		requeues[result.RequeueAfter] = true
+	}
+	require.Greater(t, len(requeues), 10)
+
+	// the short one is left as is.
+	h.r.NotReadyRequeue = 500 * time.Millisecond
+	h.r.MaxRequeueAfter = 0
+	result, err := h.r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing-0"}})
+	require.Nil(t, err)
+	require.Equal(t, 500*time.Millisecond, result.RequeueAfter)
+}
+
+func TestRestoreStrippedPauseUpdated(t *testing.T) {
+	h := newHarness(t)
+	h.r.RestoreStrippedPause = true
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	h.reconcile()
+	paused, _ := h.state()
+	require.True(t, paused)
+
+	// stripped along with an update, unpause to apply it instead of restoring.
+	h.mutate(func(thing *unstructured.Unstructured) {
+		ann := thing.GetAnnotations()
+		delete(ann, AnnotationKeyReconciliationPaused)
+		thing.SetAnnotations(ann)
+		err := unstructured.SetNestedField(thing.Object, "b", "spec", "forProvider", "cidrBlock")
+		require.Nil(t, err)
+	})
+	h.reconcile()
+	paused, info := h.state()
+	require.False(t, paused)
+	require.Zero(t, info.PauseRestores)
+	require.Equal(t, UnpauseReasonUpdated, info.History[len(info.History)-1].Reason)
+}
+
+func TestMaxPauseRestoresDefault(t *testing.T) {
+	r := newThingReconciler(nil)
+	require.Equal(t, DefaultMaxPauseRestores, r.maxPauseRestores())
+	r.MaxPauseRestores = 5
+	require.Equal(t, 5, r.maxPauseRestores())
+	r.MaxPauseRestores = -1
+	require.Zero(t, r.maxPauseRestores())
+}
+
+func TestRestoreStrippedPause(t *testing.T) {
+	h := newHarness(t)
+	h.r.RestoreStrippedPause = true
+	h.r.MaxPauseRestores = 2
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	h.reconcile()
+	paused, _ := h.state()
+	require.True(t, paused)
+
+	strip := func() {
+		h.mutate(func(thing *unstructured.Unstructured) {
+			ann := thing.GetAnnotations()
+			delete(ann, AnnotationKeyReconciliationPaused)
+			thing.SetAnnotations(ann)
+		})
+	}
+
+	restored := pauseRestores.plain.WithLabelValues(testGVK.String())
+	before := testutil.ToFloat64(restored)
+
+	// dropped by crossplane, add it back.
+	for i := 1; i <= 2; i++ {
+		strip()
+		result := h.reconcile()
+		require.Zero(t, result.RequeueAfter)
+		paused, info := h.state()
+		require.True(t, paused)
+		require.True(t, info.Pause)
+		require.Equal(t, i, info.PauseRestores)
+		require.Equal(t, before+float64(i), testutil.ToFloat64(restored))
+	}
+
+	// capped, stop fighting.
+	strip()
+	h.reconcile()
+	paused, info := h.state()
+	require.False(t, paused)
+	require.False(t, info.Pause)
+	require.Zero(t, info.PauseRestores)
+	require.Equal(t, UnpauseReasonPauseRestoresExhausted, info.History[len(info.History)-1].Reason)
+	require.Equal(t, before+2, testutil.ToFloat64(restored))
+
+	// the count is reset once paused again.
+	h.advance(DefaultFrozenTimeDuration)
+	h.reconcile()
+	strip()
+	h.reconcile()
+	paused, info = h.state()
+	require.True(t, paused)
+	require.Equal(t, 1, info.PauseRestores)
+}
+
+func TestReconcileTimeout(t *testing.T) {
+	h := newHarness(t)
+	h.r.ReconcileTimeout = 50 * time.Millisecond
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	slow := true
+	h.r.PrePauseValidate = func(ctx context.Context, obj *unstructured.Unstructured) (bool, string, error) {
+		if !slow {
+			return true, "", nil
+		}
+		select {
+		case <-ctx.Done():
+			return false, "", ctx.Err()
+		case <-time.After(10 * time.Second):
+			return true, "", nil
+		}
+	}
+
+	timeouts := reconcileTimeouts.plain.WithLabelValues(testGVK.String())
+	before := testutil.ToFloat64(timeouts)
+	start := time.Now()
+	_, err = h.r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+	require.Equal(t, before+1, testutil.ToFloat64(timeouts))
+	paused, _ := h.state()
+	require.False(t, paused)
+
+	// paused once the hook is fast again.
+	slow = false
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+	require.Equal(t, before+1, testutil.ToFloat64(timeouts))
+}
@@ -0,0 +1,84 @@
+package crossplanepause
+
+import (
+	"context"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// AnnotationKeyReconcileOnce is the annotation key to let crossplane reconcile a paused resource once, the value is "true".
+// We unpause the resource, wait for crossplane to reconcile it, then pause it again and remove the annotation.
+const AnnotationKeyReconcileOnce = "cloud.pingcap.com/reconcile-once"
+
+// DefaultReconcileOnceTimeout the default max Duration we wait for crossplane to reconcile a resource once.
+const DefaultReconcileOnceTimeout = 5 * time.Minute
+
+// ReconcileOnce records a reconcile once requested by AnnotationKeyReconcileOnce in progress.
+type ReconcileOnce struct {
+	// The time we unpause the resource to reconcile it once.
+	Since metav1.Time `json:"since"`
+	// The observed generation when we unpause the resource, it's nil if there is no observed generation.
+	ObservedGeneration *int64 `json:"observedGeneration,omitempty"`
+}
+
+// isReconcileOnceRequested returns true if obj is requested to reconcile once by AnnotationKeyReconcileOnce.
+func isReconcileOnceRequested(obj *unstructured.Unstructured) bool {
+	return obj.GetAnnotations()[AnnotationKeyReconcileOnce] == "true"
+}
+
+func (r *Reconciler) reconcileOnceTimeout() time.Duration {
+	if r.ReconcileOnceTimeout > 0 {
+		return r.ReconcileOnceTimeout
+	}
+
+	return DefaultReconcileOnceTimeout
+}
+
+// newReconcileOnce returns the ReconcileOnce of obj we unpause at now.
+func (r *Reconciler) newReconcileOnce(obj *unstructured.Unstructured, now metav1.Time) (*ReconcileOnce, error) {
+	once := &ReconcileOnce{Since: now}
+	observed, ok, err := observedGeneration(obj, r.observedGenerationPath())
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		once.ObservedGeneration = &observed
+	}
+
+	return once, nil
+}
+
+// reconcileOnceDone returns true if crossplane has reconciled obj since info.ReconcileOnce, which means the observed
+// generation is bumped, the Synced condition transits to a reason other than ReconcilePaused after we unpause it, or
+// we have waited for the ReconcileOnceTimeout. Otherwise it returns how long to wait at most.
+// The Synced condition covers the resources without an observed generation, and the reconciles that don't bump it.
+func (r *Reconciler) reconcileOnceDone(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, now time.Time) (bool, time.Duration, error) {
+	once := info.ReconcileOnce
+	if once.ObservedGeneration != nil {
+		observed, ok, err := observedGeneration(obj, r.observedGenerationPath())
+		if err != nil {
+			return false, 0, err
+		}
+		if ok && observed > *once.ObservedGeneration {
+			return true, 0, nil
+		}
+	}
+
+	synced, err := r.condition(ctx, obj, xpv1.TypeSynced)
+	if err != nil {
+		return false, 0, err
+	}
+	if synced != nil && synced.Reason != xpv1.ReasonReconcilePaused && synced.LastTransitionTime.After(once.Since.Time) {
+		return true, 0, nil
+	}
+
+	deadline := once.Since.Add(r.reconcileOnceTimeout())
+	if !now.Before(deadline) {
+		return true, 0, nil
+	}
+
+	return false, deadline.Sub(now), nil
+}
@@ -0,0 +1,150 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// setConditionsAt sets u Ready and Synced, both transited at tm.
+func setConditionsAt(t *testing.T, u *unstructured.Unstructured, tm time.Time) {
+	t.Helper()
+
+	available, success := xpv1.Available(), xpv1.ReconcileSuccess()
+	available.LastTransitionTime = metav1.NewTime(tm)
+	success.LastTransitionTime = metav1.NewTime(tm)
+	setConditions(t, u, available, success)
+}
+
+func TestReconcileOnce(t *testing.T) {
+	for _, reconciled := range []string{"bump", "synced", "timeout"} {
+		h := newHarness(t)
+		thing := newThing(t, "thing")
+		setConditionsAt(t, thing, h.clock.Now())
+		err := unstructured.SetNestedField(thing.Object, int64(1), DefaultObservedGenerationPath...)
+		require.Nil(t, err)
+		err = h.cli.Create(context.Background(), thing)
+		require.Nil(t, err)
+
+		h.reconcile()
+		paused, _ := h.state()
+		require.True(t, paused)
+
+		// request to reconcile once, we unpause it.
+		h.mutate(func(thing *unstructured.Unstructured) {
+			ann := thing.GetAnnotations()
+			ann[AnnotationKeyReconcileOnce] = "true"
+			thing.SetAnnotations(ann)
+		})
+		h.advance(time.Minute)
+		h.reconcile()
+		paused, info := h.state()
+		require.False(t, paused)
+		require.Equal(t, UnpauseReasonReconcileOnce, info.History[len(info.History)-1].Reason)
+		require.Equal(t, int64(1), *info.ReconcileOnce.ObservedGeneration)
+
+		// wait for crossplane to reconcile it, the frozen window doesn't apply.
+		result := h.reconcile()
+		require.Equal(t, DefaultReconcileOnceTimeout, result.RequeueAfter)
+		paused, _ = h.state()
+		require.False(t, paused)
+
+		switch reconciled {
+		case "bump":
+			h.mutate(func(thing *unstructured.Unstructured) {
+				err := unstructured.SetNestedField(thing.Object, int64(2), DefaultObservedGenerationPath...)
+				require.Nil(t, err)
+			})
+		case "synced":
+			// reconciled without bumping the observed generation.
+			h.advance(time.Second)
+			h.mutate(func(thing *unstructured.Unstructured) {
+				setConditionsAt(t, thing, h.clock.Now())
+			})
+		case "timeout":
+			h.advance(DefaultReconcileOnceTimeout)
+		}
+
+		// pause it again and remove the command annotation.
+		h.reconcile()
+		paused, info = h.state()
+		require.True(t, paused)
+		require.True(t, info.Pause)
+		require.Nil(t, info.ReconcileOnce)
+		require.NotContains(t, getThing(t, h.cli, "thing").GetAnnotations(), AnnotationKeyReconcileOnce)
+
+		// it's idempotent, nothing happens without the annotation.
+		h.reconcile()
+		paused, _ = h.state()
+		require.True(t, paused)
+	}
+}
+
+func TestShortUnpauseOnUpdate(t *testing.T) {
+	h := newHarness(t)
+	h.r.ShortUnpauseOnUpdate = true
+	h.r.ReconcileOnceTimeout = time.Hour
+	thing := newThing(t, "thing")
+	setConditionsAt(t, thing, h.clock.Now())
+	thing.SetGeneration(1)
+	err := unstructured.SetNestedField(thing.Object, int64(1), DefaultObservedGenerationPath...)
+	require.Nil(t, err)
+	err = h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+
+	h.reconcile()
+	paused, _ := h.state()
+	require.True(t, paused)
+
+	// updated, we unpause it until crossplane reconciles it.
+	h.mutate(func(thing *unstructured.Unstructured) {
+		err := unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+		require.Nil(t, err)
+		thing.SetGeneration(2)
+	})
+	h.reconcile()
+	paused, info := h.state()
+	require.False(t, paused)
+	require.Equal(t, UnpauseReasonUpdated, info.History[len(info.History)-1].Reason)
+	require.Equal(t, int64(1), *info.ReconcileOnce.ObservedGeneration)
+
+	result := h.reconcile()
+	require.Equal(t, time.Hour, result.RequeueAfter)
+	paused, _ = h.state()
+	require.False(t, paused)
+
+	// crossplane reconciles it, we pause it again at once instead of waiting for the frozen window.
+	h.advance(time.Second)
+	h.mutate(func(thing *unstructured.Unstructured) {
+		err := unstructured.SetNestedField(thing.Object, int64(2), DefaultObservedGenerationPath...)
+		require.Nil(t, err)
+	})
+	h.reconcile()
+	paused, info = h.state()
+	require.True(t, paused)
+	require.Nil(t, info.ReconcileOnce)
+	cidrBlock, _, _ := unstructured.NestedString(info.Object.Object, "spec", "forProvider", "cidrBlock")
+	require.Equal(t, "a", cidrBlock)
+
+	// without it, we wait for the frozen window.
+	h.r.ShortUnpauseOnUpdate = false
+	h.mutate(func(thing *unstructured.Unstructured) {
+		err := unstructured.SetNestedField(thing.Object, "b", "spec", "forProvider", "cidrBlock")
+		require.Nil(t, err)
+		thing.SetGeneration(3)
+	})
+	h.reconcile()
+	h.mutate(func(thing *unstructured.Unstructured) {
+		err := unstructured.SetNestedField(thing.Object, int64(3), DefaultObservedGenerationPath...)
+		require.Nil(t, err)
+	})
+	result = h.reconcile()
+	require.Equal(t, DefaultFrozenTimeDuration, result.RequeueAfter)
+	paused, _ = h.state()
+	require.False(t, paused)
+}
@@ -3,26 +3,42 @@ package crossplanepause
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/lru"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // AnnotationKeyReconciliationPaused is the annotation key to make crossplane pause reconciling.
@@ -32,10 +48,52 @@ const AnnotationKeyReconciliationPaused = "crossplane.io/paused"
 // AnnotationKeyPauseInfo is annotation key to store pause info.
 const AnnotationKeyPauseInfo = "cloud.pingcap.com/pause-info"
 
+// AnnotationKeyPausePinned is the annotation key to pin a resource paused, the UnPausePollInterval will never unpause it.
+// It's still unpaused once it's updated or deleted.
+const AnnotationKeyPausePinned = "cloud.pingcap.com/pause-pinned"
+
+// AnnotationKeyPausedBy is the annotation key to mark the resource paused by us, the value is the name of the controller.
+// It's set along with the pause annotation and removed along with it, so we recognize the resource we paused even if
+// the pause info is removed by others.
+const AnnotationKeyPausedBy = "cloud.pingcap.com/paused-by"
+
+// AnnotationKeyFrozenDuration is the annotation key to override the FrozenTimeDuration of a resource, the value is a Go duration like "10m".
+const AnnotationKeyFrozenDuration = "cloud.pingcap.com/frozen-duration"
+
 // DefaultFrozenTimeDuration the default min Duration we will add the pause annotation again once we found the resource is updated.
 // We CAN NOT guarantee the resource will be reconciled by crossplane before adding the pause annotation again.
 const DefaultFrozenTimeDuration = 5 * time.Minute
 
+// DefaultMaxPauseRestores the default max times we add the stripped pause annotation back for a pause.
+const DefaultMaxPauseRestores = 3
+
+// MinUnPausePollIntervalFactor the UnPausePollInterval should be at least MinUnPausePollIntervalFactor times of the ProviderPollInterval,
+// otherwise we may unpause and pause the resource again before the provider polls it even once.
+const MinUnPausePollIntervalFactor = 2
+
+// MinJitteredUnPausePollInterval the UnPausePollInterval shorter than it is not jittered, the jitter is meaningless for it.
+const MinJitteredUnPausePollInterval = time.Second
+
+// FrozenTimeDurationClampDivisor the FrozenTimeDuration is clamped to 1/FrozenTimeDurationClampDivisor of the
+// UnPausePollInterval if it's not shorter than the UnPausePollInterval and ClampFrozenTimeDuration is set.
+const FrozenTimeDurationClampDivisor = 2
+
+// DefaultConditionsPath the default path of the conditions.
+var DefaultConditionsPath = []string{"status", "conditions"}
+
+// DefaultObservedGenerationPath the default path of the observed generation set by the provider once it has applied the spec.
+var DefaultObservedGenerationPath = []string{"status", "atProvider", "observedGeneration"}
+
+// statusObservedGenerationPath the path of the observed generation set by the controllers following the Kubernetes
+// convention, which we check regardless of the RequireObservedGeneration, see generationPending.
+var statusObservedGenerationPath = []string{"status", "observedGeneration"}
+
+// requiredConditionTypes the conditions must be true to pause a resource.
+var requiredConditionTypes = []xpv1.ConditionType{xpv1.TypeReady, xpv1.TypeSynced}
+
+// maxConcurrentReconciles the MaxConcurrentReconciles of the controller.
+const maxConcurrentReconciles = 10
+
 // PauseInfo the json value of AnnotationKeyPauseInfo
 type PauseInfo struct {
 	Pause           bool                       `json:"pause"`
@@ -45,6 +103,36 @@ type PauseInfo struct {
 
 	// The time we need to unpause to respect UnPausePollInterval.
 	ShouldUnpauseTime *metav1.Time `json:"shouldUnpauseTime,omitempty"`
+	// The number of UnPausePollInterval we skip unpausing in a row by SoftUnpause.
+	SkippedUnpauses int `json:"skippedUnpauses,omitempty"`
+	// The last MaxPauseHistory unpauses.
+	History []UnpauseRecord `json:"history,omitempty"`
+	// The UnPausePollInterval used to compute ShouldUnpauseTime, we shift ShouldUnpauseTime once the interval is changed.
+	UnPausePollInterval *metav1.Duration `json:"unPausePollInterval,omitempty"`
+	// The reconcile once requested by AnnotationKeyReconcileOnce in progress, we pause the resource again once it's done.
+	ReconcileOnce *ReconcileOnce `json:"reconcileOnce,omitempty"`
+	// The fingerprint of the unpaused resource when it's changed last time, for the QuiescencePeriod.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// The time the unpaused resource is changed last time, for the QuiescencePeriod.
+	LastChangeTime *metav1.Time `json:"lastChangeTime,omitempty"`
+	// The number of times we restore the pause annotation stripped by others since we paused the resource.
+	PauseRestores int `json:"pauseRestores,omitempty"`
+	// The number of the observations in a row the unpaused resource is ready to pause, for the MinSyncedObservations.
+	SyncedObservations int `json:"syncedObservations,omitempty"`
+	// The time of the last observation counted in SyncedObservations.
+	LastSyncedObservation *metav1.Time `json:"lastSyncedObservation,omitempty"`
+	// The note in the AnnotationKeyPauseNote annotation when we paused or unpaused the resource last time.
+	Note string `json:"note,omitempty"`
+	// The number of the failed deletion attempts of the deleting resource, for the MaxDeletionFailures.
+	DeletionFailures int `json:"deletionFailures,omitempty"`
+	// The time of the last failed deletion attempt counted in DeletionFailures.
+	LastDeletionFailure *metav1.Time `json:"lastDeletionFailure,omitempty"`
+	// True if the resource is paused since its deletion is stuck, it's kept paused though it's deleting.
+	DeletionStuck bool `json:"deletionStuck,omitempty"`
+	// The time in the AnnotationKeyPauseUntil annotation when we paused the resource, we unpause it at the time.
+	PauseUntil *metav1.Time `json:"pauseUntil,omitempty"`
+	// The required condition types the resource has when we paused it, to report the ones lost while it's paused.
+	Conditions []string `json:"conditions,omitempty"`
 }
 
 // Reconciler reconciles a crossplane resource to avoid keep polling by add pause annotation.
@@ -56,356 +144,1989 @@ type PauseInfo struct {
 // 1. the resource deleted.
 // 2. the resource is paused longer than UnPausePollInterval
 // 3. the spec is updated.
+// It's safe to reconcile the same resource concurrently, e.g. by the sweeper, the ExternalEvents or another replica,
+// every write is a get-modify-write guarded by the resourceVersion, and the decision is re-checked against the
+// fresh object after a conflict, so no update is lost or applied twice. There is no per-object lock in the process.
 type Reconciler struct {
 	client.Client
+	// Scheme if sets, the GroupVersionKind must be registered in it, SetupWithManager fails otherwise. The resources are
+	// reconciled as unstructured, leave it unset if the types of the provider are not at hand.
 	Scheme *runtime.Scheme
 	// The GVK of the resource we want to reconcile.
 	GroupVersionKind schema.GroupVersionKind
+	// ControllerName the name of the controller, it's in the logs and the metrics of controller-runtime.
+	// If not set, it's derived from the GroupVersionKind, so the reconcilers of different GVKs never collide.
+	ControllerName string
 	// If sets UnPausePollInterval, every UnPausePollInterval, we will unpause the resource to let
 	// crossplane to reconcile it when Ready and Sync condition are true.
 	// We will add a jitter to avoid unpause too many resources at the same time.
 	UnPausePollInterval *time.Duration
+	// UnPausePollJitter the max jitter added to the UnPausePollInterval, as a fraction of it.
+	// If not set, DefaultUnPausePollJitter will be used.
+	UnPausePollJitter *float64
+	// SoftUnpause if true, when the UnPausePollInterval is reached, we only unpause the resource if it's drifted from
+	// the one we paused, or it has been paused for ForceUnpauseEvery intervals. Otherwise we keep it paused for another interval.
+	SoftUnpause bool
+	// ForceUnpauseEvery the number of UnPausePollInterval after which we unpause the resource anyway in SoftUnpause mode.
+	// If it's not positive, the resource is never unpaused by the UnPausePollInterval in SoftUnpause mode.
+	ForceUnpauseEvery int
+	// SpecDefaults the known defaulted fields of spec, keyed by the dot separated path relative to spec like "forProvider.tenancy".
+	// A field with the default value or the zero value is considered as absent when checking if the resource is updated,
+	// so the defaulting of the provider (e.g. a conversion webhook) doesn't unpause the resource.
+	// Note nil, empty and absent maps and slices are always considered as equal.
+	SpecDefaults map[string]interface{}
+	// DisableCrossplaneSpecDefaults if true, the CrossplaneSpecDefaults like spec.deletionPolicy "Delete" are not added
+	// to the SpecDefaults, so the defaulting of them by crossplane-runtime unpauses the resource.
+	DisableCrossplaneSpecDefaults bool
+	// SetLikeSpecPaths the dot separated paths relative to spec like "forProvider.securityGroupRefs" of the lists which are
+	// semantically sets, they're compared regardless of the order when checking if the resource is updated.
+	SetLikeSpecPaths []string
+	// RespectManualPause if true, we leave the resource alone if it's paused but our pause info says we didn't pause it,
+	// which means it's paused manually after we unpaused it. Otherwise we take it over as if we paused it.
+	RespectManualPause bool
+	// AdoptManuallyPaused if true, the Ready and Synced resource paused by other guy without our pause info, e.g. paused
+	// manually before we're deployed, is adopted by writing our pause info, so it's unpaused like the ones we paused.
+	// It's held by the same gates as a fresh pause, like the SpecMatch, the rollout and the settling, and the deleting
+	// ones are never adopted. Otherwise it's left alone forever.
+	AdoptManuallyPaused bool
+	// JSONAnnotationKeys the annotation keys holding JSON values like the last-applied-config, they're compared
+	// structurally when checking if the resource is updated, so reformatting them is not an update.
+	JSONAnnotationKeys []string
+	// ShortUnpauseOnUpdate if true, once the paused resource is updated, we unpause it only until crossplane reconciles it,
+	// which is detected by the bump of the observed generation or the transition of the Synced condition like
+	// AnnotationKeyReconcileOnce, then pause it again with the fresh snapshot without waiting for the FrozenTimeDuration.
+	// If neither happens, we wait for the ReconcileOnceTimeout instead.
+	ShortUnpauseOnUpdate bool
+	// DisableUnpauseOnUpdate if true, we never unpause the resource because it's updated, only the deletion and the
+	// UnPausePollInterval unpause it. It's for the observe-only adoption which never wants crossplane to act on the drift.
+	// The SoftUnpause still unpauses the updated one once the UnPausePollInterval is reached instead of extending it.
+	// WARNING: any change of the spec will NOT be applied by crossplane until the resource is unpaused for other reasons.
+	DisableUnpauseOnUpdate bool
+	// UnpauseOnDeletion if false, we keep the resource paused once it's deleted, e.g. to prevent crossplane from keeping
+	// trying to delete a stuck external resource while an operator investigates. The resource deleted while unpaused is
+	// never paused by it. If not set, default true will be used.
+	UnpauseOnDeletion *bool
+	// MaxDeletionFailures if positive, once the deleting resource we unpaused reports MaxDeletionFailures failed
+	// deletion attempts by the Synced with ReconcileError, e.g. the external resource can't be deleted, we pause it
+	// again with a warning event for the operators to intervene, instead of letting crossplane retry it forever.
+	// It's kept paused until it's unpaused for other reasons like the AnnotationKeyReconcileOnce.
+	MaxDeletionFailures int
+	// DeletionFailureInterval the min Duration between two failed deletion attempts counted for the MaxDeletionFailures.
+	// If not set, DefaultDeletionFailureInterval will be used.
+	DeletionFailureInterval time.Duration
+	// QuiescencePeriod if sets, we pause the resource once it's unchanged for QuiescencePeriod regardless of its
+	// conditions, for the users who don't trust the conditions of the provider. Any change of the spec, the status or
+	// the metadata like the labels restarts the period.
+	QuiescencePeriod time.Duration
+	// StabilityWindow if sets, we pause the resource only once its Ready and Synced conditions have not transitioned for
+	// StabilityWindow, so the resource flapping between the states is not paused right after it becomes ready.
+	StabilityWindow time.Duration
+	// MinResourceAge if sets, we pause the resource only once it's created for MinResourceAge, it's combined with the
+	// StabilityWindow by the SettlingPolicy, the resource is paused once both are satisfied. They don't apply with
+	// the QuiescencePeriod, which is a settling gate itself.
+	MinResourceAge time.Duration
+	// MinSyncedObservations if sets, we pause the resource only once it's observed ready to pause for MinSyncedObservations
+	// reconciles in a row, which are at least SyncedObservationInterval apart, for the resources whose Synced flaps
+	// between true and false every provider poll. Any observation of a false Synced resets the count.
+	// It doesn't apply with the QuiescencePeriod.
+	MinSyncedObservations int
+	// SyncedObservationInterval the min Duration between two observations counted for the MinSyncedObservations.
+	// If not set, DefaultSyncedObservationInterval will be used.
+	SyncedObservationInterval time.Duration
+	// SyncedOptional if true, the resource is paused on Ready alone unless its Synced condition is false, for the resources
+	// which reach Ready but legitimately never reach Synced like the read-only observations, they're polled forever otherwise.
+	SyncedOptional bool
+	// TreatMissingSyncedAsTrue if true, a missing Synced condition is considered as true, for the providers which never
+	// emit it. Unlike SyncedOptional, a present Synced condition still blocks pausing unless it's true.
+	TreatMissingSyncedAsTrue bool
+	// BlockingConditionReasons the reasons of the Ready and Synced which block pausing even though they're true, e.g. a
+	// provider reports Ready with a warning reason while the external resource is still being modified.
+	BlockingConditionReasons []xpv1.ConditionReason
+	// WatchFinalizers if true, adding or removing a finalizer of a paused resource is considered as an update,
+	// reordering the finalizers is not.
+	// Deprecated: use MetadataWatch.WatchFinalizers instead, it's ignored if MetadataWatch is set.
+	WatchFinalizers bool
+	// WatchConditionChanges if true, a change of the conditions of a paused resource is considered as an update, like a
+	// flipped Ready or a new condition type, since it means crossplane detected something. Only the type, the status and
+	// the reason are compared, and the Synced reporting the reconcile is paused is ignored.
+	WatchConditionChanges bool
+	// MetadataWatch if sets, it configures which fields of the metadata are considered when checking if the paused
+	// resource is updated. If not set, the labels and the annotations are considered, and the finalizers if
+	// WatchFinalizers is true.
+	MetadataWatch *MetadataWatch
+	// SpecEqual if sets, it replaces the default comparison of the spec when checking if the resource is updated, to express
+	// the domain-specific equivalences like two CIDR notations that are equal. The specs passed to it are normalized and
+	// the SpecDefaults are removed, it must not modify them.
+	SpecEqual func(old, now map[string]interface{}) (bool, error)
+	// SnapshotTransform if sets, it transforms the snapshot of the resource stored in PauseInfo.Object, e.g. to redact a
+	// sensitive field or strip a large one. It's applied to the live resource as well before checking if it's updated,
+	// so a field it strips is not watched. It's passed a copy it may modify in place, and must be idempotent since it's
+	// applied to the stored snapshot again.
+	SnapshotTransform func(obj *unstructured.Unstructured) *unstructured.Unstructured
 	// FrozenTimeDuration the min Duration we will add the pause annotation again once we found the resource is updated.
 	// We CAN NOT guarantee the resource will be reconciled by crossplane before adding the pause annotation again.
 	// If not set, default 5 minutes will be used.
+	// It should be shorter than the UnPausePollInterval, or the resource unpaused by the UnPausePollInterval stays
+	// unpaused for the FrozenTimeDuration, longer than it's paused, see ClampFrozenTimeDuration.
 	FrozenTimeDuration *time.Duration
+	// ClampFrozenTimeDuration if true, we will lower the FrozenTimeDuration to 1/FrozenTimeDurationClampDivisor of the
+	// UnPausePollInterval if it's not shorter than the UnPausePollInterval. Only the fields are checked, not the
+	// settings reloaded from the SettingsConfigMap.
+	ClampFrozenTimeDuration bool
+	// NotReadyRequeue if sets, we will requeue the resource after NotReadyRequeue when it's not Ready and Synced yet,
+	// instead of relying on the watch to trigger the reconcile once the conditions change.
+	NotReadyRequeue time.Duration
+	// VerifyPauseRequeue if sets, we will requeue the resource after VerifyPauseRequeue once we pause it, to verify the
+	// pause annotation is still there, in case crossplane's in-flight reconcile stripped it. If it's stripped, we add it
+	// back if the resource is still Ready and Synced and not updated, otherwise we unpause it. It's only verified until
+	// another VerifyPauseRequeue after the requeue is due, the pause stripped later is left to RestoreStrippedPause.
+	VerifyPauseRequeue time.Duration
+	// RestoreStrippedPause if true, we add the pause annotation back once it's stripped by others like VerifyPauseRequeue,
+	// e.g. by an in-flight reconcile of crossplane racing with us, but only on the event of the resource instead of requeuing.
+	RestoreStrippedPause bool
+	// MaxPauseRestores we add the stripped pause annotation back at most MaxPauseRestores times for a pause, then unpause
+	// the resource instead of fighting the actor which keeps unpausing it. DefaultMaxPauseRestores if not set, a negative
+	// value for no limit.
+	MaxPauseRestores int
+	// ProviderPollInterval is a hint of the --poll-interval of the crossplane provider, we will log a warning
+	// if UnPausePollInterval is not at least MinUnPausePollIntervalFactor times of it.
+	ProviderPollInterval time.Duration
+	// ClampUnPausePollInterval if true, we will raise the UnPausePollInterval to MinUnPausePollIntervalFactor times
+	// of the ProviderPollInterval if it's shorter than that.
+	ClampUnPausePollInterval bool
+	// RequireObservedGeneration if true, we will pause the resource only if the observed generation at ObservedGenerationPath
+	// is not less than metadata.generation, which means the provider has fully applied the current spec.
+	RequireObservedGeneration bool
+	// ObservedGenerationPath the path of the observed generation, if not set, DefaultObservedGenerationPath will be used.
+	ObservedGenerationPath []string
+	// ConditionsPath the path of the conditions, if not set, DefaultConditionsPath will be used.
+	ConditionsPath []string
+	// FallbackConditionsPath if sets, the conditions are read from it if there is none at the ConditionsPath, e.g. the
+	// provider is migrating the conditions between the paths.
+	FallbackConditionsPath []string
+	// DisableGenerationGuard if true, we may pause the resource whose status.observedGeneration is behind its
+	// metadata.generation, whose conditions may be left from the previous generation. The guard only applies to the
+	// resources having status.observedGeneration, unlike the opt-in RequireObservedGeneration.
+	DisableGenerationGuard bool
+	// PauseInfoAnnotationKey the annotation key to store the pause info, if not set, AnnotationKeyPauseInfo will be used.
+	PauseInfoAnnotationKey string
+	// PauseInfoOnOwner if true, the pause info of the paused resource is stored in the AnnotationKeyOwnedPauseInfo annotation
+	// of its controller owner for the auditability, e.g. on the composite of a claim, while the paused annotation stays on
+	// the resource since crossplane reads it there. The unpaused resources and the ones without a controller owner keep
+	// the pause info themselves, and the entries of the gone resources are pruned once the owner is written.
+	// Note the resource is written before the owner, they're not updated atomically.
+	PauseInfoOnOwner bool
+	// LegacyPauseInfoAnnotationKeys the annotation keys used to store the pause info before changing the PauseInfoAnnotationKey.
+	// We read the pause info from them if it's missing in the PauseInfoAnnotationKey, and remove them once we write the pause info
+	// to the PauseInfoAnnotationKey.
+	LegacyPauseInfoAnnotationKeys []string
+	// RolloutPercentage if sets, only the percentage (0-100) of the resources are paused, the others are left polling.
+	// Whether a resource is in the rollout is decided by a stable hash of its UID, so raising it only adds resources.
+	RolloutPercentage *int
+	// OnboardAfterAge if sets, only the resources created for OnboardAfterAge are paused, so the oldest and most stable
+	// resources are onboarded first, lower it step by step to roll out to the younger ones. Unlike the MinResourceAge,
+	// which is how long every resource settles, it's a rollout control meant to be lowered to 0 eventually.
+	OnboardAfterAge time.Duration
+	// IncludeNames if not empty, only the resources whose names match any of the glob patterns like "prod-*" are reconciled.
+	IncludeNames []string
+	// ExcludeNames the resources whose names match any of the glob patterns are never reconciled, it takes precedence
+	// over the IncludeNames. The resources paused before being excluded are unpaused by the sweeper, see SweepInterval.
+	ExcludeNames []string
+	// Namespaces if not empty, only the resources in these namespaces are reconciled.
+	// The resources paused before being filtered out are unpaused by the sweeper, see SweepInterval.
+	Namespaces []string
+	// LabelSelector if sets, only the resources matching it are reconciled.
+	// The resources paused before being filtered out are unpaused by the sweeper, see SweepInterval.
+	LabelSelector *metav1.LabelSelector
+	// SpecMatch if sets, only the resources whose spec it returns true for are paused, e.g. the instance type is in a
+	// set or a tag is present, the resources paused before not matching are unpaused. The spec must not be modified.
+	SpecMatch func(spec map[string]interface{}) bool
+	// Predicates filter the events of the resources to reconcile, they're ANDed with the predicates passed to SetupWithManager.
+	Predicates []predicate.Predicate
+	// SettingsConfigMap if sets, we watch the ConfigMap and reload the UnPausePollInterval, FrozenTimeDuration and
+	// UnPausePollJitter from it without restarting, see the ConfigMapKey* for the keys. The missing keys fall back to the fields.
+	// Only the ConfigMap itself is cached.
+	SettingsConfigMap *types.NamespacedName
+	// ProviderDeployment if sets, we watch the Deployment of the provider and take its rollouts as the restarts of the
+	// provider, see ProviderRestarted. No resource is paused while it rolls out, and the cooldown starts once the rollout
+	// completes. Only the Deployment itself is cached, scaling it is not a rollout.
+	ProviderDeployment *types.NamespacedName
+	// ProviderRestartCooldown the Duration we don't pause any resource after the provider restarted, since it may report
+	// the stale Ready and Synced before reconciling the resources again. If not set, DefaultProviderRestartCooldown will be used.
+	ProviderRestartCooldown time.Duration
+	// ExternalEvents if sets, the objects sent to it by the external code are enqueued to reconcile, they run through
+	// the normal pause/unpause logic. Only the namespace and name of the object are used, e.g.
+	//	events <- event.GenericEvent{Object: &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "name"}}}
+	ExternalEvents <-chan event.GenericEvent
+	// SelfWriteCacheSize if positive, we remember the resourceVersions superseded by our own writes of the last
+	// SelfWriteCacheSize resources, and skip reconciling the stale copy of them which we have acted on, e.g. the
+	// requeue before the cache catches up with our write. It's only kept in memory, everything is reconciled as
+	// usual after restarting.
+	SelfWriteCacheSize int
+	// ReconcileCounterSize if positive, we count the reconciles of the last ReconcileCounterSize resources since their
+	// last write by anyone, and observe it in the crossplane_pause_reconciles_since_write metric, to find the hot
+	// resources which reconcile frequently without changing anything, the candidates for longer intervals.
+	// It's only kept in memory.
+	ReconcileCounterSize int
+	// NamespaceMetricLabel if true, the metrics of the resources like the transitions are counted by their namespace as
+	// well in the namespaced twins like crossplane_pause_namespaced_transitions_total for the per-tenant breakdowns, the
+	// labels of the existing metrics are never changed. Keep it false for the clusters of many namespaces to bound the
+	// cardinality of the metrics.
+	NamespaceMetricLabel bool
+	// UpdateFailureThreshold if positive, once the update of a resource fails UpdateFailureThreshold times in a row in
+	// the UpdateFailureWindow, e.g. a webhook keeps rejecting it, we emit a warning event and requeue it after the
+	// UpdateFailureBackoff instead of the rate limited requeue.
+	UpdateFailureThreshold int
+	// UpdateFailureWindow if not set, DefaultUpdateFailureWindow will be used.
+	UpdateFailureWindow time.Duration
+	// UpdateFailureBackoff if not set, DefaultUpdateFailureBackoff will be used.
+	UpdateFailureBackoff time.Duration
+	// AuditSink if sets, every pause and unpause is recorded to it after the resource is updated, see HTTPAuditSink.
+	AuditSink AuditSink
+	// AuditFatal if true, the reconcile fails if the AuditSink fails, otherwise the failure is only logged.
+	// Note the pause or unpause is not rolled back either way.
+	AuditFatal bool
+	// Recorder records the events of the resources, if not set, the one of the manager named EventRecorderName will be used.
+	Recorder record.EventRecorder
+	// PauseBudget if sets, limits the rate of pausing the resources to smooth the writes to the API server, e.g. when
+	// a burst of resources become ready after starting. The resources out of the budget are requeued until there is
+	// a token for them. It may be shared by the reconcilers of different GVKs to limit the total rate, e.g.
+	//	rate.NewLimiter(rate.Every(100*time.Millisecond), 10)
+	// It only limits pausing, unpausing is never delayed.
+	PauseBudget *rate.Limiter
+	// ReconcileOnceTimeout the max Duration we wait for crossplane to reconcile a resource requested by AnnotationKeyReconcileOnce,
+	// before pausing it again. If not set, DefaultReconcileOnceTimeout will be used.
+	ReconcileOnceTimeout time.Duration
+	// CascadeToResourceRefs if true, the GroupVersionKind is a composite resource, we pause the composed resources in its
+	// spec.resourceRefs along with it and unpause them along with it. The composed resources are paused based solely on
+	// the Ready and Synced of the composite, which aggregate the health of them, their own conditions are not checked.
+	// They carry the ShouldUnpauseTime of the composite instead of their own snapshots, and only the ones paused by the
+	// cascade are unpaused along with it.
+	CascadeToResourceRefs bool
+	// ResourceRefsConcurrency the max number of the composed resources paused or unpaused concurrently along with their
+	// composite, see CascadeToResourceRefs. If not set, DefaultResourceRefsConcurrency will be used.
+	ResourceRefsConcurrency int
+	// PrePauseValidate if sets, it's called right before pausing a resource which is ready to pause otherwise, to run
+	// a live check like querying the cloud provider that the resource is really settled. The resource is not paused
+	// if it returns false with the reason or an error, and requeued after PrePauseRequeue.
+	PrePauseValidate func(ctx context.Context, obj *unstructured.Unstructured) (ok bool, reason string, err error)
+	// PrePauseRequeue the Duration we requeue the resource after once PrePauseValidate vetoes pausing it.
+	// If not set, DefaultPrePauseRequeue will be used.
+	PrePauseRequeue time.Duration
+	// ReadinessProbe if sets, it's consulted before pausing a resource in addition to the Ready and Synced, or instead
+	// of them if ReadinessProbeOnly is true. The resource is not paused if it reports not ready or fails, and requeued
+	// after ReadinessProbeRequeue.
+	ReadinessProbe ReadinessProbe
+	// ReadinessProbeOnly if true, the conditions of the resources are not checked, the ReadinessProbe decides alone
+	// whether a resource is ready to pause, including restoring the stripped pause and extending the pause by the
+	// SoftUnpause. It's ignored if the ReadinessProbe is not set.
+	ReadinessProbeOnly bool
+	// ReadinessProbeRequeue the Duration we requeue the resource after once the ReadinessProbe reports it's not ready.
+	// If not set, DefaultReadinessProbeRequeue will be used.
+	ReadinessProbeRequeue time.Duration
+	// Mode the operating mode, if not set, ModeEnforce will be used. In ModeObserve the resources are never paused, the
+	// ones which would be paused are reported instead, to see the effect before enforcing it.
+	Mode Mode
+	// RecordWouldPause if true, the AnnotationKeyWouldPause annotation is set on the resources which would be paused in ModeObserve.
+	RecordWouldPause bool
+	// StatusPauseState if true, we write the PauseState to status.pauseState by the status subresource once we pause or
+	// unpause the resource, for the GitOps tools to display. The schema of the CRD must preserve the field, otherwise
+	// the API server prunes it. It's never considered when checking if the resource is updated.
+	StatusPauseState bool
+	// MaxRequeueAfter if sets, the RequeueAfter longer than it is capped to it, so a long wait like the UnPausePollInterval
+	// is chunked into the periodic re-checks, letting the changes of the settings and the clock take effect sooner.
+	MaxRequeueAfter time.Duration
+	// RequeueJitter if positive, a random jitter up to the fraction of the RequeueAfter is added to every RequeueAfter we
+	// return, e.g. 0.1, so the resources which transitioned together don't requeue together like a reconcile storm.
+	// The jittered RequeueAfter is still capped by the MaxRequeueAfter, the capped ones are jittered down from it, and the
+	// ones shorter than a second are left as is.
+	RequeueJitter float64
+	// OnReconcile if sets, it's called at the end of every Reconcile with the action taken and the returned result and
+	// error, e.g. to observe the outcomes in the integration tests without parsing the logs. It must not block.
+	OnReconcile func(req ctrl.Request, action Action, res ctrl.Result, err error)
+	// ReconcileTimeout if sets, the whole Reconcile including the hooks like PrePauseValidate runs with a context of
+	// the deadline, once exceeded an error is returned so the resource is requeued, instead of tying up a worker.
+	ReconcileTimeout time.Duration
+	// ConsolidatedLog if true, the "Finish reconcile" log is folded into the "reconcile decision" log with the duration,
+	// and the "Start reconcile" log is emitted only at the verbosity 1, so there is one log per reconcile by default.
+	ConsolidatedLog bool
+	// UseDefaultConcurrency if true, we leave the MaxConcurrentReconciles of the controller unset to inherit the default
+	// of controller-runtime, instead of maxConcurrentReconciles.
+	UseDefaultConcurrency bool
+	// ConcurrencyFunc if sets, the MaxConcurrentReconciles of the controller is computed by it from the number of the
+	// resources of the GroupVersionKind counted in SetupWithManager, clamped to [MinScaledConcurrency,
+	// MaxScaledConcurrency], see ConcurrencyPerObjects. It overrides the UseDefaultConcurrency.
+	ConcurrencyFunc func(count int) int
+	// MinScaledConcurrency the min of the MaxConcurrentReconciles computed by the ConcurrencyFunc, 1 if not set.
+	MinScaledConcurrency int
+	// MaxScaledConcurrency the max of the MaxConcurrentReconciles computed by the ConcurrencyFunc.
+	// If not set, DefaultMaxScaledConcurrency will be used.
+	MaxScaledConcurrency int
+	// SweepInterval if sets, a sweeper runs every SweepInterval to unpause the resources paused by us but not selected
+	// by the IncludeNames, ExcludeNames, Namespaces and LabelSelector now, which would be paused forever otherwise.
+	// The Predicates are not considered since they filter the events rather than the resources, so select the resources
+	// by the fields above to have them swept. It only runs in the leader if the leader election of the manager is enabled.
+	SweepInterval time.Duration
+	// Clock the clock to decide the pause and unpause, it's for testing. If not set, the real clock will be used.
+	Clock clock.PassiveClock
+	// RandSource the source of the jitter added to the UnPausePollInterval and the RequeueAfter, it's for testing.
+	// If not set, a source seeded from the current time will be used.
+	RandSource rand.Source
+
+	// scaledConcurrency the MaxConcurrentReconciles computed by the ConcurrencyFunc, see scaleConcurrency.
+	scaledConcurrency int
+	// reloaded the *settings reloaded from the SettingsConfigMap.
+	reloaded atomic.Value
+	// settingsReader reads the SettingsConfigMap from the cache of it alone, the Client is used if it's not set.
+	settingsReader client.Reader
+	// providerRestart the time.Time the provider restarted last time, see ProviderRestarted.
+	providerRestart atomic.Value
+	// providerRollingOut true if the ProviderDeployment is rolling out, the cooldown starts once it completes.
+	providerRollingOut atomic.Bool
+	// frozenWindows the LastUnPauseTime of the resources we have recorded the frozen window event for, see recordFrozenWindow.
+	frozenWindows sync.Map
+	// gvkMetrics the metrics of the GroupVersionKind, see metrics.
+	gvkMetrics     *gvkMetrics
+	gvkMetricsOnce sync.Once
+	// updateFailures the consecutive update failures of the resources, see recordUpdateResult.
+	updateFailures   map[types.NamespacedName]*updateFailures
+	updateFailuresMu sync.Mutex
+	// selfWrites the resourceVersions superseded by our own writes, see selfWriteCache.
+	selfWrites     *lru.Cache
+	selfWritesOnce sync.Once
+	// reconcileCounts the reconciles of the resources since their last write, see countReconcile.
+	reconcileCounts     *lru.Cache
+	reconcileCountsOnce sync.Once
+	// scope the scope of the GroupVersionKind from the REST mapper, it's empty before SetupWithManager.
+	scope meta.RESTScopeName
+
+	// rand the random generator of RandSource, it's not safe for concurrent use so guarded by randMu, see random.
+	rand     *rand.Rand
+	randMu   sync.Mutex
+	randOnce sync.Once
+
+	// resync the channel to trigger enqueuing all the resources, see Resync.
+	resync     chan event.GenericEvent
+	resyncOnce sync.Once
+
+	// backgrounds the background components run along with the manager, see addBackground.
+	backgrounds []manager.Runnable
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
-	logger.Info("Start reconcile")
-
 	start := time.Now()
-	defer func() {
-		logger.Info("Finish reconcile", "take", time.Since(start))
-	}()
+	if r.ConsolidatedLog {
+		logger.V(1).Info("Start reconcile")
+	} else {
+		logger.Info("Start reconcile")
+		defer func() {
+			logger.Info("Finish reconcile", "take", time.Since(start))
+		}()
+	}
+
+	var (
+		d      decision
+		result ctrl.Result
+		err    error
+	)
+	if r.OnReconcile != nil {
+		defer func() {
+			r.OnReconcile(req, d.action, result, err)
+		}()
+	}
+
+	if r.ReconcileTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.ReconcileTimeout)
+		defer cancel()
+	}
+
+	req.NamespacedName = r.objectKey(req.NamespacedName)
+	d, result, err = r.reconcile(ctx, req)
+	if r.observing() && err == nil {
+		r.observeDecision(ctx, req.NamespacedName, d)
+	}
+	if r.ReconcileTimeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		r.metrics().inc(reconcileTimeouts, req.Namespace)
+		// The result may be made of the failure of a hook or a client call by the deadline, retry it.
+		result, err = ctrl.Result{}, fmt.Errorf("reconcile timed out after %s: %w", r.ReconcileTimeout, ctx.Err())
+	}
+	if err != nil && r.isUpdateFailing(req.NamespacedName) {
+		// Back off instead of the rate limited requeue, which retries in seconds.
+		logger.Error(err, "update keeps failing, back off", "after", r.updateFailureBackoff().String())
+		result, err = ctrl.Result{RequeueAfter: r.updateFailureBackoff()}, nil
+	}
+	result.RequeueAfter += r.jitter(result.RequeueAfter, r.RequeueJitter)
+	// Re-check periodically instead of holding a long timer, so the changes of the settings take effect sooner.
+	// Jittered down from the cap, or the capped ones requeue together again.
+	if r.MaxRequeueAfter > 0 && result.RequeueAfter > r.MaxRequeueAfter {
+		result.RequeueAfter = r.MaxRequeueAfter - r.jitter(r.MaxRequeueAfter, r.RequeueJitter)
+	}
+	keysAndValues := []interface{}{"action", d.action, "reason", d.reason}
+	if result.RequeueAfter > 0 {
+		keysAndValues = append(keysAndValues, "requeueAfter", result.RequeueAfter.String())
+	}
+	if err != nil {
+		keysAndValues = append(keysAndValues, "error", err.Error())
+	}
+	if r.ConsolidatedLog {
+		keysAndValues = append(keysAndValues, "take", time.Since(start))
+	}
+	logger.Info("reconcile decision", keysAndValues...)
+
+	return result, err
+}
+
+// reconcile decides and takes the action for the resource of req.
+func (r *Reconciler) reconcile(ctx context.Context, req ctrl.Request) (decision, ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// The predicate filters them out too, but the ExternalEvents and the reloading of the settings enqueue all.
+	if !r.nameAllowed(req.Name) {
+		return decision{action: ActionIgnore, reason: "name excluded"}, ctrl.Result{}, nil
+	}
+	if !r.namespaceAllowed(req.Namespace) {
+		return decision{action: ActionIgnore, reason: "namespace excluded"}, ctrl.Result{}, nil
+	}
+
+	// The ExternalEvents may miss it.
+	if r.scope == meta.RESTScopeNameNamespace && req.Namespace == "" {
+		return decision{action: ActionIgnore, reason: "missing namespace"}, ctrl.Result{}, nil
+	}
 
 	var obj = new(unstructured.Unstructured)
 	obj.SetGroupVersionKind(r.GroupVersionKind)
-	err = r.Client.Get(ctx, req.NamespacedName, obj)
+	err := r.kube().Get(ctx, req.NamespacedName, obj)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			return ctrl.Result{}, nil
+			r.forgetFrozenWindow(req.NamespacedName)
+			r.forgetUpdateFailures(req.NamespacedName)
+			return decision{action: ActionNone, reason: "not found"}, ctrl.Result{}, nil
 		}
-		return ctrl.Result{}, fmt.Errorf("unable to get object %s: %w", req.NamespacedName, err)
+		return decision{action: ActionNone, reason: "get failed"}, ctrl.Result{}, fmt.Errorf("unable to get object %s: %w", req.NamespacedName, err)
 	}
 
-	ann := obj.GetAnnotations()
-	pauseValue, _ := ann[AnnotationKeyReconciliationPaused]
+	if !r.selected(obj) {
+		return decision{action: ActionIgnore, reason: "not selected"}, ctrl.Result{}, nil
+	}
 
-	info, err := parsePauseInfo(obj)
-	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to parse pause info: %w", err)
+	r.countReconcile(obj)
+
+	if r.isSuperseded(obj) {
+		logger.Info("skip the stale object superseded by our own write", "resourceVersion", obj.GetResourceVersion())
+		return decision{action: ActionNone, reason: "superseded by our own write"}, ctrl.Result{}, nil
 	}
 
-	// We add pause ann and info ann both.
-	// in case the pause ann is added by other guy we just ignore this resource.
-	if isPaused(pauseValue) && info == nil {
-		logger.Info("ignore paused by other guy")
-		return ctrl.Result{}, nil
+	info, err := r.parsePauseInfo(obj)
+	if err != nil {
+		return decision{action: ActionNone, reason: "malformed pause info"}, ctrl.Result{}, fmt.Errorf("unable to parse pause info: %w", err)
 	}
 
+	now := r.now()
+	d, err := r.decide(ctx, obj, info, now)
+	if err != nil {
+		return decision{action: ActionNone, reason: "decide failed"}, ctrl.Result{}, err
+	}
+	if d.reason != reasonFrozenWindow {
+		r.forgetFrozenWindow(req.NamespacedName)
+	}
 	// We never pause this resource yet, so missing the info annotation.
 	if info == nil {
-		info = &PauseInfo{
-			Pause: false,
-		}
+		info = new(PauseInfo)
 	}
 
-	// Never pause the deleted resource.
-	if !obj.GetDeletionTimestamp().IsZero() {
-		err := ensureUnPause(ctx, r.Client, obj, info, "resource deleted")
+	if d.record != nil {
+		err := r.recordObservation(ctx, obj, info, d.record)
 		if err != nil {
-			return ctrl.Result{}, fmt.Errorf("unable to unpause: %w", err)
+			return decision{action: ActionKeepUnpaused, reason: "record the observation"}, ctrl.Result{}, err
 		}
 	}
 
-	if info.Pause {
-		updated, err := isUpdated(ctx, obj, info.Object)
+	return r.act(ctx, obj, info, d, now)
+}
+
+// act takes the action of the decision d for obj with its pause info at now.
+func (r *Reconciler) act(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, d decision, now time.Time) (decision, ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// The ReadinessProbe replaces the conditions decide checked to keep the resource paused.
+	if (d.action == ActionRestorePause || d.action == ActionExtendPause) && !r.readyByProbe(ctx, obj) {
+		reason := UnpauseReasonPauseStripped
+		if d.action == ActionExtendPause {
+			reason = UnpauseReasonPollInterval
+		}
+		d = decision{action: ActionUnpause, reason: string(reason)}
+	}
+
+	switch d.action {
+	case ActionUnpause:
+		reason := UnpauseReason(d.reason)
+		if reason == UnpauseReasonPauseInfoLost {
+			info = &PauseInfo{Pause: true}
+		}
+		err := r.ensureUnPause(ctx, obj, info, reason)
 		if err != nil {
-			return ctrl.Result{}, fmt.Errorf("unable to check if updated: %w", err)
+			return d, ctrl.Result{}, fmt.Errorf("unable to unpause: %w", err)
 		}
+		// will trigger enqueue again since we update annotation in ensureUnPause().
+		// and run into the info.Pause = false case
+		return d, ctrl.Result{}, nil
 
-		if updated {
-			err := ensureUnPause(ctx, r.Client, obj, info, "resource, updated")
-			if err != nil {
-				return ctrl.Result{}, fmt.Errorf("unable to unpause: %w", err)
-			}
-			// will trigger enqueue again since we update annotation in ensureUnPause().
-			// and run into the info.Pause = false case
-			return ctrl.Result{}, nil
+	case ActionRestorePause:
+		err := r.restorePause(ctx, obj, info)
+		if err != nil {
+			return d, ctrl.Result{}, fmt.Errorf("unable to restore pause: %w", err)
 		}
+		return d, ctrl.Result{RequeueAfter: d.after}, nil
 
-		if r.UnPausePollInterval != nil {
-			now := time.Now()
-			shouldUnpauseTime := info.LastPauseTime.Add(*r.UnPausePollInterval)
-			if info.ShouldUnpauseTime != nil {
-				shouldUnpauseTime = info.ShouldUnpauseTime.Time
+	case ActionKeepPaused, ActionExtendPause:
+		if r.needMigrate(obj, info) {
+			err := r.migratePauseInfo(ctx, obj, info)
+			if err != nil {
+				return decision{action: ActionKeepPaused, reason: "migrate pause info"}, ctrl.Result{}, fmt.Errorf("unable to migrate pause info: %w", err)
 			}
-
-			if now.Before(shouldUnpauseTime) {
-				logger.Info("requque after to check if should unpause by UnPausePollInterval", "after", shouldUnpauseTime.Sub(now).String())
-				return ctrl.Result{RequeueAfter: shouldUnpauseTime.Sub(now)}, nil
+		} else if !r.DisableUnpauseOnUpdate && r.isSnapshotStale(obj, info) {
+			err := r.refreshSnapshot(ctx, obj, info)
+			if err != nil {
+				return decision{action: ActionKeepPaused, reason: "refresh snapshot"}, ctrl.Result{}, fmt.Errorf("unable to refresh snapshot: %w", err)
 			}
+		}
+		if d.action == ActionKeepPaused {
+			return d, ctrl.Result{RequeueAfter: d.after}, nil
+		}
 
-			err := ensureUnPause(ctx, r.Client, obj, info, "resource trigger unPause poll interval")
+		err := r.extendPause(ctx, obj, info)
+		if err != nil {
+			return d, ctrl.Result{}, fmt.Errorf("unable to extend pause: %w", err)
+		}
+		after := info.ShouldUnpauseTime.Sub(now)
+		logger.Info("keep pause since not drifted", "skippedUnpauses", info.SkippedUnpauses, "after", after.String())
+		return d, ctrl.Result{RequeueAfter: after}, nil
+
+	case ActionKeepUnpaused:
+		switch d.reason {
+		case reasonFrozenWindow:
+			r.recordFrozenWindow(obj, info.LastUnPauseTime.Time, now.Add(d.after))
+		case settlingReasonTooYoung, settlingReasonUnstable:
+			r.deferPause(ctx, obj, d.after, d.reason)
+		case reasonPauseUntilPassed:
+			// will trigger enqueue again since we update annotation in clearPauseUntil().
+			err := r.clearPauseUntil(ctx, obj)
 			if err != nil {
-				return ctrl.Result{}, fmt.Errorf("unable to unpause: %w", err)
+				return d, ctrl.Result{}, fmt.Errorf("unable to clear pause until: %w", err)
 			}
-			return ctrl.Result{}, nil
 		}
+		return d, ctrl.Result{RequeueAfter: d.after}, nil
 
-		logger.Info("keep pause")
-		return ctrl.Result{}, nil
+	case ActionPause, ActionWouldPause:
+		return r.pause(ctx, obj, info, d, now)
 	}
 
-	// start to handle info.Pause == false case.
-	now := time.Now()
-	if info.LastUnPauseTime != nil && info.LastUnPauseTime.Add(*r.FrozenTimeDuration).After(now) {
-		after := info.LastUnPauseTime.Add(*r.FrozenTimeDuration).Sub(now)
-		logger.Info("keep unpause in frozen time duration", "checkAfter", after.String())
-		return ctrl.Result{RequeueAfter: after}, nil
+	return d, ctrl.Result{RequeueAfter: d.after}, nil
+}
+
+// pause pauses obj on the decision d, or observes it in ModeObserve, once the hooks which may have side effects allow.
+func (r *Reconciler) pause(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, d decision, now time.Time) (decision, ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// The stuck deletion is paused whatever the hooks say.
+	if d.reason == PauseReasonDeletionStuck {
+		failure, err := r.deletionFailure(ctx, obj)
+		if err != nil {
+			return decision{action: ActionNone, reason: "malformed conditions"}, ctrl.Result{}, err
+		}
+		// Recovered since we recorded it, let the next reconcile handle it.
+		if failure == nil {
+			return decision{action: ActionKeepUnpaused, reason: reasonDeleting}, ctrl.Result{}, nil
+		}
+		err = r.pauseStuckDeletion(ctx, obj, info, failure)
+		if err != nil {
+			return d, ctrl.Result{}, fmt.Errorf("unable to pause: %w", err)
+		}
+		return d, ctrl.Result{}, nil
 	}
 
-	readyCondition, err := getCondition(obj, xpv1.TypeReady)
-	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to get ready condition: %w", err)
+	if ready, reason := r.probeReady(ctx, obj); !ready {
+		logger.V(1).Info("not pause since not ready by the readiness probe", "reason", reason)
+		return decision{action: ActionKeepUnpaused, reason: reason}, ctrl.Result{RequeueAfter: r.readinessProbeRequeue()}, nil
 	}
 
-	if readyCondition == nil || readyCondition.Status != corev1.ConditionTrue {
-		return ctrl.Result{}, nil
+	// Validate before taking the pause budget, a vetoed resource should not spend it.
+	if ok, reason := r.prePauseValidate(ctx, obj); !ok {
+		logger.Info("not pause since vetoed", "reason", reason)
+		return decision{action: ActionKeepUnpaused, reason: reason}, ctrl.Result{RequeueAfter: r.prePauseRequeue()}, nil
 	}
 
-	syncedCondition, err := getCondition(obj, xpv1.TypeSynced)
+	delay, err := r.pauseBudgetDelay(now)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to get synced condition: %w", err)
+		return decision{action: ActionKeepUnpaused, reason: "invalid pause budget"}, ctrl.Result{}, err
+	}
+	if delay > 0 {
+		logger.V(1).Info("requeue since out of the pause budget", "after", delay.String())
+		return decision{action: ActionKeepUnpaused, reason: "out of the pause budget"}, ctrl.Result{RequeueAfter: delay}, nil
 	}
 
-	if syncedCondition == nil || syncedCondition.Status != corev1.ConditionTrue {
-		return ctrl.Result{}, nil
+	if d.action == ActionWouldPause {
+		err := r.observePause(ctx, obj, d.reason)
+		if err != nil {
+			return d, ctrl.Result{}, fmt.Errorf("unable to observe pause: %w", err)
+		}
+		return d, ctrl.Result{}, nil
 	}
 
-	err = ensurePause(ctx, r.Client, obj, info, r.UnPausePollInterval, "Ready and Synced")
+	err = r.ensurePause(ctx, obj, info, d.reason)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to pause: %w", err)
+		return d, ctrl.Result{}, fmt.Errorf("unable to pause: %w", err)
 	}
 
-	return ctrl.Result{}, nil
+	return d, ctrl.Result{RequeueAfter: r.VerifyPauseRequeue}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// The pds are ANDed with the Predicates of the reconciler.
+// The controller is built step by step, the returned error tells the failing step.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, pds ...predicate.Predicate) error {
+	err := r.validateGVK(mgr)
+	if err != nil {
+		return err
+	}
+	err = r.validatePauseBudget()
+	if err != nil {
+		return err
+	}
+	err = r.validateNames()
+	if err != nil {
+		return err
+	}
+	err = r.validateSelector()
+	if err != nil {
+		return err
+	}
+	err = r.validateUnPausePollInterval()
+	if err != nil {
+		return err
+	}
+	if m := r.mode(); m != ModeEnforce && m != ModeObserve {
+		return fmt.Errorf("invalid Mode %q", m)
+	}
+
+	err = r.scaleConcurrency(context.Background(), mgr.GetAPIReader())
+	if err != nil {
+		return err
+	}
+
 	if r.FrozenTimeDuration == nil {
 		tmp := DefaultFrozenTimeDuration
 		r.FrozenTimeDuration = &tmp
 	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(EventRecorderName)
+	}
+	if r.SweepInterval > 0 {
+		r.addBackground(&sweeper{r: r})
+	}
+
+	logger := mgr.GetLogger().WithValues("gvk", r.GroupVersionKind.String(), "controller", r.controllerName())
+	r.checkUnPausePollInterval(logger)
+	r.checkFrozenTimeDuration(logger)
+	r.logConfig(logger)
+
+	err = r.setupBackgrounds(mgr)
+	if err != nil {
+		return err
+	}
+
+	c, err := controller.New(r.controllerName(), mgr, r.controllerOptions())
+	if err != nil {
+		return fmt.Errorf("unable to create controller: %w", err)
+	}
 
 	var u = &unstructured.Unstructured{}
 	u.SetGroupVersionKind(r.GroupVersionKind)
+	err = c.Watch(&source.Kind{Type: u}, &handler.EnqueueRequestForObject{}, r.predicate(pds...))
+	if err != nil {
+		return fmt.Errorf("unable to watch %s: %w", r.GroupVersionKind, err)
+	}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(u, builder.WithPredicates(pds...)).
-		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
-		Complete(r)
-}
+	if r.SettingsConfigMap != nil {
+		err = r.watchSettingsConfigMap(mgr, c)
+		if err != nil {
+			return fmt.Errorf("unable to watch settings config map: %w", err)
+		}
+	}
 
-func parsePauseInfo(obj *unstructured.Unstructured) (info *PauseInfo, err error) {
-	ann := obj.GetAnnotations()
+	if r.ProviderDeployment != nil {
+		err = r.watchProviderDeployment(mgr, c)
+		if err != nil {
+			return fmt.Errorf("unable to watch provider deployment: %w", err)
+		}
+	}
 
-	v, ok := ann[AnnotationKeyPauseInfo]
-	if !ok {
-		return nil, nil
+	if r.ExternalEvents != nil {
+		err = c.Watch(&source.Channel{Source: r.ExternalEvents}, &handler.EnqueueRequestForObject{})
+		if err != nil {
+			return fmt.Errorf("unable to watch external events: %w", err)
+		}
 	}
 
-	info = new(PauseInfo)
-	err = json.Unmarshal([]byte(v), info)
+	err = r.watchResync(c)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("unable to watch resync: %w", err)
 	}
 
-	return
+	return nil
 }
 
-func ensurePause(ctx context.Context, cli client.Client, obj *unstructured.Unstructured, info *PauseInfo, unPausePollInterval *time.Duration, reason string) error {
-	if info == nil {
-		info = new(PauseInfo)
+// controllerOptions returns the options of the controller.
+func (r *Reconciler) controllerOptions() controller.Options {
+	opts := controller.Options{Reconciler: r}
+	switch {
+	case r.scaledConcurrency > 0:
+		opts.MaxConcurrentReconciles = r.scaledConcurrency
+	case !r.UseDefaultConcurrency:
+		opts.MaxConcurrentReconciles = maxConcurrentReconciles
 	}
 
-	if info.Pause {
-		return nil
-	}
+	return opts
+}
 
-	info.Pause = true
-	now := metav1.Now()
-	info.LastPauseTime = &now
-	info.Object = obj
-	if unPausePollInterval != nil {
-		shouldUnpauseTime := info.LastPauseTime.Add(*unPausePollInterval)
-		// To avoid unpause too much resources at the same time when enable this feature.
-		jitter := time.Duration(rand.Float64() * 0.1 * float64(*unPausePollInterval))
-		shouldUnpauseTime = shouldUnpauseTime.Add(jitter)
-		info.ShouldUnpauseTime = &metav1.Time{Time: shouldUnpauseTime}
+// validateGVK returns an error if the GroupVersionKind is not served by the API server, e.g. the CRD is not installed,
+// or not registered in the Scheme if it's set, instead of failing obscurely once the manager starts watching it.
+// It records the scope of the GroupVersionKind.
+func (r *Reconciler) validateGVK(mgr ctrl.Manager) error {
+	gvk := r.GroupVersionKind
+	if gvk.Kind == "" || gvk.Version == "" {
+		return fmt.Errorf("invalid GroupVersionKind %q", gvk)
 	}
-	unstructured.RemoveNestedField(obj.Object, "metadata", "annotations", AnnotationKeyPauseInfo)
 
-	data, err := json.Marshal(info)
+	mapping, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
-		return fmt.Errorf("unable to marshal pause info: %w", err)
-	}
-
-	ann := obj.GetAnnotations()
-	if ann == nil {
-		ann = make(map[string]string)
+		return fmt.Errorf("unable to find %s, is the CRD installed: %w", gvk, err)
 	}
-	ann[AnnotationKeyReconciliationPaused] = "true"
-	ann[AnnotationKeyPauseInfo] = string(data)
-	obj.SetAnnotations(ann)
+	// A kind may be namespaced in one version and cluster-scoped in another.
+	r.scope = mapping.Scope.Name()
 
-	err = cli.Update(context.Background(), obj)
-	if err != nil {
-		return fmt.Errorf("failed to update object: %w", err)
+	if r.Scheme != nil && !r.Scheme.Recognizes(gvk) {
+		return fmt.Errorf("%s is not registered in the Scheme, add the types of the provider to it by AddToScheme, "+
+			"or leave the Scheme unset", gvk)
 	}
 
-	log.FromContext(ctx).Info("pause resource", "reason", reason)
 	return nil
 }
 
-func ensureUnPause(ctx context.Context, cli client.Client, obj *unstructured.Unstructured, info *PauseInfo, reason string) error {
-	if info == nil {
-		return nil
+// objectKey returns the key to get the resource of the GroupVersionKind by key, the namespace is dropped if it's
+// cluster-scoped, e.g. set by the ExternalEvents. The key is returned as is if the scope is unknown before SetupWithManager.
+func (r *Reconciler) objectKey(key types.NamespacedName) types.NamespacedName {
+	if r.scope == meta.RESTScopeNameRoot {
+		key.Namespace = ""
 	}
 
-	if !info.Pause {
-		return nil
-	}
+	return key
+}
 
-	ann := obj.GetAnnotations()
-	if ann == nil {
-		ann = make(map[string]string)
+// controllerName returns the ControllerName, or the one derived from the GroupVersionKind if it's not set.
+func (r *Reconciler) controllerName() string {
+	if r.ControllerName != "" {
+		return r.ControllerName
 	}
 
-	info.Pause = false
-	info.Object = nil
-	now := metav1.Now()
-	info.LastUnPauseTime = &now
-	info.ShouldUnpauseTime = nil
+	gvk := r.GroupVersionKind
+	return strings.TrimSuffix(strings.ToLower("pause-"+gvk.Kind+"."+gvk.Version+"."+gvk.Group), ".")
+}
 
-	data, err := json.Marshal(info)
-	if err != nil {
-		return fmt.Errorf("unable to marshal pause info: %w", err)
+// pausedByUs returns true if obj is marked paused by this controller in the AnnotationKeyPausedBy annotation, the ones
+// paused by the other controllers, e.g. of another GroupVersionKind sharing the resource, are not ours to unpause.
+func (r *Reconciler) pausedByUs(obj *unstructured.Unstructured) bool {
+	return obj.GetAnnotations()[AnnotationKeyPausedBy] == r.controllerName()
+}
+
+// predicate returns the predicate composed of all the predicates of the reconciler and pds.
+func (r *Reconciler) predicate(pds ...predicate.Predicate) predicate.Predicate {
+	all := make([]predicate.Predicate, 0, len(r.Predicates)+len(pds)+1)
+	if len(r.IncludeNames) > 0 || len(r.ExcludeNames) > 0 || len(r.Namespaces) > 0 || r.LabelSelector != nil {
+		all = append(all, r.selectorPredicate())
 	}
+	all = append(all, r.Predicates...)
+	all = append(all, pds...)
+	return predicate.And(all...)
+}
 
-	delete(ann, AnnotationKeyReconciliationPaused)
-	ann[AnnotationKeyPauseInfo] = string(data)
-	obj.SetAnnotations(ann)
+// checkUnPausePollInterval warns if the UnPausePollInterval is too short compared to the ProviderPollInterval,
+// and clamps it if ClampUnPausePollInterval is set.
+func (r *Reconciler) checkUnPausePollInterval(logger logr.Logger) {
+	if r.UnPausePollInterval == nil || r.ProviderPollInterval <= 0 {
+		r.metrics().set(unPausePollIntervalTooShort, 0)
+		return
+	}
 
-	err = cli.Update(context.Background(), obj)
-	if err != nil {
-		return fmt.Errorf("failed to update object: %w", err)
+	min := MinUnPausePollIntervalFactor * r.ProviderPollInterval
+	if *r.UnPausePollInterval >= min {
+		r.metrics().set(unPausePollIntervalTooShort, 0)
+		return
 	}
 
-	log.FromContext(ctx).Info("unPause resource", "reason", reason)
-	return nil
-}
+	r.metrics().set(unPausePollIntervalTooShort, 1)
+	logger.Info("UnPausePollInterval is too short, the provider may not poll the resource before we pause it again",
+		"unPausePollInterval", r.UnPausePollInterval.String(),
+		"providerPollInterval", r.ProviderPollInterval.String(),
+		"suggestedMin", min.String())
 
-func isUpdated(ctx context.Context, old *unstructured.Unstructured, now *unstructured.Unstructured) (bool, error) {
-	now = now.DeepCopy()
-	old = old.DeepCopy()
+	if r.ClampUnPausePollInterval {
+		r.UnPausePollInterval = &min
+		logger.Info("clamp UnPausePollInterval", "unPausePollInterval", min.String())
+	}
+}
 
-	unstructured.RemoveNestedField(now.Object, "metadata", "annotations", AnnotationKeyReconciliationPaused)
-	unstructured.RemoveNestedField(now.Object, "metadata", "annotations", AnnotationKeyPauseInfo)
+// checkFrozenTimeDuration warns if the FrozenTimeDuration is not shorter than the UnPausePollInterval, which makes
+// the resource unpaused by the UnPausePollInterval stay unpaused longer than it's paused, and clamps it if
+// ClampFrozenTimeDuration is set.
+func (r *Reconciler) checkFrozenTimeDuration(logger logr.Logger) {
+	frozenTimeDuration := DefaultFrozenTimeDuration
+	if r.FrozenTimeDuration != nil {
+		frozenTimeDuration = *r.FrozenTimeDuration
+	}
+	if r.UnPausePollInterval == nil || frozenTimeDuration < *r.UnPausePollInterval {
+		r.metrics().set(frozenTimeDurationTooLong, 0)
+		return
+	}
 
-	unstructured.RemoveNestedField(old.Object, "metadata", "annotations", AnnotationKeyReconciliationPaused)
-	unstructured.RemoveNestedField(old.Object, "metadata", "annotations", AnnotationKeyPauseInfo)
+	max := *r.UnPausePollInterval / FrozenTimeDurationClampDivisor
+	r.metrics().set(frozenTimeDurationTooLong, 1)
+	logger.Info("FrozenTimeDuration is not shorter than UnPausePollInterval, the resource stays unpaused longer than paused",
+		"frozenTimeDuration", frozenTimeDuration.String(),
+		"unPausePollInterval", r.UnPausePollInterval.String(),
+		"suggestedMax", max.String())
 
-	// check spec
-	equal, err := checkFieldEqual(ctx, old, now, "spec")
-	if err != nil {
-		return false, err
+	if r.ClampFrozenTimeDuration {
+		r.FrozenTimeDuration = &max
+		logger.Info("clamp FrozenTimeDuration", "frozenTimeDuration", max.String())
 	}
+}
 
-	if !equal {
-		return true, nil
+// logConfig logs the effective configuration of the reconciler, it should be called after all the defaulting.
+func (r *Reconciler) logConfig(logger logr.Logger) {
+	unPausePollInterval := "disabled"
+	if r.UnPausePollInterval != nil {
+		unPausePollInterval = r.UnPausePollInterval.String()
 	}
 
-	if !equal {
-		return true, nil
+	frozenTimeDuration := DefaultFrozenTimeDuration
+	if r.FrozenTimeDuration != nil {
+		frozenTimeDuration = *r.FrozenTimeDuration
 	}
 
-	// check annotations
-	equal, err = checkFieldEqual(ctx, old, now, "metadata", "annotations")
-	if err != nil {
-		return false, err
+	logger.Info("reconciler config",
+		"unPausePollInterval", unPausePollInterval,
+		"unPausePollJitter", r.fieldSettings().unPausePollJitter,
+		"frozenTimeDuration", frozenTimeDuration.String(),
+		"notReadyRequeue", r.NotReadyRequeue.String(),
+		"verifyPauseRequeue", r.VerifyPauseRequeue.String(),
+		"restoreStrippedPause", r.RestoreStrippedPause,
+		"maxPauseRestores", r.maxPauseRestores(),
+		"providerPollInterval", r.ProviderPollInterval.String(),
+		"clampUnPausePollInterval", r.ClampUnPausePollInterval,
+		"clampFrozenTimeDuration", r.ClampFrozenTimeDuration,
+		"softUnpause", r.SoftUnpause,
+		"forceUnpauseEvery", r.ForceUnpauseEvery,
+		"maxConcurrentReconciles", r.controllerOptions().MaxConcurrentReconciles,
+		"requiredConditions", requiredConditionTypes,
+		"syncedOptional", r.SyncedOptional,
+		"minSyncedObservations", r.MinSyncedObservations,
+		"syncedObservationInterval", r.syncedObservationInterval().String(),
+		"treatMissingSyncedAsTrue", r.TreatMissingSyncedAsTrue,
+		"blockingConditionReasons", r.BlockingConditionReasons,
+		"quiescencePeriod", r.QuiescencePeriod.String(),
+		"stabilityWindow", r.StabilityWindow.String(),
+		"minResourceAge", r.MinResourceAge.String(),
+		"prePauseValidate", r.PrePauseValidate != nil,
+		"readinessProbe", r.ReadinessProbe != nil,
+		"readinessProbeOnly", r.readinessProbeOnly(),
+		"readinessProbeRequeue", r.readinessProbeRequeue().String(),
+		"onReconcile", r.OnReconcile != nil,
+		"mode", r.mode(),
+		"recordWouldPause", r.RecordWouldPause,
+		"maxRequeueAfter", r.MaxRequeueAfter.String(),
+		"requeueJitter", r.RequeueJitter,
+		"reconcileTimeout", r.ReconcileTimeout.String(),
+		"statusPauseState", r.StatusPauseState,
+		"prePauseRequeue", r.prePauseRequeue().String(),
+		"conditionsPaths", r.conditionsPaths(),
+		"requireObservedGeneration", r.RequireObservedGeneration,
+		"disableGenerationGuard", r.DisableGenerationGuard,
+		"observedGenerationPath", strings.Join(r.observedGenerationPath(), "."),
+		"specDefaults", r.specDefaults(),
+		"setLikeSpecPaths", r.SetLikeSpecPaths,
+		"disableUnpauseOnUpdate", r.DisableUnpauseOnUpdate,
+		"shortUnpauseOnUpdate", r.ShortUnpauseOnUpdate,
+		"respectManualPause", r.RespectManualPause,
+		"snapshotTransform", r.SnapshotTransform != nil,
+		"adoptManuallyPaused", r.AdoptManuallyPaused,
+		"unpauseOnDeletion", r.unpauseOnDeletion(),
+		"maxDeletionFailures", r.MaxDeletionFailures,
+		"deletionFailureInterval", r.deletionFailureInterval().String(),
+		"watchFinalizers", r.WatchFinalizers,
+		"useDefaultConcurrency", r.UseDefaultConcurrency,
+		"scaledConcurrency", r.scaledConcurrency,
+		"minScaledConcurrency", r.MinScaledConcurrency,
+		"maxScaledConcurrency", r.MaxScaledConcurrency,
+		"consolidatedLog", r.ConsolidatedLog,
+		"metadataWatch", r.metadataWatch(),
+		"watchConditionChanges", r.WatchConditionChanges,
+		"specEqual", r.SpecEqual != nil,
+		"pauseInfoAnnotationKey", r.pauseInfoAnnotationKey(),
+		"legacyPauseInfoAnnotationKeys", r.LegacyPauseInfoAnnotationKeys,
+		"pauseInfoOnOwner", r.PauseInfoOnOwner,
+		"ignoredAnnotations", r.ignoredAnnotationKeys(),
+		"jsonAnnotations", r.JSONAnnotationKeys,
+		"predicates", len(r.Predicates),
+		"includeNames", r.IncludeNames,
+		"excludeNames", r.ExcludeNames,
+		"namespaces", r.Namespaces,
+		"labelSelector", r.LabelSelector != nil,
+		"specMatch", r.SpecMatch != nil,
+		"rolloutPercentage", r.RolloutPercentage,
+		"onboardAfterAge", r.OnboardAfterAge.String(),
+		"sweepInterval", r.SweepInterval.String(),
+		"backgrounds", len(r.backgrounds),
+		"selfWriteCacheSize", r.SelfWriteCacheSize,
+		"reconcileCounterSize", r.ReconcileCounterSize,
+		"namespaceMetricLabel", r.NamespaceMetricLabel,
+		"updateFailureThreshold", r.UpdateFailureThreshold,
+		"updateFailureWindow", r.updateFailureWindow().String(),
+		"updateFailureBackoff", r.updateFailureBackoff().String(),
+		"cascadeToResourceRefs", r.CascadeToResourceRefs,
+		"resourceRefsConcurrency", r.resourceRefsConcurrency(),
+		"pauseBudget", r.PauseBudget != nil,
+		"reconcileOnceTimeout", r.reconcileOnceTimeout().String(),
+		"settingsConfigMap", r.SettingsConfigMap,
+		"providerDeployment", r.ProviderDeployment,
+		"providerRestartCooldown", r.providerRestartCooldown().String(),
+		"externalEvents", r.ExternalEvents != nil,
+		"auditSink", r.AuditSink != nil,
+		"auditFatal", r.AuditFatal,
+	)
+}
+
+func (r *Reconciler) observedGenerationPath() []string {
+	if len(r.ObservedGenerationPath) == 0 {
+		return DefaultObservedGenerationPath
 	}
 
-	if !equal {
-		return true, nil
+	return r.ObservedGenerationPath
+}
+
+func (r *Reconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
 	}
 
-	// check labels
-	equal, err = checkFieldEqual(ctx, old, now, "metadata", "labels")
-	if err != nil {
-		return false, err
+	return r.Clock.Now()
+}
+
+// jitter returns a random jitter up to the fraction of interval, it's 0 if the interval is shorter than
+// MinJitteredUnPausePollInterval.
+func (r *Reconciler) jitter(interval time.Duration, fraction float64) time.Duration {
+	if interval < MinJitteredUnPausePollInterval || fraction <= 0 {
+		return 0
 	}
 
-	if !equal {
-		return true, nil
+	return time.Duration(r.random() * fraction * float64(interval))
+}
+
+// validateUnPausePollInterval returns an error if the UnPausePollInterval is set but not positive, which unpauses
+// the resources right after pausing them, leave it nil to disable it.
+func (r *Reconciler) validateUnPausePollInterval() error {
+	if r.UnPausePollInterval != nil && *r.UnPausePollInterval <= 0 {
+		return fmt.Errorf("invalid UnPausePollInterval %s: it must be positive, leave it nil to disable it", *r.UnPausePollInterval)
 	}
 
-	return false, nil
+	return nil
 }
 
-func checkFieldEqual(ctx context.Context, obj1, obj2 *unstructured.Unstructured, fields ...string) (bool, error) {
-	spec1, ok1, err := unstructured.NestedMap(obj1.Object, fields...)
-	if err != nil {
-		return false, err
+// random returns a pseudo-random number in [0.0,1.0) from the RandSource.
+func (r *Reconciler) random() float64 {
+	r.randOnce.Do(func() {
+		source := r.RandSource
+		if source == nil {
+			source = rand.NewSource(time.Now().UnixNano())
+		}
+		r.rand = rand.New(source)
+	})
+
+	r.randMu.Lock()
+	defer r.randMu.Unlock()
+	return r.rand.Float64()
+}
+
+// maxPauseRestores returns the MaxPauseRestores, DefaultMaxPauseRestores if not set, or 0 for no limit.
+func (r *Reconciler) maxPauseRestores() int {
+	if r.MaxPauseRestores < 0 {
+		return 0
+	}
+	if r.MaxPauseRestores == 0 {
+		return DefaultMaxPauseRestores
 	}
 
-	spec2, ok2, err := unstructured.NestedMap(obj2.Object, fields...)
-	if err != nil {
-		return false, err
+	return r.MaxPauseRestores
+}
+
+func (r *Reconciler) unpauseOnDeletion() bool {
+	return r.UnpauseOnDeletion == nil || *r.UnpauseOnDeletion
+}
+
+// frozenTimeDuration returns the FrozenTimeDuration of obj, which can be overridden by the AnnotationKeyFrozenDuration annotation.
+func (r *Reconciler) frozenTimeDuration(ctx context.Context, obj *unstructured.Unstructured) time.Duration {
+	frozenTimeDuration := r.settings().frozenTimeDuration
+	v, ok := obj.GetAnnotations()[AnnotationKeyFrozenDuration]
+	if !ok {
+		return frozenTimeDuration
 	}
 
-	if ok1 != ok2 {
-		return false, nil
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.FromContext(ctx).Info("invalid frozen duration annotation, use the default one", "value", v, "default", frozenTimeDuration.String())
+		return frozenTimeDuration
 	}
 
-	// both not exist
-	if !ok1 {
-		return true, nil
+	return d
+}
+
+func (r *Reconciler) pauseInfoAnnotationKey() string {
+	if r.PauseInfoAnnotationKey == "" {
+		return AnnotationKeyPauseInfo
 	}
 
-	if !reflect.DeepEqual(spec1, spec2) {
-		diff := cmp.Diff(spec1, spec2)
-		log.FromContext(ctx).Info("field not equal", "field", strings.Join(fields, "."), "diff", diff)
-		return false, nil
+	return r.PauseInfoAnnotationKey
+}
+
+// ignoredAnnotationKeys returns the annotations which are not considered when checking if the resource is updated.
+func (r *Reconciler) ignoredAnnotationKeys() []string {
+	keys := []string{
+		AnnotationKeyReconciliationPaused,
+		AnnotationKeyPausePinned,
+		AnnotationKeyFrozenDuration,
+		AnnotationKeyReconcileOnce,
+		AnnotationKeyPausedBy,
+		AnnotationKeyPauseNote,
+		AnnotationKeyPauseUntil,
+		AnnotationKeyWouldPause,
+		r.pauseInfoAnnotationKey(),
 	}
 
-	return true, nil
+	return append(keys, r.LegacyPauseInfoAnnotationKeys...)
 }
 
-func isPaused(v string) bool {
-	return v == "true"
+// parsePauseInfo parses the pause info from the PauseInfoAnnotationKey annotation of obj,
+// and falls back to the LegacyPauseInfoAnnotationKeys if it's missing.
+// It returns nil if none of them exists.
+func (r *Reconciler) parsePauseInfo(obj *unstructured.Unstructured) (*PauseInfo, error) {
+	return pauseInfoFromAnnotations(obj.GetAnnotations(), append([]string{r.pauseInfoAnnotationKey()}, r.LegacyPauseInfoAnnotationKeys...)...)
 }
 
-func getCondition(obj *unstructured.Unstructured, ty xpv1.ConditionType) (res *xpv1.Condition, err error) {
-	/*
-	   status:
-	     conditions:
-	     - lastTransitionTime: "2022-07-22T10:54:18Z"
-	       reason: Available
-	       status: "True"
-	       type: Ready
-	*/
-	conditions, ok, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+// setPauseInfo sets the pause info into the PauseInfoAnnotationKey annotation of obj, and removes the legacy ones.
+func (r *Reconciler) setPauseInfo(obj *unstructured.Unstructured, info *PauseInfo) error {
+	data, err := json.Marshal(info)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get conditions: %w", err)
+		return fmt.Errorf("unable to marshal pause info: %w", err)
 	}
 
-	if !ok {
-		return nil, nil
+	ann := obj.GetAnnotations()
+	if ann == nil {
+		ann = make(map[string]string)
+	}
+	for _, key := range r.LegacyPauseInfoAnnotationKeys {
+		delete(ann, key)
+	}
+	ann[r.pauseInfoAnnotationKey()] = string(data)
+	obj.SetAnnotations(ann)
+	return nil
+}
+
+func (r *Reconciler) ensurePause(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, reason string) error {
+	if info == nil {
+		info = new(PauseInfo)
+	}
+
+	if info.Pause {
+		return nil
+	}
+
+	paused := false
+	stuck := info.DeletionStuck
+	var pauseUntilErr error
+	var infoBytes int
+	err := r.update(ctx, obj, func(obj *unstructured.Unstructured, refetched bool) (bool, error) {
+		paused = false
+		if refetched {
+			// The object changed since we decided to pause it, re-check the decision against the fresh one.
+			freshInfo, err := r.parsePauseInfo(obj)
+			if err != nil {
+				return false, fmt.Errorf("unable to parse pause info: %w", err)
+			}
+			if freshInfo == nil {
+				freshInfo = new(PauseInfo)
+			}
+			if freshInfo.Pause {
+				return false, nil
+			}
+
+			// The stuck deletion is not paused by the conditions, see pauseStuckDeletion.
+			ready := !obj.GetDeletionTimestamp().IsZero() && freshInfo.DeletionFailures >= r.MaxDeletionFailures
+			if !stuck {
+				ready, err = r.readyToPause(ctx, obj, freshInfo)
+				if err != nil {
+					return false, err
+				}
+			}
+			if !ready {
+				return false, nil
+			}
+			info = freshInfo
+			info.DeletionStuck = stuck
+		}
+
+		info.Pause = true
+		now := metav1.NewTime(r.now())
+		info.LastPauseTime = &now
+		info.Object = r.trimObject(obj)
+		r.setShouldUnpauseTime(info, now.Time)
+		info.PauseUntil, pauseUntilErr = parsePauseUntil(obj)
+		if info.PauseUntil != nil {
+			// It's asked by the operators explicitly, neither jittered nor shifted by the UnPausePollInterval.
+			info.ShouldUnpauseTime = info.PauseUntil.DeepCopy()
+			info.UnPausePollInterval = nil
+		}
+		info.SkippedUnpauses = 0
+		info.ReconcileOnce = nil
+		info.Fingerprint = ""
+		info.LastChangeTime = nil
+		info.PauseRestores = 0
+		info.SyncedObservations = 0
+		info.LastSyncedObservation = nil
+		info.Note = pauseNote(obj)
+		conditions, err := r.presentConditions(ctx, obj)
+		if err != nil {
+			return false, err
+		}
+		info.Conditions = conditions
+
+		err = r.setPauseInfo(obj, info)
+		if err != nil {
+			return false, err
+		}
+
+		ann := obj.GetAnnotations()
+		delete(ann, AnnotationKeyReconcileOnce)
+		infoBytes = len(ann[r.pauseInfoAnnotationKey()])
+		ann[AnnotationKeyReconciliationPaused] = "true"
+		ann[AnnotationKeyPausedBy] = r.controllerName()
+		obj.SetAnnotations(ann)
+		paused = true
+		return true, nil
+	})
+	if apierrors.IsNotFound(err) {
+		log.FromContext(ctx).Info("skip pause since resource is gone")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update object: %w", err)
+	}
+
+	if !paused {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("pause resource", "reason", reason)
+	// The annotations are limited in size, let operators alert before the pause info is too large.
+	r.metrics().set(pauseInfoBytes, float64(infoBytes))
+	if pauseUntilErr != nil {
+		log.FromContext(ctx).Error(pauseUntilErr, "ignore the pause until")
+		r.event(obj, corev1.EventTypeWarning, EventReasonInvalidPauseUntil, "Ignore the pause until: %s", pauseUntilErr.Error())
+	}
+	r.metrics().incObject(transitions, obj, string(ActionPause))
+	if info.Note != "" {
+		r.event(obj, corev1.EventTypeNormal, EventReasonPaused, "%s", withNote("Pause resource: "+reason, info.Note))
+	}
+	r.patchPauseState(ctx, obj, info, reason)
+	err = r.audit(ctx, obj, ActionPause, reason, info)
+	if err != nil {
+		return err
+	}
+	if r.CascadeToResourceRefs {
+		err := r.pauseResourceRefs(ctx, obj, info)
+		if err != nil {
+			return fmt.Errorf("unable to pause the composed resources: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) ensureUnPause(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, reason UnpauseReason) error {
+	if info == nil {
+		return nil
+	}
+
+	if !info.Pause {
+		return nil
+	}
+
+	unpaused := false
+	var pausedConditions []string
+	err := r.update(ctx, obj, func(obj *unstructured.Unstructured, refetched bool) (bool, error) {
+		unpaused = false
+		if refetched {
+			freshInfo, err := r.parsePauseInfo(obj)
+			if err != nil {
+				return false, fmt.Errorf("unable to parse pause info: %w", err)
+			}
+			// Someone else (e.g. another replica) has already unpaused it.
+			if freshInfo == nil && reason == UnpauseReasonPauseInfoLost {
+				freshInfo = &PauseInfo{Pause: isPaused(obj.GetAnnotations()[AnnotationKeyReconciliationPaused]) && r.pausedByUs(obj)}
+			}
+			if freshInfo == nil || !freshInfo.Pause {
+				return false, nil
+			}
+			info = freshInfo
+		}
+
+		pausedConditions = info.Conditions
+		info.Pause = false
+		info.Object = nil
+		info.Conditions = nil
+		now := metav1.NewTime(r.now())
+		info.LastUnPauseTime = &now
+		info.ShouldUnpauseTime = nil
+		info.UnPausePollInterval = nil
+		info.SkippedUnpauses = 0
+		info.ReconcileOnce = nil
+		info.Fingerprint = ""
+		info.LastChangeTime = nil
+		info.PauseRestores = 0
+		info.DeletionStuck = false
+		info.DeletionFailures = 0
+		info.LastDeletionFailure = nil
+		info.PauseUntil = nil
+		if reason == UnpauseReasonReconcileOnce || (reason == UnpauseReasonUpdated && r.ShortUnpauseOnUpdate) {
+			once, err := r.newReconcileOnce(obj, now)
+			if err != nil {
+				return false, err
+			}
+			info.ReconcileOnce = once
+		}
+		info.Note = pauseNote(obj)
+		appendHistory(info, UnpauseRecord{Time: now, Reason: reason, Note: info.Note})
+
+		err := r.setPauseInfo(obj, info)
+		if err != nil {
+			return false, err
+		}
+
+		ann := obj.GetAnnotations()
+		delete(ann, AnnotationKeyReconciliationPaused)
+		delete(ann, AnnotationKeyPausedBy)
+		delete(ann, AnnotationKeyPauseUntil)
+		obj.SetAnnotations(ann)
+		unpaused = true
+		return true, nil
+	})
+	if apierrors.IsNotFound(err) {
+		log.FromContext(ctx).Info("skip unpause since resource is gone")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update object: %w", err)
+	}
+
+	if !unpaused {
+		return nil
+	}
+
+	if info.LastPauseTime != nil {
+		r.metrics().observeObject(pausedDuration, obj, info.LastUnPauseTime.Sub(info.LastPauseTime.Time).Seconds(), string(reason))
+	}
+	r.metrics().incObject(transitions, obj, string(ActionUnpause))
+	log.FromContext(ctx).Info("unPause resource", "reason", reason, "message", reason.Message())
+	r.patchPauseState(ctx, obj, info, string(reason))
+	r.event(obj, corev1.EventTypeNormal, reason.EventReason(), "%s", withNote("Unpause resource: "+reason.Message(), info.Note))
+	if reason != UnpauseReasonDeleted {
+		r.reportLostConditions(ctx, obj, pausedConditions)
+	}
+	err = r.audit(ctx, obj, ActionUnpause, string(reason), info)
+	if err != nil {
+		return err
+	}
+	if r.CascadeToResourceRefs {
+		err := r.unpauseResourceRefs(ctx, obj, reason)
+		if err != nil {
+			return fmt.Errorf("unable to unpause the composed resources: %w", err)
+		}
+	}
+	return nil
+}
+
+// verifyingPause returns true if the pause of info is still verified by the VerifyPauseRequeue at now.
+func (r *Reconciler) verifyingPause(info *PauseInfo, now time.Time) bool {
+	if r.VerifyPauseRequeue <= 0 || info.LastPauseTime == nil {
+		return false
+	}
+
+	return !now.After(info.LastPauseTime.Add(2 * r.VerifyPauseRequeue))
+}
+
+// restorePause adds the pause annotation back to a paused resource whose pause annotation is stripped.
+func (r *Reconciler) restorePause(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo) error {
+	restored := false
+	err := r.update(ctx, obj, func(obj *unstructured.Unstructured, refetched bool) (bool, error) {
+		restored = false
+		if refetched {
+			freshInfo, err := r.parsePauseInfo(obj)
+			if err != nil {
+				return false, fmt.Errorf("unable to parse pause info: %w", err)
+			}
+			if freshInfo == nil || !freshInfo.Pause || isPaused(obj.GetAnnotations()[AnnotationKeyReconciliationPaused]) {
+				return false, nil
+			}
+			info = freshInfo
+		}
+
+		info.PauseRestores++
+		err := r.setPauseInfo(obj, info)
+		if err != nil {
+			return false, err
+		}
+
+		ann := obj.GetAnnotations()
+		ann[AnnotationKeyReconciliationPaused] = "true"
+		obj.SetAnnotations(ann)
+		restored = true
+		return true, nil
+	})
+	if apierrors.IsNotFound(err) {
+		log.FromContext(ctx).Info("skip restore pause since resource is gone")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update object: %w", err)
+	}
+
+	if !restored {
+		return nil
+	}
+
+	r.metrics().incObject(pauseRestores, obj)
+	log.FromContext(ctx).Info("restore pause annotation stripped by others", "pauseRestores", info.PauseRestores)
+	return nil
+}
+
+// extendPause keeps the paused resource paused for another UnPausePollInterval.
+func (r *Reconciler) extendPause(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo) error {
+	err := r.update(ctx, obj, func(obj *unstructured.Unstructured, refetched bool) (bool, error) {
+		if refetched {
+			freshInfo, err := r.parsePauseInfo(obj)
+			if err != nil {
+				return false, fmt.Errorf("unable to parse pause info: %w", err)
+			}
+			if freshInfo == nil || !freshInfo.Pause {
+				return false, nil
+			}
+			info = freshInfo
+		}
+
+		r.setShouldUnpauseTime(info, r.now())
+		info.SkippedUnpauses++
+		err := r.setPauseInfo(obj, info)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update object: %w", err)
+	}
+
+	return nil
+}
+
+// setShouldUnpauseTime sets the time we should unpause a resource paused at from to respect UnPausePollInterval,
+// and the UnPausePollInterval used, both of them are nil if UnPausePollInterval is not set.
+func (r *Reconciler) setShouldUnpauseTime(info *PauseInfo, from time.Time) {
+	s := r.settings()
+	if s.unPausePollInterval == nil {
+		info.ShouldUnpauseTime = nil
+		info.UnPausePollInterval = nil
+		return
+	}
+
+	shouldUnpauseTime := from.Add(*s.unPausePollInterval)
+	// To avoid unpause too much resources at the same time when enable this feature.
+	shouldUnpauseTime = shouldUnpauseTime.Add(r.jitter(*s.unPausePollInterval, s.unPausePollJitter))
+	info.ShouldUnpauseTime = &metav1.Time{Time: shouldUnpauseTime}
+	info.UnPausePollInterval = &metav1.Duration{Duration: *s.unPausePollInterval}
+}
+
+// updateWithRetry updates obj after mutating it by mutate.
+// The update relies on the resourceVersion of obj for optimistic concurrency, if it's rejected
+// because of a conflict, we re-fetch the object into obj and call mutate again with refetched = true.
+// mutate returns false if there is no need to update the object anymore.
+func updateWithRetry(ctx context.Context, cli client.Client, obj *unstructured.Unstructured, mutate func(obj *unstructured.Unstructured, refetched bool) (bool, error)) error {
+	refetched := false
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if refetched {
+			fresh := new(unstructured.Unstructured)
+			fresh.SetGroupVersionKind(obj.GroupVersionKind())
+			err := cli.Get(ctx, client.ObjectKeyFromObject(obj), fresh)
+			if err != nil {
+				return err
+			}
+			obj.Object = fresh.Object
+		}
+
+		need, err := mutate(obj, refetched)
+		refetched = true
+		if err != nil {
+			return err
+		}
+
+		if !need {
+			return nil
+		}
+
+		return cli.Update(ctx, obj)
+	})
+}
+
+func (r *Reconciler) isUpdated(ctx context.Context, old *unstructured.Unstructured, now *unstructured.Unstructured) (bool, error) {
+	now = r.transformSnapshot(copyForCompare(now))
+	old = r.transformSnapshot(copyForCompare(old))
+
+	for _, key := range r.ignoredAnnotationKeys() {
+		unstructured.RemoveNestedField(now.Object, "metadata", "annotations", key)
+		unstructured.RemoveNestedField(old.Object, "metadata", "annotations", key)
+	}
+
+	// check spec
+	defaults := r.specDefaults()
+	removeSpecDefaults(old, defaults)
+	removeSpecDefaults(now, defaults)
+	sortSetLikeLists(old, r.SetLikeSpecPaths)
+	sortSetLikeLists(now, r.SetLikeSpecPaths)
+	specEqual := deepEqual
+	if r.SpecEqual != nil {
+		specEqual = r.SpecEqual
+	}
+	equal, err := checkFieldEqualFunc(ctx, old, now, specEqual, "spec")
+	if err != nil {
+		return false, err
+	}
+
+	if !equal {
+		return true, nil
+	}
+
+	if !equal {
+		return true, nil
+	}
+
+	watch := r.metadataWatch()
+
+	// check annotations
+	if watch.WatchAnnotations {
+		normalizeJSONAnnotations(old, r.JSONAnnotationKeys)
+		normalizeJSONAnnotations(now, r.JSONAnnotationKeys)
+		equal, err = checkFieldEqual(ctx, old, now, "metadata", "annotations")
+		if err != nil {
+			return false, err
+		}
+
+		if !equal {
+			return true, nil
+		}
+	}
+
+	// check labels
+	if watch.WatchLabels {
+		equal, err = checkFieldEqual(ctx, old, now, "metadata", "labels")
+		if err != nil {
+			return false, err
+		}
+
+		if !equal {
+			return true, nil
+		}
+	}
+
+	// check finalizers
+	if watch.WatchFinalizers {
+		equal, err = checkFinalizersEqual(ctx, old, now)
+		if err != nil {
+			return false, err
+		}
+
+		if !equal {
+			return true, nil
+		}
+	}
+
+	// check owner references
+	if watch.WatchOwnerReferences {
+		equal, err = checkOwnerReferencesEqual(ctx, old, now)
+		if err != nil {
+			return false, err
+		}
+
+		if !equal {
+			return true, nil
+		}
+	}
+
+	// check conditions
+	if r.WatchConditionChanges && !checkConditionsEqual(ctx, old, now) {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// trimObject returns a copy of obj only keeping the fields we need to check if the resource is updated.
+// The returned object is stored in PauseInfo.Object, so the pause info annotation stays small.
+func (r *Reconciler) trimObject(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	res := new(unstructured.Unstructured)
+	res.SetAPIVersion(obj.GetAPIVersion())
+	res.SetKind(obj.GetKind())
+	res.SetName(obj.GetName())
+	res.SetNamespace(obj.GetNamespace())
+
+	if labels, ok, _ := unstructured.NestedStringMap(obj.Object, "metadata", "labels"); ok {
+		res.SetLabels(labels)
+	}
+
+	ann := obj.GetAnnotations()
+	if ann == nil {
+		ann = make(map[string]string)
+	}
+	for _, key := range r.ignoredAnnotationKeys() {
+		delete(ann, key)
+	}
+	res.SetAnnotations(ann)
+
+	if finalizers := obj.GetFinalizers(); len(finalizers) > 0 {
+		res.SetFinalizers(finalizers)
+	}
+
+	if refs := obj.GetOwnerReferences(); len(refs) > 0 {
+		res.SetOwnerReferences(refs)
+	}
+
+	if spec, ok := obj.Object["spec"]; ok {
+		res.Object["spec"] = runtime.DeepCopyJSONValue(spec)
+	}
+
+	if r.WatchConditionChanges {
+		if conditions, ok := trimConditions(obj); ok {
+			_ = unstructured.SetNestedSlice(res.Object, conditions, "status", "conditions")
+		}
+	}
+
+	return r.transformSnapshot(res)
+}
+
+// copyForCompare returns a copy of obj to modify for the comparison in isUpdated, only the metadata and the spec are
+// copied deeply since only they are modified, the others like the status, which may be large, are shared with obj.
+func copyForCompare(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	res := &unstructured.Unstructured{Object: make(map[string]interface{}, len(obj.Object))}
+	for key, v := range obj.Object {
+		if key == "metadata" || key == "spec" {
+			v = runtime.DeepCopyJSONValue(v)
+		}
+		res.Object[key] = v
+	}
+
+	return res
+}
+
+// checkFieldEqual returns true if the map at fields of obj1 and obj2 are equal after normalized,
+// an absent map is considered as an empty one.
+func checkFieldEqual(ctx context.Context, obj1, obj2 *unstructured.Unstructured, fields ...string) (bool, error) {
+	return checkFieldEqualFunc(ctx, obj1, obj2, deepEqual, fields...)
+}
+
+// checkFieldEqualFunc checks if the fields of obj1 and obj2 are equal by equal, the absent field is considered as an empty map.
+func checkFieldEqualFunc(ctx context.Context, obj1, obj2 *unstructured.Unstructured,
+	equal func(map[string]interface{}, map[string]interface{}) (bool, error), fields ...string) (bool, error) {
+	spec1, err := nestedNullableMap(obj1.Object, fields...)
+	if err != nil {
+		return false, err
+	}
+
+	spec2, err := nestedNullableMap(obj2.Object, fields...)
+	if err != nil {
+		return false, err
+	}
+
+	spec1 = normalize(spec1).(map[string]interface{})
+	spec2 = normalize(spec2).(map[string]interface{})
+	ok, err := equal(spec1, spec2)
+	if err != nil {
+		return false, fmt.Errorf("unable to compare %s: %w", strings.Join(fields, "."), err)
+	}
+	if !ok {
+		logFieldNotEqual(ctx, strings.Join(fields, "."), spec1, spec2)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// logFieldNotEqual logs the field is not equal, the diff of v1 and v2 is computed only if the verbosity is at least 1,
+// it's expensive for a large field.
+func logFieldNotEqual(ctx context.Context, field string, v1, v2 interface{}) {
+	logger := log.FromContext(ctx)
+	if !logger.V(1).Enabled() {
+		logger.Info("field not equal", "field", field)
+		return
+	}
+
+	logger.Info("field not equal", "field", field, "diff", cmp.Diff(v1, v2))
+}
+
+// nestedNullableMap returns the map at fields of obj, it returns nil if the map is absent or null.
+// The map is not copied, normalize it to get a copy.
+func nestedNullableMap(obj map[string]interface{}, fields ...string) (map[string]interface{}, error) {
+	v, _, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is of the type %T, expected map[string]interface{}", strings.Join(fields, "."), v)
+	}
+	return m, nil
+}
+
+func deepEqual(m1, m2 map[string]interface{}) (bool, error) {
+	return reflect.DeepEqual(m1, m2), nil
+}
+
+// blockingCondition is a required condition which is not true.
+type blockingCondition struct {
+	Type xpv1.ConditionType
+	// Status is empty if the condition is missing.
+	Status corev1.ConditionStatus
+	Reason xpv1.ConditionReason
+}
+
+// String returns the description of the blocking condition like "Ready is False".
+func (c *blockingCondition) String() string {
+	switch c.Status {
+	case "":
+		return fmt.Sprintf("%s is Missing", c.Type)
+	case corev1.ConditionTrue:
+		return fmt.Sprintf("%s has blocking reason %s", c.Type, c.Reason)
+	default:
+		return fmt.Sprintf("%s is %s", c.Type, c.Status)
+	}
+}
+
+// isBlockingReason returns true if reason is one of the BlockingConditionReasons.
+func (r *Reconciler) isBlockingReason(reason xpv1.ConditionReason) bool {
+	for _, blocking := range r.BlockingConditionReasons {
+		if reason == blocking {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getBlockingCondition returns the first required condition of obj which is not true or has one of the
+// BlockingConditionReasons, or nil if all of them are true.
+// None of them blocks if the ReadinessProbe replaces the condition checks.
+func (r *Reconciler) getBlockingCondition(ctx context.Context, obj *unstructured.Unstructured) (*blockingCondition, error) {
+	if r.readinessProbeOnly() {
+		return nil, nil
+	}
+
+	for _, ty := range requiredConditionTypes {
+		c, err := r.condition(ctx, obj, ty)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get %s condition: %w", strings.ToLower(string(ty)), err)
+		}
+
+		// Only a reconcile error blocks if the Synced is optional, it may be missing or unknown forever.
+		if ty == xpv1.TypeSynced && r.SyncedOptional && (c == nil || c.Status != corev1.ConditionFalse) {
+			continue
+		}
+
+		// The provider never emits the Synced.
+		if ty == xpv1.TypeSynced && r.TreatMissingSyncedAsTrue && c == nil {
+			continue
+		}
+
+		if c == nil {
+			return &blockingCondition{Type: ty}, nil
+		}
+
+		if c.Status != corev1.ConditionTrue || r.isBlockingReason(c.Reason) {
+			return &blockingCondition{Type: ty, Status: c.Status, Reason: c.Reason}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// readyToPause re-checks if obj is ready to pause after it's changed, by the QuiescencePeriod if it's set,
+// otherwise by the conditions.
+func (r *Reconciler) readyToPause(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo) (bool, error) {
+	pending, err := r.generationPending(obj)
+	if err != nil || pending {
+		return false, err
+	}
+
+	if r.QuiescencePeriod <= 0 {
+		ready, err := r.isReadyAndSynced(ctx, obj)
+		if err != nil || !ready {
+			return false, err
+		}
+		return r.readyByProbe(ctx, obj), nil
+	}
+
+	fingerprint, err := r.fingerprint(obj)
+	if err != nil {
+		return false, err
+	}
+	return info.Fingerprint == fingerprint, nil
+}
+
+// isReadyAndSynced returns true if both the Ready and Synced condition of obj are true, see SyncedOptional for the exception.
+func (r *Reconciler) isReadyAndSynced(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+	blocking, err := r.getBlockingCondition(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+
+	return blocking == nil, nil
+}
+
+// pauseReason returns the reason of pausing obj made of the reasons of its required conditions like
+// "Ready=Available, Synced=ReconcileSuccess", so the provider's own reasoning is in the events and the history.
+// The missing conditions and reasons are omitted, it falls back to "Ready and Synced" if there is none.
+// It's "ready by readiness probe" if the ReadinessProbe replaces the condition checks.
+func (r *Reconciler) pauseReason(ctx context.Context, obj *unstructured.Unstructured) (string, error) {
+	if r.readinessProbeOnly() {
+		return "ready by readiness probe", nil
+	}
+
+	reasons := make([]string, 0, len(requiredConditionTypes))
+	for _, ty := range requiredConditionTypes {
+		c, err := r.condition(ctx, obj, ty)
+		if err != nil {
+			return "", fmt.Errorf("unable to get %s condition: %w", strings.ToLower(string(ty)), err)
+		}
+		if c == nil || c.Reason == "" {
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("%s=%s", ty, c.Reason))
+	}
+
+	if len(reasons) == 0 {
+		return "Ready and Synced", nil
+	}
+	return strings.Join(reasons, ", "), nil
+}
+
+// observedGenerationReached returns true if the observed generation at path is not less than metadata.generation of obj.
+func observedGenerationReached(obj *unstructured.Unstructured, path []string) (bool, error) {
+	observed, ok, err := observedGeneration(obj, path)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return observed >= obj.GetGeneration(), nil
+}
+
+// generationPending returns true if status.observedGeneration of obj is behind metadata.generation, the conditions
+// may be left from the previous generation then. It returns false if there is no status.observedGeneration.
+func (r *Reconciler) generationPending(obj *unstructured.Unstructured) (bool, error) {
+	if r.DisableGenerationGuard {
+		return false, nil
+	}
+
+	observed, ok, err := observedGeneration(obj, statusObservedGenerationPath)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return observed < obj.GetGeneration(), nil
+}
+
+// observedGeneration returns the observed generation at path of obj, and false if it's missing.
+func observedGeneration(obj *unstructured.Unstructured, path []string) (int64, bool, error) {
+	v, ok, err := unstructured.NestedFieldNoCopy(obj.Object, path...)
+	if err != nil {
+		return 0, false, fmt.Errorf("unable to get observed generation: %w", err)
+	}
+
+	if !ok {
+		return 0, false, nil
+	}
+
+	switch v := v.(type) {
+	case int64:
+		return v, true, nil
+	case float64:
+		return int64(v), true, nil
+	default:
+		return 0, false, fmt.Errorf("unexpected type %T of observed generation at %s", v, strings.Join(path, "."))
+	}
+}
+
+// checkFinalizersEqual returns true if obj1 and obj2 have the same finalizers regardless of the order.
+func checkFinalizersEqual(ctx context.Context, obj1, obj2 *unstructured.Unstructured) (bool, error) {
+	finalizers1, _, err := unstructured.NestedStringSlice(obj1.Object, "metadata", "finalizers")
+	if err != nil {
+		return false, err
+	}
+
+	finalizers2, _, err := unstructured.NestedStringSlice(obj2.Object, "metadata", "finalizers")
+	if err != nil {
+		return false, err
+	}
+
+	set1 := sets.NewString(finalizers1...)
+	set2 := sets.NewString(finalizers2...)
+	if !set1.Equal(set2) {
+		logFieldNotEqual(ctx, "metadata.finalizers", set1.List(), set2.List())
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func isPaused(v string) bool {
+	return v == "true"
+}
+
+// inRollout returns true if obj is in the rollout of RolloutPercentage.
+func (r *Reconciler) inRollout(obj *unstructured.Unstructured) bool {
+	if r.RolloutPercentage == nil {
+		return true
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(obj.GetUID()))
+	return int(h.Sum32()%100) < *r.RolloutPercentage
+}
+
+// onboardDelay returns how long to wait at now before obj is old enough for the OnboardAfterAge, it returns 0 if it is.
+func (r *Reconciler) onboardDelay(obj *unstructured.Unstructured, now time.Time) time.Duration {
+	if r.OnboardAfterAge <= 0 {
+		return 0
+	}
+
+	if delay := obj.GetCreationTimestamp().Add(r.OnboardAfterAge).Sub(now); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// NextUnpauseTime returns the time the UnPausePollInterval will unpause obj paused with info, for planning. It returns nil
+// if obj is not paused by us, it's pinned or the UnPausePollInterval is disabled. The resource may be unpaused earlier
+// once it's updated or deleted, or later if it's not drifted with SoftUnpause. It's the pause until if it's set.
+func (r *Reconciler) NextUnpauseTime(obj *unstructured.Unstructured, info *PauseInfo) *time.Time {
+	if info == nil || !info.Pause || info.LastPauseTime == nil || isPinned(obj) {
+		return nil
+	}
+
+	if info.PauseUntil != nil {
+		t := info.PauseUntil.Time
+		return &t
+	}
+
+	unPausePollInterval := r.settings().unPausePollInterval
+	if unPausePollInterval == nil {
+		return nil
+	}
+
+	t := shouldUnpauseTime(info, *unPausePollInterval)
+	return &t
+}
+
+// shouldUnpauseTime returns the time to unpause the resource paused with info by the unPausePollInterval.
+func shouldUnpauseTime(info *PauseInfo, unPausePollInterval time.Duration) time.Time {
+	if info.ShouldUnpauseTime == nil {
+		return info.LastPauseTime.Add(unPausePollInterval)
+	}
+
+	t := info.ShouldUnpauseTime.Time
+	// Shift it if the UnPausePollInterval is changed since we computed it.
+	if info.UnPausePollInterval != nil {
+		t = t.Add(unPausePollInterval - info.UnPausePollInterval.Duration)
+	}
+	return t
+}
+
+func isPinned(obj *unstructured.Unstructured) bool {
+	return obj.GetAnnotations()[AnnotationKeyPausePinned] == "true"
+}
+
+// getCondition returns the condition of type ty at the DefaultConditionsPath, or nil if it's missing.
+func getCondition(ctx context.Context, obj *unstructured.Unstructured, ty xpv1.ConditionType) (*xpv1.Condition, error) {
+	return getConditionAt(ctx, obj, ty, [][]string{DefaultConditionsPath})
+}
+
+// condition returns the condition of type ty at the ConditionsPath or the FallbackConditionsPath, or nil if it's missing.
+func (r *Reconciler) condition(ctx context.Context, obj *unstructured.Unstructured, ty xpv1.ConditionType) (*xpv1.Condition, error) {
+	return getConditionAt(ctx, obj, ty, r.conditionsPaths())
+}
+
+// conditionsPaths returns the paths to read the conditions from in order.
+func (r *Reconciler) conditionsPaths() [][]string {
+	paths := [][]string{DefaultConditionsPath}
+	if len(r.ConditionsPath) > 0 {
+		paths[0] = r.ConditionsPath
+	}
+	if len(r.FallbackConditionsPath) > 0 {
+		paths = append(paths, r.FallbackConditionsPath)
+	}
+
+	return paths
+}
+
+// getConditionAt returns the condition of type ty, or nil if it's missing. The conditions are read from the first of
+// the paths having any.
+// The malformed condition entries are skipped, so a bad one written by the provider doesn't fail the reconcile.
+func getConditionAt(ctx context.Context, obj *unstructured.Unstructured, ty xpv1.ConditionType, paths [][]string) (res *xpv1.Condition, err error) {
+	/*
+	   status:
+	     conditions:
+	     - lastTransitionTime: "2022-07-22T10:54:18Z"
+	       reason: Available
+	       status: "True"
+	       type: Ready
+	*/
+	var conditions []interface{}
+	for _, path := range paths {
+		v, ok, err := unstructured.NestedFieldNoCopy(obj.Object, path...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get conditions: %w", err)
+		}
+
+		// The object may have no status at all, or a null one.
+		if !ok || v == nil {
+			continue
+		}
+
+		conditions, ok = v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unable to get conditions: %s is of the type %T, expected []interface{}", strings.Join(path, "."), v)
+		}
+		if len(conditions) > 0 {
+			break
+		}
 	}
 
 	for _, c := range conditions {
@@ -417,7 +2138,8 @@ func getCondition(obj *unstructured.Unstructured, ty xpv1.ConditionType) (res *x
 		res = new(xpv1.Condition)
 		err = json.Unmarshal(data, res)
 		if err != nil {
-			return nil, fmt.Errorf("unable to unmarshal condition: %w", err)
+			log.FromContext(ctx).Info("skip malformed condition", "object", client.ObjectKeyFromObject(obj), "condition", string(data), "error", err.Error())
+			continue
 		}
 
 		if res.Type == ty {
@@ -2,95 +2,1654 @@ package crossplanepause
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	ec2v1beta1 "github.com/crossplane-contrib/provider-aws/apis/ec2/v1beta1"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// testGVK is not registered in any scheme, so the fake client keeps all fields of the unstructured object.
+var testGVK = schema.GroupVersionKind{Group: "test.crossplane.io", Version: "v1", Kind: "Thing"}
+
+// newThing returns a Ready and Synced object of testGVK.
+func newThing(t *testing.T, name string) *unstructured.Unstructured {
+	t.Helper()
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(testGVK)
+	u.SetName(name)
+	setConditions(t, u, xpv1.Available(), xpv1.ReconcileSuccess())
+	return u
+}
+
+func setConditions(t *testing.T, u *unstructured.Unstructured, cs ...xpv1.Condition) {
+	t.Helper()
+
+	conditions := make([]interface{}, 0, len(cs))
+	for i := range cs {
+		c, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&cs[i])
+		require.Nil(t, err)
+		conditions = append(conditions, c)
+	}
+	err := unstructured.SetNestedSlice(u.Object, conditions, "status", "conditions")
+	require.Nil(t, err)
+}
+
+func getThing(t *testing.T, cli client.Client, name string) *unstructured.Unstructured {
+	t.Helper()
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(testGVK)
+	err := cli.Get(context.Background(), client.ObjectKey{Name: name}, u)
+	require.Nil(t, err)
+	return u
+}
+
+func newThingReconciler(cli client.Client) *Reconciler {
+	return &Reconciler{
+		Client:             cli,
+		GroupVersionKind:   testGVK,
+		FrozenTimeDuration: pointer.Duration(DefaultFrozenTimeDuration),
+	}
+}
+
 func TestPause(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = ec2v1beta1.SchemeBuilder.AddToScheme(scheme)
 	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
 	ctx := context.Background()
 
-	subnetTPL := &ec2v1beta1.Subnet{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "test-subnet",
-			Annotations: map[string]string{
-				"some": "value",
-			},
+	subnetTPL := &ec2v1beta1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-subnet",
+			Annotations: map[string]string{
+				"some": "value",
+			},
+		},
+		Spec: ec2v1beta1.SubnetSpec{
+			ForProvider: ec2v1beta1.SubnetParameters{
+				CIDRBlock: "a",
+			},
+		},
+		Status: ec2v1beta1.SubnetStatus{
+			AtProvider: ec2v1beta1.SubnetObservation{
+				SubnetID: "a",
+			},
+		},
+	}
+
+	var err error
+	var subnet *ec2v1beta1.Subnet
+	var u *unstructured.Unstructured
+
+	setValue := func(t *testing.T) {
+		t.Helper()
+		var err error
+
+		u = &unstructured.Unstructured{}
+		u.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(subnet)
+		u.SetGroupVersionKind(ec2v1beta1.SubnetGroupVersionKind)
+		require.Nil(t, err)
+	}
+
+	tmpDuration := time.Hour
+	unPauseInterval := &tmpDuration
+	r := &Reconciler{
+		Client:              cli,
+		UnPausePollInterval: unPauseInterval,
+	}
+
+	// create the object and pause it.
+	subnet = subnetTPL.DeepCopy()
+	err = cli.Create(ctx, subnet)
+	require.Nil(t, err)
+	setValue(t)
+	err = r.ensurePause(ctx, u, nil, "test")
+	require.Nil(t, err)
+	// read back and check
+	u = &unstructured.Unstructured{}
+	u.SetGroupVersionKind(ec2v1beta1.SubnetGroupVersionKind)
+	err = cli.Get(ctx, client.ObjectKeyFromObject(subnetTPL), u)
+	require.Nil(t, err)
+	info, err := r.parsePauseInfo(u)
+	require.Nil(t, err)
+	require.True(t, info.Pause)
+	require.NotNil(t, info.LastPauseTime)
+	require.NotNil(t, info.ShouldUnpauseTime)
+	rate := float64(info.ShouldUnpauseTime.Sub(info.LastPauseTime.Time)) / float64(*unPauseInterval)
+	require.True(t, rate >= 1.0 && rate <= 1.1)
+	require.Equal(t, "true", u.GetAnnotations()[AnnotationKeyReconciliationPaused])
+	// unpause it
+	err = r.ensureUnPause(ctx, u, info, "test")
+	require.Nil(t, err)
+	// read back and check
+	u = &unstructured.Unstructured{}
+	u.SetGroupVersionKind(ec2v1beta1.SubnetGroupVersionKind)
+	err = cli.Get(ctx, client.ObjectKeyFromObject(subnetTPL), u)
+	require.Nil(t, err)
+	info, err = r.parsePauseInfo(u)
+	require.Nil(t, err)
+	require.False(t, info.Pause)
+	require.Nil(t, info.Object)
+	require.NotNil(t, info.LastUnPauseTime)
+	require.Equal(t, "", u.GetAnnotations()[AnnotationKeyReconciliationPaused])
+}
+
+func TestPauseConflict(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = ec2v1beta1.SchemeBuilder.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	ctx := context.Background()
+
+	subnet := &ec2v1beta1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-subnet",
+		},
+	}
+	subnet.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+	err := cli.Create(ctx, subnet)
+	require.Nil(t, err)
+	r := &Reconciler{Client: cli}
+
+	get := func(t *testing.T) *unstructured.Unstructured {
+		t.Helper()
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(ec2v1beta1.SubnetGroupVersionKind)
+		err := cli.Get(ctx, client.ObjectKeyFromObject(subnet), u)
+		require.Nil(t, err)
+		return u
+	}
+
+	// make u stale by updating the object behind it.
+	bump := func(t *testing.T, key, value string) {
+		t.Helper()
+		u := get(t)
+		ann := u.GetAnnotations()
+		if ann == nil {
+			ann = make(map[string]string)
+		}
+		ann[key] = value
+		u.SetAnnotations(ann)
+		err := cli.Update(ctx, u)
+		require.Nil(t, err)
+	}
+
+	// an update with a stale resourceVersion is rejected.
+	u := get(t)
+	bump(t, "some", "value")
+	err = cli.Update(ctx, u.DeepCopy())
+	require.True(t, apierrors.IsConflict(err))
+
+	// we re-fetch and retry the pause.
+	err = r.ensurePause(ctx, u, nil, "test")
+	require.Nil(t, err)
+	u = get(t)
+	info, err := r.parsePauseInfo(u)
+	require.Nil(t, err)
+	require.True(t, info.Pause)
+	require.Equal(t, "true", u.GetAnnotations()[AnnotationKeyReconciliationPaused])
+	require.Equal(t, "value", u.GetAnnotations()["some"])
+
+	// we re-fetch and retry the unpause.
+	bump(t, "other", "value")
+	err = r.ensureUnPause(ctx, u, info, "test")
+	require.Nil(t, err)
+	u = get(t)
+	info, err = r.parsePauseInfo(u)
+	require.Nil(t, err)
+	require.False(t, info.Pause)
+	require.Equal(t, "", u.GetAnnotations()[AnnotationKeyReconciliationPaused])
+	require.Equal(t, "value", u.GetAnnotations()["other"])
+
+	// the resource is not ready anymore after re-fetch, so we don't pause it.
+	u = get(t)
+	notReady := get(t)
+	err = unstructured.SetNestedSlice(notReady.Object, nil, "status", "conditions")
+	require.Nil(t, err)
+	err = cli.Update(ctx, notReady)
+	require.Nil(t, err)
+	err = r.ensurePause(ctx, u, info, "test")
+	require.Nil(t, err)
+	u = get(t)
+	info, err = r.parsePauseInfo(u)
+	require.Nil(t, err)
+	require.False(t, info.Pause)
+	require.Equal(t, "", u.GetAnnotations()[AnnotationKeyReconciliationPaused])
+}
+
+func TestNotReadyRequeue(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = ec2v1beta1.SchemeBuilder.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	ctx := context.Background()
+
+	subnet := &ec2v1beta1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-subnet",
+		},
+	}
+	subnet.SetConditions(xpv1.Available(), xpv1.ReconcileError(errors.New("boom")))
+	err := cli.Create(ctx, subnet)
+	require.Nil(t, err)
+
+	r := &Reconciler{
+		Client:             cli,
+		Scheme:             scheme,
+		GroupVersionKind:   ec2v1beta1.SubnetGroupVersionKind,
+		FrozenTimeDuration: pointer.Duration(DefaultFrozenTimeDuration),
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(subnet)}
+
+	// not requeue by default.
+	res, err := r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, ctrl.Result{}, res)
+
+	r.NotReadyRequeue = time.Minute
+	res, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, res)
+
+	// not requeue once it's paused.
+	subnet.SetConditions(xpv1.ReconcileSuccess())
+	err = cli.Update(ctx, subnet)
+	require.Nil(t, err)
+	res, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, ctrl.Result{}, res)
+	err = cli.Get(ctx, client.ObjectKeyFromObject(subnet), subnet)
+	require.Nil(t, err)
+	require.Equal(t, "true", subnet.GetAnnotations()[AnnotationKeyReconciliationPaused])
+}
+
+func TestVerifyPauseRequeue(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	err := cli.Create(ctx, newThing(t, "thing"))
+	require.Nil(t, err)
+
+	r := newThingReconciler(cli)
+	r.FrozenTimeDuration = pointer.Duration(0)
+	r.VerifyPauseRequeue = time.Second
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}}
+
+	result, err := r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, time.Second, result.RequeueAfter)
+	thing := getThing(t, cli, "thing")
+	require.Equal(t, "true", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+
+	// the pause survives, nothing to do.
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, thing.GetResourceVersion(), getThing(t, cli, "thing").GetResourceVersion())
+
+	strip := func(t *testing.T) {
+		t.Helper()
+		thing := getThing(t, cli, "thing")
+		ann := thing.GetAnnotations()
+		delete(ann, AnnotationKeyReconciliationPaused)
+		thing.SetAnnotations(ann)
+		err := cli.Update(ctx, thing)
+		require.Nil(t, err)
+	}
+
+	// stripped by others, add it back.
+	strip(t)
+	result, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, time.Second, result.RequeueAfter)
+	thing = getThing(t, cli, "thing")
+	require.Equal(t, "true", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+	info, err := r.parsePauseInfo(thing)
+	require.Nil(t, err)
+	require.True(t, info.Pause)
+
+	// stripped and the conditions are not intact, unpause it.
+	strip(t)
+	thing = getThing(t, cli, "thing")
+	setConditions(t, thing, xpv1.Unavailable(), xpv1.ReconcileSuccess())
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	require.Empty(t, thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+	info, err = r.parsePauseInfo(thing)
+	require.Nil(t, err)
+	require.False(t, info.Pause)
+}
+
+func TestVerifyPauseRequeueWindow(t *testing.T) {
+	h := newHarness(t)
+	h.r.VerifyPauseRequeue = time.Minute
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	h.reconcile()
+	paused, _ := h.state()
+	require.True(t, paused)
+
+	// stripped long after the verification, not fought.
+	h.advance(time.Hour)
+	h.mutate(func(thing *unstructured.Unstructured) {
+		ann := thing.GetAnnotations()
+		delete(ann, AnnotationKeyReconciliationPaused)
+		thing.SetAnnotations(ann)
+	})
+	h.reconcile()
+	paused, info := h.state()
+	require.False(t, paused)
+	require.Zero(t, info.PauseRestores)
+}
+
+func TestVerifyPauseRequeueUpdated(t *testing.T) {
+	h := newHarness(t)
+	h.r.VerifyPauseRequeue = time.Minute
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	h.reconcile()
+	paused, _ := h.state()
+	require.True(t, paused)
+
+	// stripped along with an update in the verification, unpause to apply it.
+	h.advance(time.Minute)
+	h.mutate(func(thing *unstructured.Unstructured) {
+		ann := thing.GetAnnotations()
+		delete(ann, AnnotationKeyReconciliationPaused)
+		thing.SetAnnotations(ann)
+		err := unstructured.SetNestedField(thing.Object, "b", "spec", "forProvider", "cidrBlock")
+		require.Nil(t, err)
+	})
+	h.reconcile()
+	paused, info := h.state()
+	require.False(t, paused)
+	require.Zero(t, info.PauseRestores)
+	require.Equal(t, UnpauseReasonUpdated, info.History[len(info.History)-1].Reason)
+}
+
+func TestCheckUnPausePollInterval(t *testing.T) {
+	var logs []string
+	logger := funcr.New(func(prefix, args string) {
+		logs = append(logs, args)
+	}, funcr.Options{})
+	gvk := ec2v1beta1.SubnetGroupVersionKind
+
+	r := &Reconciler{
+		GroupVersionKind:     gvk,
+		UnPausePollInterval:  pointer.Duration(time.Hour),
+		ProviderPollInterval: time.Minute,
+	}
+
+	// safe
+	r.checkUnPausePollInterval(logger)
+	require.Empty(t, logs)
+	require.Equal(t, 0.0, testutil.ToFloat64(unPausePollIntervalTooShort.WithLabelValues(gvk.String())))
+
+	// too short
+	r.UnPausePollInterval = pointer.Duration(time.Minute)
+	r.checkUnPausePollInterval(logger)
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], "UnPausePollInterval is too short")
+	require.Equal(t, 1.0, testutil.ToFloat64(unPausePollIntervalTooShort.WithLabelValues(gvk.String())))
+	require.Equal(t, time.Minute, *r.UnPausePollInterval)
+
+	// clamp
+	logs = nil
+	r.ClampUnPausePollInterval = true
+	r.checkUnPausePollInterval(logger)
+	require.Len(t, logs, 2)
+	require.Equal(t, MinUnPausePollIntervalFactor*time.Minute, *r.UnPausePollInterval)
+
+	// safe after clamped
+	logs = nil
+	r.checkUnPausePollInterval(logger)
+	require.Empty(t, logs)
+	require.Equal(t, 0.0, testutil.ToFloat64(unPausePollIntervalTooShort.WithLabelValues(gvk.String())))
+}
+
+func TestCheckFrozenTimeDuration(t *testing.T) {
+	var logs []string
+	logger := funcr.New(func(prefix, args string) {
+		logs = append(logs, args)
+	}, funcr.Options{})
+	gvk := ec2v1beta1.SubnetGroupVersionKind
+	gauge := frozenTimeDurationTooLong.WithLabelValues(gvk.String())
+
+	r := &Reconciler{
+		GroupVersionKind:    gvk,
+		UnPausePollInterval: pointer.Duration(time.Hour),
+		FrozenTimeDuration:  pointer.Duration(5 * time.Minute),
+	}
+
+	// safe
+	r.checkFrozenTimeDuration(logger)
+	require.Empty(t, logs)
+	require.Equal(t, 0.0, testutil.ToFloat64(gauge))
+
+	// no UnPausePollInterval to conflict with
+	r.UnPausePollInterval = nil
+	r.FrozenTimeDuration = pointer.Duration(2 * time.Hour)
+	r.checkFrozenTimeDuration(logger)
+	require.Empty(t, logs)
+
+	// too long
+	r.UnPausePollInterval = pointer.Duration(time.Hour)
+	r.checkFrozenTimeDuration(logger)
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], "FrozenTimeDuration is not shorter than UnPausePollInterval")
+	require.Equal(t, 1.0, testutil.ToFloat64(gauge))
+	require.Equal(t, 2*time.Hour, *r.FrozenTimeDuration)
+
+	// clamp
+	logs = nil
+	r.ClampFrozenTimeDuration = true
+	r.checkFrozenTimeDuration(logger)
+	require.Len(t, logs, 2)
+	require.Equal(t, 30*time.Minute, *r.FrozenTimeDuration)
+
+	// safe after clamped, the unpaused resource is paused again before the next poll.
+	logs = nil
+	r.checkFrozenTimeDuration(logger)
+	require.Empty(t, logs)
+	require.Equal(t, 0.0, testutil.ToFloat64(gauge))
+	require.Less(t, r.frozenTimeDuration(context.Background(), newThing(t, "thing")), *r.UnPausePollInterval)
+}
+
+func TestPauseInfoBytes(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	thing := newThing(t, "thing")
+	err := unstructured.SetNestedField(thing.Object, strings.Repeat("a", 100*1024), "spec", "forProvider", "userData")
+	require.Nil(t, err)
+	err = cli.Create(ctx, thing)
+	require.Nil(t, err)
+
+	r := newThingReconciler(cli)
+	err = r.ensurePause(ctx, thing, nil, "test")
+	require.Nil(t, err)
+
+	size := len(getThing(t, cli, "thing").GetAnnotations()[AnnotationKeyPauseInfo])
+	require.Greater(t, size, 100*1024)
+	require.Equal(t, float64(size), testutil.ToFloat64(pauseInfoBytes.WithLabelValues(testGVK.String())))
+
+	// the size is never written for the resource gone.
+	err = r.ensurePause(ctx, newThing(t, "gone"), nil, "test")
+	require.Nil(t, err)
+	require.Equal(t, float64(size), testutil.ToFloat64(pauseInfoBytes.WithLabelValues(testGVK.String())))
+}
+
+func TestExternalEvents(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	thing := newThing(t, "thing")
+	err := cli.Create(ctx, thing)
+	require.Nil(t, err)
+
+	events := make(chan event.GenericEvent)
+	r := newThingReconciler(cli)
+	r.UnPausePollInterval = pointer.Duration(time.Nanosecond)
+	r.ExternalEvents = events
+	err = r.ensurePause(ctx, thing, nil, "test")
+	require.Nil(t, err)
+
+	// Watch the ExternalEvents only, so it doesn't need an API server.
+	mgr := newTestManager(t)
+	c, err := controller.NewUnmanaged("external", mgr, controller.Options{Reconciler: r})
+	require.Nil(t, err)
+	err = c.Watch(&source.Channel{Source: r.ExternalEvents}, &handler.EnqueueRequestForObject{})
+	require.Nil(t, err)
+	go func() {
+		_ = c.Start(ctx)
+	}()
+
+	events <- event.GenericEvent{Object: &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "thing"}}}
+	require.Eventually(t, func() bool {
+		info, err := r.parsePauseInfo(getThing(t, cli, "thing"))
+		return err == nil && !info.Pause
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRequireObservedGeneration(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	thing := newThing(t, "thing")
+	thing.SetGeneration(2)
+	err := unstructured.SetNestedField(thing.Object, int64(1), DefaultObservedGenerationPath...)
+	require.Nil(t, err)
+	err = cli.Create(ctx, thing)
+	require.Nil(t, err)
+
+	r := newThingReconciler(cli)
+	r.RequireObservedGeneration = true
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(thing)}
+
+	// lagging
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	require.Equal(t, "", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+
+	// reached
+	err = unstructured.SetNestedField(thing.Object, int64(2), DefaultObservedGenerationPath...)
+	require.Nil(t, err)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	require.Equal(t, "true", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+
+	// custom path
+	thing = newThing(t, "custom")
+	thing.SetGeneration(3)
+	err = unstructured.SetNestedField(thing.Object, int64(3), "status", "observedGeneration")
+	require.Nil(t, err)
+	err = cli.Create(ctx, thing)
+	require.Nil(t, err)
+	req = ctrl.Request{NamespacedName: client.ObjectKeyFromObject(thing)}
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, "", getThing(t, cli, "custom").GetAnnotations()[AnnotationKeyReconciliationPaused])
+	r.ObservedGenerationPath = []string{"status", "observedGeneration"}
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, "true", getThing(t, cli, "custom").GetAnnotations()[AnnotationKeyReconciliationPaused])
+}
+
+func TestGenerationGuard(t *testing.T) {
+	h := newHarness(t)
+	h.r.NotReadyRequeue = time.Minute
+	thing := newThing(t, "thing")
+	thing.SetGeneration(2)
+	err := unstructured.SetNestedField(thing.Object, int64(1), "status", "observedGeneration")
+	require.Nil(t, err)
+	err = h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+
+	// the conditions are left from the generation 1.
+	result := h.reconcile()
+	require.Equal(t, time.Minute, result.RequeueAfter)
+	paused, _ := h.state()
+	require.False(t, paused)
+
+	// opted out.
+	h.r.DisableGenerationGuard = true
+	ready, err := h.r.readyToPause(context.Background(), getThing(t, h.cli, "thing"), &PauseInfo{})
+	require.Nil(t, err)
+	require.True(t, ready)
+	h.r.DisableGenerationGuard = false
+
+	// caught up.
+	h.mutate(func(thing *unstructured.Unstructured) {
+		err := unstructured.SetNestedField(thing.Object, int64(2), "status", "observedGeneration")
+		require.Nil(t, err)
+	})
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+
+	// no status.observedGeneration to check.
+	thing = newThing(t, "other")
+	thing.SetGeneration(2)
+	err = h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+	_, err = h.r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(thing)})
+	require.Nil(t, err)
+	require.Equal(t, "true", getThing(t, h.cli, "other").GetAnnotations()[AnnotationKeyReconciliationPaused])
+}
+
+func TestPausePinned(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	thing := newThing(t, "thing")
+	err := unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+	err = cli.Create(ctx, thing)
+	require.Nil(t, err)
+
+	r := newThingReconciler(cli)
+	r.UnPausePollInterval = pointer.Duration(time.Nanosecond)
+	r.FrozenTimeDuration = pointer.Duration(0)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(thing)}
+
+	// pause and pin it.
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	require.Equal(t, "true", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+	ann := thing.GetAnnotations()
+	ann[AnnotationKeyPausePinned] = "true"
+	thing.SetAnnotations(ann)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+
+	// the poll interval is ignored while pinned.
+	time.Sleep(time.Millisecond)
+	res, err := r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, ctrl.Result{}, res)
+	thing = getThing(t, cli, "thing")
+	require.Equal(t, "true", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+
+	// a spec edit still unpauses it.
+	err = unstructured.SetNestedField(thing.Object, "b", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	require.Equal(t, "", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+
+	// unpin it, the poll interval works again.
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	require.Equal(t, "true", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+	ann = thing.GetAnnotations()
+	delete(ann, AnnotationKeyPausePinned)
+	thing.SetAnnotations(ann)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	require.Equal(t, "", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+}
+
+func TestRespectManualPause(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	err := cli.Create(ctx, newThing(t, "thing"))
+	require.Nil(t, err)
+
+	r := newThingReconciler(cli)
+	r.FrozenTimeDuration = pointer.Duration(0)
+	r.RespectManualPause = true
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}}
+
+	// paused by us, unpause it once updated.
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	thing := getThing(t, cli, "thing")
+	err = unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	require.Empty(t, thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+
+	// paused manually with the stale info, leave it alone.
+	ann := thing.GetAnnotations()
+	ann[AnnotationKeyReconciliationPaused] = "true"
+	thing.SetAnnotations(ann)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, thing.GetResourceVersion(), getThing(t, cli, "thing").GetResourceVersion())
+
+	// take it over by default.
+	r.RespectManualPause = false
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	info, err := r.parsePauseInfo(getThing(t, cli, "thing"))
+	require.Nil(t, err)
+	require.True(t, info.Pause)
+}
+
+func TestSyncedOptional(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		conditions     []xpv1.Condition
+		syncedOptional bool
+		paused         bool
+	}{
+		{name: "never synced", conditions: []xpv1.Condition{xpv1.Available()}, paused: false},
+		{name: "never synced optional", conditions: []xpv1.Condition{xpv1.Available()}, syncedOptional: true, paused: true},
+		{name: "reconcile error optional", conditions: []xpv1.Condition{xpv1.Available(), xpv1.ReconcileError(errors.New("boom"))}, syncedOptional: true, paused: false},
+		{name: "not ready optional", conditions: []xpv1.Condition{xpv1.Creating()}, syncedOptional: true, paused: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().Build()
+			ctx := context.Background()
+
+			thing := newThing(t, "thing")
+			setConditions(t, thing, tc.conditions...)
+			err := cli.Create(ctx, thing)
+			require.Nil(t, err)
+
+			r := newThingReconciler(cli)
+			r.SyncedOptional = tc.syncedOptional
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}})
+			require.Nil(t, err)
+			require.Equal(t, tc.paused, isPaused(getThing(t, cli, "thing").GetAnnotations()[AnnotationKeyReconciliationPaused]))
+		})
+	}
+}
+
+func TestPauseReason(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		conditions []xpv1.Condition
+		reason     string
+	}{
+		{
+			conditions: []xpv1.Condition{xpv1.Available(), xpv1.ReconcileSuccess()},
+			reason:     "Ready=Available, Synced=ReconcileSuccess",
+		},
+		{
+			conditions: []xpv1.Condition{
+				{Type: xpv1.TypeReady, Status: corev1.ConditionTrue, Reason: "Observed"},
+				{Type: xpv1.TypeSynced, Status: corev1.ConditionTrue, Reason: "UpToDate"},
+			},
+			reason: "Ready=Observed, Synced=UpToDate",
+		},
+		{
+			// the missing Synced with TreatMissingSyncedAsTrue.
+			conditions: []xpv1.Condition{xpv1.Available()},
+			reason:     "Ready=Available",
+		},
+		{
+			conditions: []xpv1.Condition{
+				{Type: xpv1.TypeReady, Status: corev1.ConditionTrue},
+				{Type: xpv1.TypeSynced, Status: corev1.ConditionTrue},
+			},
+			reason: "Ready and Synced",
+		},
+	}
+
+	for _, c := range cases {
+		thing := newThing(t, "thing")
+		setConditions(t, thing, c.conditions...)
+		reason, err := newThingReconciler(nil).pauseReason(ctx, thing)
+		require.Nil(t, err)
+		require.Equal(t, c.reason, reason)
+	}
+}
+
+func TestTreatMissingSyncedAsTrue(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		conditions []xpv1.Condition
+		paused     bool
+	}{
+		{name: "missing", conditions: []xpv1.Condition{xpv1.Available()}, paused: true},
+		{name: "false", conditions: []xpv1.Condition{xpv1.Available(), xpv1.ReconcileError(errors.New("boom"))}, paused: false},
+		{name: "unknown", conditions: []xpv1.Condition{xpv1.Available(), {Type: xpv1.TypeSynced, Status: corev1.ConditionUnknown}}, paused: false},
+		{name: "true", conditions: []xpv1.Condition{xpv1.Available(), xpv1.ReconcileSuccess()}, paused: true},
+		{name: "not ready", conditions: []xpv1.Condition{xpv1.Creating()}, paused: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().Build()
+			ctx := context.Background()
+
+			thing := newThing(t, "thing")
+			setConditions(t, thing, tc.conditions...)
+			err := cli.Create(ctx, thing)
+			require.Nil(t, err)
+
+			r := newThingReconciler(cli)
+			r.TreatMissingSyncedAsTrue = true
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}})
+			require.Nil(t, err)
+			require.Equal(t, tc.paused, isPaused(getThing(t, cli, "thing").GetAnnotations()[AnnotationKeyReconciliationPaused]))
+		})
+	}
+}
+
+func TestBlockingConditionReasons(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		conditions []xpv1.Condition
+		paused     bool
+		reason     string
+	}{
+		{name: "absent", conditions: []xpv1.Condition{xpv1.Available(), xpv1.ReconcileSuccess()}, paused: true},
+		{
+			name:       "ready",
+			conditions: []xpv1.Condition{{Type: xpv1.TypeReady, Status: corev1.ConditionTrue, Reason: "AvailableWithWarnings"}, xpv1.ReconcileSuccess()},
+			reason:     "Ready has blocking reason AvailableWithWarnings",
+		},
+		{
+			name:       "synced",
+			conditions: []xpv1.Condition{xpv1.Available(), {Type: xpv1.TypeSynced, Status: corev1.ConditionTrue, Reason: "Modifying"}},
+			reason:     "Synced has blocking reason Modifying",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHarness(t)
+			h.r.NotReadyRequeue = time.Minute
+			h.r.BlockingConditionReasons = []xpv1.ConditionReason{"AvailableWithWarnings", "Modifying"}
+			thing := newThing(t, "thing")
+			setConditions(t, thing, tc.conditions...)
+			err := h.cli.Create(context.Background(), thing)
+			require.Nil(t, err)
+
+			result := h.reconcile()
+			paused, _ := h.state()
+			require.Equal(t, tc.paused, paused)
+			if tc.paused {
+				return
+			}
+			require.Equal(t, time.Minute, result.RequeueAfter)
+
+			blocking, err := h.r.getBlockingCondition(context.Background(), getThing(t, h.cli, "thing"))
+			require.Nil(t, err)
+			require.Equal(t, tc.reason, blocking.String())
+		})
+	}
+}
+
+func TestSpecless(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec func(u *unstructured.Unstructured)
+	}{
+		{name: "absent", spec: func(u *unstructured.Unstructured) {}},
+		{name: "null", spec: func(u *unstructured.Unstructured) { u.Object["spec"] = nil }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().Build()
+			ctx := context.Background()
+
+			thing := newThing(t, "thing")
+			tc.spec(thing)
+			err := cli.Create(ctx, thing)
+			require.Nil(t, err)
+			// neither spec nor status.
+			bare := &unstructured.Unstructured{}
+			bare.SetGroupVersionKind(testGVK)
+			bare.SetName("bare")
+			bare.Object["status"] = nil
+			err = cli.Create(ctx, bare)
+			require.Nil(t, err)
+
+			r := newThingReconciler(cli)
+			r.FrozenTimeDuration = pointer.Duration(0)
+			reconcileThing := func(t *testing.T, name string) *PauseInfo {
+				t.Helper()
+				_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: name}})
+				require.Nil(t, err)
+				info, err := r.parsePauseInfo(getThing(t, cli, name))
+				require.Nil(t, err)
+				return info
+			}
+
+			require.Nil(t, reconcileThing(t, "bare"))
+
+			info := reconcileThing(t, "thing")
+			require.True(t, info.Pause)
+			// no spurious unpause.
+			for i := 0; i < 2; i++ {
+				info = reconcileThing(t, "thing")
+				require.True(t, info.Pause)
+			}
+
+			// a spec is added.
+			thing = getThing(t, cli, "thing")
+			delete(thing.Object, "spec")
+			err = unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+			require.Nil(t, err)
+			err = cli.Update(ctx, thing)
+			require.Nil(t, err)
+			info = reconcileThing(t, "thing")
+			require.False(t, info.Pause)
+			require.Equal(t, UnpauseReasonUpdated, info.History[len(info.History)-1].Reason)
+		})
+	}
+}
+
+func TestRolloutPercentage(t *testing.T) {
+	r := &Reconciler{RolloutPercentage: pointer.Int(10)}
+
+	things := make([]*unstructured.Unstructured, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		thing := newThing(t, fmt.Sprintf("thing-%d", i))
+		thing.SetUID(types.UID(fmt.Sprintf("uid-%d", i)))
+		things = append(things, thing)
+	}
+
+	selected := make(map[string]bool)
+	for _, thing := range things {
+		if r.inRollout(thing) {
+			selected[thing.GetName()] = true
+		}
+	}
+	require.InDelta(t, 100, len(selected), 40)
+
+	// stable and raising it only adds resources.
+	r.RolloutPercentage = pointer.Int(50)
+	more := 0
+	for _, thing := range things {
+		in := r.inRollout(thing)
+		if selected[thing.GetName()] {
+			require.True(t, in)
+		}
+		if in {
+			more++
+		}
+	}
+	require.InDelta(t, 500, more, 100)
+
+	// out of the rollout, keep polling.
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+	err := cli.Create(ctx, things[0])
+	require.Nil(t, err)
+	r = newThingReconciler(cli)
+	r.RolloutPercentage = pointer.Int(0)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(things[0])}
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Empty(t, getThing(t, cli, things[0].GetName()).GetAnnotations()[AnnotationKeyReconciliationPaused])
+
+	r.RolloutPercentage = pointer.Int(100)
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, "true", getThing(t, cli, things[0].GetName()).GetAnnotations()[AnnotationKeyReconciliationPaused])
+}
+
+func TestOnboardAfterAge(t *testing.T) {
+	h := newHarness(t)
+	h.r.OnboardAfterAge = 30 * 24 * time.Hour
+	ctx := context.Background()
+
+	ages := map[string]time.Duration{
+		"thing-1d":   24 * time.Hour,
+		"thing-10d":  10 * 24 * time.Hour,
+		"thing-60d":  60 * 24 * time.Hour,
+		"thing-365d": 365 * 24 * time.Hour,
+	}
+	for name, age := range ages {
+		thing := newThing(t, name)
+		thing.SetCreationTimestamp(metav1.NewTime(h.clock.Now().Add(-age)))
+		err := h.cli.Create(ctx, thing)
+		require.Nil(t, err)
+	}
+
+	onboard := func() map[string]time.Duration {
+		requeues := make(map[string]time.Duration)
+		for name := range ages {
+			result, err := h.r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: name}})
+			require.Nil(t, err)
+			requeues[name] = result.RequeueAfter
+		}
+		return requeues
+	}
+	paused := func(name string) bool {
+		return isPaused(getThing(t, h.cli, name).GetAnnotations()[AnnotationKeyReconciliationPaused])
+	}
+
+	// only the old cohort is onboarded, the young ones are requeued until they're old enough.
+	requeues := onboard()
+	require.True(t, paused("thing-60d"))
+	require.True(t, paused("thing-365d"))
+	require.False(t, paused("thing-1d"))
+	require.False(t, paused("thing-10d"))
+	require.Equal(t, 20*24*time.Hour, requeues["thing-10d"])
+	require.Equal(t, 29*24*time.Hour, requeues["thing-1d"])
+
+	// lower the threshold to onboard the next cohort.
+	h.r.OnboardAfterAge = 7 * 24 * time.Hour
+	onboard()
+	require.True(t, paused("thing-10d"))
+	require.False(t, paused("thing-1d"))
+
+	// fully rolled out.
+	h.r.OnboardAfterAge = 0
+	onboard()
+	require.True(t, paused("thing-1d"))
+}
+
+func TestLogConfig(t *testing.T) {
+	var logs []string
+	logger := funcr.New(func(prefix, args string) {
+		logs = append(logs, args)
+	}, funcr.Options{})
+	mgr := newTestManager(t, func(o *manager.Options) {
+		o.Logger = logger
+	})
+
+	r := &Reconciler{
+		Client:                   mgr.GetClient(),
+		GroupVersionKind:         testGVK,
+		UnPausePollInterval:      pointer.Duration(time.Minute),
+		ProviderPollInterval:     time.Minute,
+		ClampUnPausePollInterval: true,
+		WatchFinalizers:          true,
+	}
+	err := r.SetupWithManager(mgr)
+	require.Nil(t, err)
+
+	var config string
+	for _, l := range logs {
+		if strings.Contains(l, `"msg"="reconciler config"`) {
+			config = l
+		}
+	}
+	require.NotEmpty(t, config)
+	for _, field := range []string{
+		`"gvk"="test.crossplane.io/v1, Kind=Thing"`,
+		// post clamping
+		`"unPausePollInterval"="2m0s"`,
+		// post defaulting
+		`"frozenTimeDuration"="5m0s"`,
+		`"maxConcurrentReconciles"=10`,
+		`"requiredConditions"=["Ready","Synced"]`,
+		`"observedGenerationPath"="status.atProvider.observedGeneration"`,
+		`"watchFinalizers"=true`,
+	} {
+		require.Contains(t, config, field)
+	}
+}
+
+func TestSetupWithManager(t *testing.T) {
+	mgr := newTestManager(t)
+	r := newThingReconciler(mgr.GetClient())
+	r.SettingsConfigMap = &types.NamespacedName{Namespace: "default", Name: "settings"}
+	r.ExternalEvents = make(chan event.GenericEvent)
+	err := r.SetupWithManager(mgr)
+	require.Nil(t, err)
+	// the settings config map is read from the cache of it alone.
+	require.NotNil(t, r.settingsReader)
+
+	r = newThingReconciler(mgr.GetClient())
+	r.GroupVersionKind = testGVK.GroupVersion().WithKind("NotRegistered")
+	err = r.SetupWithManager(mgr)
+	require.ErrorContains(t, err, "is the CRD installed")
+
+	r.GroupVersionKind = schema.GroupVersionKind{}
+	err = r.SetupWithManager(mgr)
+	require.ErrorContains(t, err, "invalid GroupVersionKind")
+}
+
+func TestSetupWithManagerScheme(t *testing.T) {
+	mgr := newTestManager(t)
+	r := newThingReconciler(mgr.GetClient())
+	r.Scheme = runtime.NewScheme()
+	err := r.SetupWithManager(mgr)
+	require.ErrorContains(t, err, "test.crossplane.io/v1, Kind=Thing is not registered in the Scheme")
+
+	r.Scheme.AddKnownTypeWithName(testGVK, &unstructured.Unstructured{})
+	err = r.SetupWithManager(mgr)
+	require.Nil(t, err)
+}
+
+func TestSetupWithManagerExample(t *testing.T) {
+	mgr := newTestManager(t, func(o *manager.Options) {
+		o.MapperProvider = func(c *rest.Config) (meta.RESTMapper, error) {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(ec2v1beta1.SubnetGroupVersionKind, meta.RESTScopeRoot)
+			return mapper, nil
+		}
+	})
+
+	// wired like cmd/example.go.
+	err := ec2v1beta1.SchemeBuilder.AddToScheme(mgr.GetScheme())
+	require.Nil(t, err)
+	r := Reconciler{
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		GroupVersionKind:    ec2v1beta1.SubnetGroupVersionKind,
+		UnPausePollInterval: pointer.Duration(time.Hour * 5),
+		FrozenTimeDuration:  pointer.Duration(time.Minute * 5),
+	}
+	err = r.SetupWithManager(mgr)
+	require.Nil(t, err)
+}
+
+func TestControllerOptions(t *testing.T) {
+	r := newThingReconciler(nil)
+	opts := r.controllerOptions()
+	require.Equal(t, maxConcurrentReconciles, opts.MaxConcurrentReconciles)
+	require.Equal(t, r, opts.Reconciler)
+
+	r.UseDefaultConcurrency = true
+	require.Equal(t, controller.Options{Reconciler: r}, r.controllerOptions())
+}
+
+func TestFrozenDurationAnnotation(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+	r := newThingReconciler(cli)
+
+	lastUnPauseTime := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	create := func(t *testing.T, name string, frozenDuration *string) ctrl.Request {
+		t.Helper()
+		thing := newThing(t, name)
+		err := r.setPauseInfo(thing, &PauseInfo{LastUnPauseTime: &lastUnPauseTime})
+		require.Nil(t, err)
+		if frozenDuration != nil {
+			ann := thing.GetAnnotations()
+			ann[AnnotationKeyFrozenDuration] = *frozenDuration
+			thing.SetAnnotations(ann)
+		}
+		err = cli.Create(ctx, thing)
+		require.Nil(t, err)
+		return ctrl.Request{NamespacedName: client.ObjectKeyFromObject(thing)}
+	}
+
+	for _, tc := range []struct {
+		name           string
+		frozenDuration *string
+		paused         bool
+	}{
+		{name: "absent", frozenDuration: nil, paused: false},
+		{name: "valid", frozenDuration: pointer.String("1m"), paused: true},
+		{name: "invalid", frozenDuration: pointer.String("abc"), paused: false},
+		{name: "negative", frozenDuration: pointer.String("-1m"), paused: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := create(t, tc.name, tc.frozenDuration)
+			res, err := r.Reconcile(ctx, req)
+			require.Nil(t, err)
+			thing := getThing(t, cli, tc.name)
+			if tc.paused {
+				require.Equal(t, ctrl.Result{}, res)
+				require.Equal(t, "true", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+			} else {
+				// the default 5m frozen duration
+				require.True(t, res.RequeueAfter > 2*time.Minute && res.RequeueAfter <= 3*time.Minute)
+				require.Equal(t, "", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+			}
+		})
+	}
+}
+
+func TestLogBlockingCondition(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	var logs []string
+	logger := funcr.New(func(prefix, args string) {
+		logs = append(logs, args)
+	}, funcr.Options{Verbosity: 1})
+	ctx := log.IntoContext(context.Background(), logger)
+	r := newThingReconciler(cli)
+
+	for _, tc := range []struct {
+		name       string
+		conditions []xpv1.Condition
+		expected   []string
+	}{
+		{
+			name:       "ready-missing",
+			conditions: []xpv1.Condition{xpv1.ReconcileSuccess()},
+			expected:   []string{`"condition"="Ready"`, `"status"="Missing"`},
 		},
-		Spec: ec2v1beta1.SubnetSpec{
-			ForProvider: ec2v1beta1.SubnetParameters{
-				CIDRBlock: "a",
-			},
+		{
+			name:       "ready-false",
+			conditions: []xpv1.Condition{xpv1.Creating(), xpv1.ReconcileSuccess()},
+			expected:   []string{`"condition"="Ready"`, `"status"="False"`, `"reason"="Creating"`},
 		},
-		Status: ec2v1beta1.SubnetStatus{
-			AtProvider: ec2v1beta1.SubnetObservation{
-				SubnetID: "a",
-			},
+		{
+			name:       "synced-false",
+			conditions: []xpv1.Condition{xpv1.Available(), xpv1.ReconcileError(errors.New("boom"))},
+			expected:   []string{`"condition"="Synced"`, `"status"="False"`, `"reason"="ReconcileError"`},
 		},
+		{
+			name:       "synced-unknown",
+			conditions: []xpv1.Condition{xpv1.Available(), {Type: xpv1.TypeSynced, Status: corev1.ConditionUnknown, Reason: "Unknown"}},
+			expected:   []string{`"condition"="Synced"`, `"status"="Unknown"`, `"reason"="Unknown"`},
+		},
+		{
+			name:       "synced-missing",
+			conditions: []xpv1.Condition{xpv1.Available()},
+			expected:   []string{`"condition"="Synced"`, `"status"="Missing"`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			logs = nil
+			thing := newThing(t, tc.name)
+			setConditions(t, thing, tc.conditions...)
+			err := cli.Create(ctx, thing)
+			require.Nil(t, err)
+
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(thing)})
+			require.Nil(t, err)
+
+			var blockingLog string
+			for _, l := range logs {
+				if strings.Contains(l, "not pause since the condition is not true") {
+					blockingLog = l
+				}
+			}
+			require.NotEmpty(t, blockingLog)
+			for _, e := range tc.expected {
+				require.Contains(t, blockingLog, e)
+			}
+		})
 	}
+}
 
-	var err error
-	var subnet *ec2v1beta1.Subnet
-	var u *unstructured.Unstructured
+func TestLogReconcileDecision(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	var logs []string
+	logger := funcr.New(func(prefix, args string) {
+		logs = append(logs, args)
+	}, funcr.Options{})
+	ctx := log.IntoContext(context.Background(), logger)
 
-	setValue := func(t *testing.T) {
-		t.Helper()
-		var err error
+	err := cli.Create(ctx, newThing(t, "thing"))
+	require.Nil(t, err)
+	r := newThingReconciler(cli)
+	r.UnPausePollInterval = pointer.Duration(time.Hour)
+	r.UnPausePollJitter = pointer.Float64(0)
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}}
 
-		u = &unstructured.Unstructured{}
-		u.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(subnet)
-		u.SetGroupVersionKind(ec2v1beta1.SubnetGroupVersionKind)
+	decisionLog := func(t *testing.T) string {
+		t.Helper()
+		logs = nil
+		_, err := r.Reconcile(ctx, req)
 		require.Nil(t, err)
+		for _, l := range logs {
+			if strings.Contains(l, "reconcile decision") {
+				return l
+			}
+		}
+		require.FailNow(t, "missing the reconcile decision log")
+		return ""
 	}
 
-	tmpDuration := time.Hour
-	unPauseInterval := &tmpDuration
+	l := decisionLog(t)
+	require.Contains(t, l, `"action"="Pause"`)
+	require.Contains(t, l, `"reason"="Ready=Available, Synced=ReconcileSuccess"`)
+	require.NotContains(t, l, "requeueAfter")
 
-	// create the object and pause it.
-	subnet = subnetTPL.DeepCopy()
-	err = cli.Create(ctx, subnet)
+	// requeue to unpause by the UnPausePollInterval.
+	l = decisionLog(t)
+	require.Contains(t, l, `"action"="KeepPaused"`)
+	require.Contains(t, l, `"reason"="wait for the UnPausePollInterval"`)
+	require.Regexp(t, `"requeueAfter"="[0-9]+m[0-9.]+s"`, l)
+}
+
+func TestConsolidatedLog(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		consolidated bool
+		verbosity    int
+		expected     []string
+	}{
+		{name: "default", expected: []string{"Start reconcile", "reconcile decision", "Finish reconcile"}},
+		{name: "consolidated", consolidated: true, expected: []string{"reconcile decision"}},
+		{name: "consolidated verbose", consolidated: true, verbosity: 1, expected: []string{"Start reconcile", "reconcile decision"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().Build()
+			var logs []string
+			logger := funcr.New(func(prefix, args string) {
+				for _, msg := range []string{"Start reconcile", "reconcile decision", "Finish reconcile"} {
+					if strings.Contains(args, fmt.Sprintf(`"msg"=%q`, msg)) {
+						logs = append(logs, args)
+					}
+				}
+			}, funcr.Options{Verbosity: tc.verbosity})
+			ctx := log.IntoContext(context.Background(), logger)
+
+			err := cli.Create(ctx, newThing(t, "thing"))
+			require.Nil(t, err)
+			r := newThingReconciler(cli)
+			r.ConsolidatedLog = tc.consolidated
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}})
+			require.Nil(t, err)
+
+			require.Len(t, logs, len(tc.expected))
+			for i, msg := range tc.expected {
+				require.Contains(t, logs[i], msg)
+			}
+			// the duration is folded into the decision log.
+			decision := logs[len(logs)-1]
+			if !tc.consolidated {
+				decision = logs[1]
+			}
+			require.Contains(t, decision, `"action"="Pause"`)
+			require.Equal(t, tc.consolidated, strings.Contains(decision, `"take"=`))
+		})
+	}
+}
+
+func TestOnReconcile(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+	err := cli.Create(ctx, newThing(t, "thing"))
 	require.Nil(t, err)
-	setValue(t)
-	err = ensurePause(ctx, cli, u, nil, unPauseInterval, "test")
+
+	type outcome struct {
+		req    ctrl.Request
+		action Action
+		res    ctrl.Result
+		err    error
+	}
+	var outcomes []outcome
+	r := newThingReconciler(cli)
+	r.VerifyPauseRequeue = time.Minute
+	r.OnReconcile = func(req ctrl.Request, action Action, res ctrl.Result, err error) {
+		outcomes = append(outcomes, outcome{req: req, action: action, res: res, err: err})
+	}
+
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}}
+	res, err := r.Reconcile(ctx, req)
 	require.Nil(t, err)
-	// read back and check
-	u = &unstructured.Unstructured{}
-	u.SetGroupVersionKind(ec2v1beta1.SubnetGroupVersionKind)
-	err = cli.Get(ctx, client.ObjectKeyFromObject(subnetTPL), u)
+	require.Equal(t, []outcome{{req: req, action: ActionPause, res: ctrl.Result{RequeueAfter: time.Minute}}}, outcomes)
+	require.Equal(t, outcomes[0].res, res)
+
+	outcomes = nil
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "absent"}})
+	require.Nil(t, err)
+	require.Len(t, outcomes, 1)
+	require.Equal(t, ActionNone, outcomes[0].action)
+}
+
+// updateErrorClient returns err on Update.
+type updateErrorClient struct {
+	client.Client
+	err error
+}
+
+func (c *updateErrorClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.err
+}
+
+func TestUpdateNotFound(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	thing := newThing(t, "thing")
+	err := cli.Create(ctx, thing)
 	require.Nil(t, err)
-	info, err := parsePauseInfo(u)
+
+	// the object is deleted between Get and Update.
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: testGVK.Group, Resource: "things"}, "thing")
+	r := newThingReconciler(&updateErrorClient{Client: cli, err: notFound})
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(thing)}
+	res, err := r.Reconcile(ctx, req)
 	require.Nil(t, err)
-	require.True(t, info.Pause)
-	require.NotNil(t, info.LastPauseTime)
-	require.NotNil(t, info.ShouldUnpauseTime)
-	rate := float64(info.ShouldUnpauseTime.Sub(info.LastPauseTime.Time)) / float64(*unPauseInterval)
-	require.True(t, rate >= 1.0 && rate <= 1.1)
-	require.Equal(t, "true", u.GetAnnotations()[AnnotationKeyReconciliationPaused])
-	// unpause it
-	err = ensureUnPause(ctx, cli, u, info, "test")
+	require.Equal(t, ctrl.Result{}, res)
+
+	// unpause
+	r.Client = cli
+	_, err = r.Reconcile(ctx, req)
 	require.Nil(t, err)
-	// read back and check
-	u = &unstructured.Unstructured{}
-	u.SetGroupVersionKind(ec2v1beta1.SubnetGroupVersionKind)
-	err = cli.Get(ctx, client.ObjectKeyFromObject(subnetTPL), u)
+	thing = getThing(t, cli, "thing")
+	info, err := r.parsePauseInfo(thing)
+	require.Nil(t, err)
+	r.Client = &updateErrorClient{Client: cli, err: notFound}
+	err = r.ensureUnPause(ctx, thing, info, "test")
+	require.Nil(t, err)
+
+	// other errors are still returned.
+	thing = getThing(t, cli, "thing")
+	info, err = r.parsePauseInfo(thing)
+	require.Nil(t, err)
+	r.Client = &updateErrorClient{Client: cli, err: errors.New("boom")}
+	err = r.ensureUnPause(ctx, thing, info, "test")
+	require.NotNil(t, err)
+}
+
+func TestSoftUnpause(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	thing := newThing(t, "thing")
+	err := unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+	err = cli.Create(ctx, thing)
+	require.Nil(t, err)
+
+	r := newThingReconciler(cli)
+	r.UnPausePollInterval = pointer.Duration(time.Nanosecond)
+	r.FrozenTimeDuration = pointer.Duration(0)
+	r.SoftUnpause = true
+	r.ForceUnpauseEvery = 3
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(thing)}
+
+	reconcile := func(t *testing.T) (*unstructured.Unstructured, *PauseInfo) {
+		t.Helper()
+		_, err := r.Reconcile(ctx, req)
+		require.Nil(t, err)
+		thing := getThing(t, cli, "thing")
+		info, err := r.parsePauseInfo(thing)
+		require.Nil(t, err)
+		return thing, info
+	}
+
+	_, info := reconcile(t)
+	require.True(t, info.Pause)
+	lastPauseTime := info.LastPauseTime
+
+	// not drifted, stay paused and extend.
+	for i := 1; i < r.ForceUnpauseEvery; i++ {
+		_, info = reconcile(t)
+		require.True(t, info.Pause)
+		require.Equal(t, i, info.SkippedUnpauses)
+		require.True(t, lastPauseTime.Equal(info.LastPauseTime))
+		require.False(t, info.ShouldUnpauseTime.Before(lastPauseTime))
+	}
+
+	// force unpause after ForceUnpauseEvery intervals.
+	_, info = reconcile(t)
+	require.False(t, info.Pause)
+	require.Equal(t, 0, info.SkippedUnpauses)
+
+	// drifted, unpause.
+	thing, info = reconcile(t)
+	require.True(t, info.Pause)
+	err = unstructured.SetNestedField(thing.Object, "b", "spec", "forProvider", "cidrBlock")
 	require.Nil(t, err)
-	info, err = parsePauseInfo(u)
+	err = cli.Update(ctx, thing)
 	require.Nil(t, err)
+	_, info = reconcile(t)
 	require.False(t, info.Pause)
-	require.Nil(t, info.Object)
-	require.NotNil(t, info.LastUnPauseTime)
-	require.Equal(t, "", u.GetAnnotations()[AnnotationKeyReconciliationPaused])
+
+	// never force unpause if ForceUnpauseEvery is not positive.
+	r.ForceUnpauseEvery = 0
+	_, info = reconcile(t)
+	require.True(t, info.Pause)
+	for i := 1; i <= 5; i++ {
+		_, info = reconcile(t)
+		require.True(t, info.Pause)
+		require.Equal(t, i, info.SkippedUnpauses)
+	}
+}
+
+func TestPredicate(t *testing.T) {
+	thing := newThing(t, "thing")
+	called := make(map[string]int)
+	newPredicate := func(name string, pass bool) predicate.Predicate {
+		return predicate.NewPredicateFuncs(func(client.Object) bool {
+			called[name]++
+			return pass
+		})
+	}
+
+	r := &Reconciler{
+		Predicates: []predicate.Predicate{newPredicate("field", true)},
+	}
+
+	// no predicates passed
+	require.True(t, r.predicate().Create(event.CreateEvent{Object: thing}))
+	require.Equal(t, 1, called["field"])
+
+	// all pass
+	p := r.predicate(newPredicate("arg1", true), newPredicate("arg2", true))
+	require.True(t, p.Create(event.CreateEvent{Object: thing}))
+	require.Equal(t, 2, called["field"])
+	require.Equal(t, 1, called["arg1"])
+	require.Equal(t, 1, called["arg2"])
+
+	// one of the passed predicates rejects
+	p = r.predicate(newPredicate("reject", false))
+	require.False(t, p.Update(event.UpdateEvent{ObjectOld: thing, ObjectNew: thing}))
+	require.Equal(t, 3, called["field"])
+	require.Equal(t, 1, called["reject"])
+
+	// the field predicate rejects
+	r.Predicates = []predicate.Predicate{newPredicate("field-reject", false)}
+	p = r.predicate(newPredicate("arg1", true))
+	require.False(t, p.Delete(event.DeleteEvent{Object: thing}))
+	require.Equal(t, 1, called["field-reject"])
+}
+
+func TestDisableUnpauseOnUpdate(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	thing := newThing(t, "thing")
+	thing.SetFinalizers([]string{"finalizer.crossplane.io"})
+	err := unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+	err = cli.Create(ctx, thing)
+	require.Nil(t, err)
+
+	r := newThingReconciler(cli)
+	r.DisableUnpauseOnUpdate = true
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(thing)}
+
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	require.Equal(t, "true", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+
+	// a spec change doesn't unpause it.
+	err = unstructured.SetNestedField(thing.Object, "b", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	require.Equal(t, "true", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+
+	// the deletion still unpauses it.
+	err = cli.Delete(ctx, thing)
+	require.Nil(t, err)
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+	thing = getThing(t, cli, "thing")
+	require.Equal(t, "", thing.GetAnnotations()[AnnotationKeyReconciliationPaused])
+}
+
+func TestDisableUnpauseOnUpdateSoftUnpause(t *testing.T) {
+	h := newHarness(t)
+	h.r.DisableUnpauseOnUpdate = true
+	h.r.SoftUnpause = true
+	h.r.UnPausePollInterval = pointer.Duration(time.Hour)
+	h.r.UnPausePollJitter = pointer.Float64(0)
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	h.reconcile()
+	paused, _ := h.state()
+	require.True(t, paused)
+
+	// not drifted, the pause is extended.
+	h.advance(time.Hour)
+	h.reconcile()
+	paused, info := h.state()
+	require.True(t, paused)
+	require.Equal(t, 1, info.SkippedUnpauses)
+
+	// the spec change doesn't unpause it by itself, but the pause is not extended over it.
+	h.mutate(func(thing *unstructured.Unstructured) {
+		err := unstructured.SetNestedField(thing.Object, "b", "spec", "forProvider", "cidrBlock")
+		require.Nil(t, err)
+	})
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+	h.advance(time.Hour)
+	h.reconcile()
+	paused, info = h.state()
+	require.False(t, paused)
+	require.Equal(t, UnpauseReasonPollInterval, info.History[len(info.History)-1].Reason)
+}
+
+func TestUnpauseOnDeletion(t *testing.T) {
+	for _, unpause := range []*bool{nil, pointer.Bool(true), pointer.Bool(false)} {
+		cli := fake.NewClientBuilder().Build()
+		ctx := context.Background()
+
+		thing := newThing(t, "thing")
+		thing.SetFinalizers([]string{"test"})
+		err := cli.Create(ctx, thing)
+		require.Nil(t, err)
+
+		r := newThingReconciler(cli)
+		r.UnpauseOnDeletion = unpause
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(thing)}
+		_, err = r.Reconcile(ctx, req)
+		require.Nil(t, err)
+
+		err = cli.Delete(ctx, getThing(t, cli, "thing"))
+		require.Nil(t, err)
+		thing = getThing(t, cli, "thing")
+		require.False(t, thing.GetDeletionTimestamp().IsZero())
+
+		_, err = r.Reconcile(ctx, req)
+		require.Nil(t, err)
+		thing = getThing(t, cli, "thing")
+		info, err := r.parsePauseInfo(thing)
+		require.Nil(t, err)
+		paused := unpause != nil && !*unpause
+		require.Equal(t, paused, info.Pause)
+		require.Equal(t, paused, isPaused(thing.GetAnnotations()[AnnotationKeyReconciliationPaused]))
+
+		// The one deleted while unpaused is never paused.
+		other := newThing(t, "other")
+		other.SetFinalizers([]string{"test"})
+		err = cli.Create(ctx, other)
+		require.Nil(t, err)
+		err = cli.Delete(ctx, getThing(t, cli, "other"))
+		require.Nil(t, err)
+
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(other)})
+		require.Nil(t, err)
+		other = getThing(t, cli, "other")
+		require.False(t, other.GetDeletionTimestamp().IsZero())
+		require.False(t, isPaused(other.GetAnnotations()[AnnotationKeyReconciliationPaused]))
+	}
 }
 
 func TestIsUpdated(t *testing.T) {
@@ -117,6 +1676,7 @@ func TestIsUpdated(t *testing.T) {
 
 	var oldSubnet, nowSubnet *ec2v1beta1.Subnet
 	var old, now *unstructured.Unstructured
+	r := &Reconciler{}
 
 	setValue := func(t *testing.T) {
 		t.Helper()
@@ -137,7 +1697,7 @@ func TestIsUpdated(t *testing.T) {
 	nowSubnet.Annotations[AnnotationKeyPauseInfo] = "value"
 	nowSubnet.Annotations[AnnotationKeyReconciliationPaused] = "true"
 	setValue(t)
-	updated, err := isUpdated(ctx, old, now)
+	updated, err := r.isUpdated(ctx, old, now)
 	require.Nil(t, err)
 	require.False(t, updated)
 
@@ -146,7 +1706,7 @@ func TestIsUpdated(t *testing.T) {
 	nowSubnet = subnet.DeepCopy()
 	nowSubnet.Spec.ForProvider.CIDRBlock = "b"
 	setValue(t)
-	updated, err = isUpdated(ctx, old, now)
+	updated, err = r.isUpdated(ctx, old, now)
 	require.Nil(t, err)
 	require.True(t, updated)
 
@@ -155,7 +1715,7 @@ func TestIsUpdated(t *testing.T) {
 	nowSubnet = subnet.DeepCopy()
 	nowSubnet.Labels = map[string]string{"a": "b"}
 	setValue(t)
-	updated, err = isUpdated(ctx, old, now)
+	updated, err = r.isUpdated(ctx, old, now)
 	require.Nil(t, err)
 	require.True(t, updated)
 
@@ -164,9 +1724,96 @@ func TestIsUpdated(t *testing.T) {
 	nowSubnet = subnet.DeepCopy()
 	nowSubnet.Annotations = map[string]string{"a": "b"}
 	setValue(t)
-	updated, err = isUpdated(ctx, old, now)
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.True(t, updated)
+
+	// test finalizer added
+	oldSubnet = subnet.DeepCopy()
+	oldSubnet.Finalizers = []string{"a"}
+	nowSubnet = subnet.DeepCopy()
+	nowSubnet.Finalizers = []string{"a", "b"}
+	setValue(t)
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.False(t, updated)
+	r.WatchFinalizers = true
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.True(t, updated)
+
+	// test finalizer removed
+	oldSubnet = subnet.DeepCopy()
+	oldSubnet.Finalizers = []string{"a"}
+	nowSubnet = subnet.DeepCopy()
+	setValue(t)
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.True(t, updated)
+
+	// test finalizer reordered
+	oldSubnet = subnet.DeepCopy()
+	oldSubnet.Finalizers = []string{"a", "b"}
+	nowSubnet = subnet.DeepCopy()
+	nowSubnet.Finalizers = []string{"b", "a"}
+	setValue(t)
+	updated, err = r.isUpdated(ctx, old, now)
+	require.Nil(t, err)
+	require.False(t, updated)
+}
+
+func TestSpecEqual(t *testing.T) {
+	var logs []string
+	// the diff is logged at the verbosity 1.
+	logger := funcr.New(func(prefix, args string) {
+		logs = append(logs, args)
+	}, funcr.Options{Verbosity: 1})
+	ctx := log.IntoContext(context.Background(), logger)
+
+	newObj := func(arn string) *unstructured.Unstructured {
+		u := newThing(t, "thing")
+		err := unstructured.SetNestedField(u.Object, arn, "spec", "forProvider", "roleArn")
+		require.Nil(t, err)
+		return u
+	}
+
+	// ARNs are case-insensitive.
+	r := &Reconciler{
+		SpecEqual: func(old, now map[string]interface{}) (bool, error) {
+			oldARN, _, err := unstructured.NestedString(old, "forProvider", "roleArn")
+			if err != nil {
+				return false, err
+			}
+			nowARN, _, err := unstructured.NestedString(now, "forProvider", "roleArn")
+			if err != nil {
+				return false, err
+			}
+			return strings.EqualFold(oldARN, nowARN), nil
+		},
+	}
+
+	updated, err := (&Reconciler{}).isUpdated(ctx, newObj("arn:aws:iam::1:role/A"), newObj("arn:aws:iam::1:role/a"))
+	require.Nil(t, err)
+	require.True(t, updated)
+
+	logs = nil
+	updated, err = r.isUpdated(ctx, newObj("arn:aws:iam::1:role/A"), newObj("arn:aws:iam::1:role/a"))
+	require.Nil(t, err)
+	require.False(t, updated)
+	require.Empty(t, logs)
+
+	updated, err = r.isUpdated(ctx, newObj("arn:aws:iam::1:role/A"), newObj("arn:aws:iam::1:role/B"))
 	require.Nil(t, err)
 	require.True(t, updated)
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], "field not equal")
+	require.Contains(t, logs[0], "role/B")
+
+	r.SpecEqual = func(old, now map[string]interface{}) (bool, error) {
+		return false, errors.New("boom")
+	}
+	_, err = r.isUpdated(ctx, newObj("a"), newObj("a"))
+	require.ErrorContains(t, err, "boom")
 }
 
 func TestGetCondition(t *testing.T) {
@@ -202,23 +1849,240 @@ func TestGetCondition(t *testing.T) {
 	err = cli.Get(ctx, client.ObjectKeyFromObject(&subnet), u)
 	require.Nil(t, err)
 
-	res, err := getCondition(u, xpv1.TypeReady)
+	res, err := getCondition(ctx, u, xpv1.TypeReady)
 	require.Nil(t, err)
 	require.Equal(t, available, *res)
 
-	res, err = getCondition(u, xpv1.TypeSynced)
+	res, err = getCondition(ctx, u, xpv1.TypeSynced)
 	require.Nil(t, err)
 	require.Equal(t, success, *res)
 
-	res, err = getCondition(u, xpv1.ConditionType("not-exist"))
+	res, err = getCondition(ctx, u, xpv1.ConditionType("not-exist"))
 	require.Nil(t, err)
 	require.Nil(t, res)
 	return
 }
 
+func TestGetMalformedCondition(t *testing.T) {
+	var logs []string
+	logger := funcr.New(func(prefix, args string) {
+		logs = append(logs, args)
+	}, funcr.Options{})
+	ctx := log.IntoContext(context.Background(), logger)
+
+	u := newThing(t, "thing")
+	conditions, _, err := unstructured.NestedSlice(u.Object, "status", "conditions")
+	require.Nil(t, err)
+	malformed := map[string]interface{}{"type": string(xpv1.TypeReady), "status": int64(1)}
+	conditions = append([]interface{}{malformed}, conditions...)
+	err = unstructured.SetNestedSlice(u.Object, conditions, "status", "conditions")
+	require.Nil(t, err)
+
+	res, err := getCondition(ctx, u, xpv1.TypeReady)
+	require.Nil(t, err)
+	require.Equal(t, corev1.ConditionTrue, res.Status)
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], "skip malformed condition")
+	require.Contains(t, logs[0], "thing")
+}
+
+func TestFallbackConditionsPath(t *testing.T) {
+	ctx := context.Background()
+	legacy := []string{"status", "atProvider", "conditions"}
+	r := newThingReconciler(nil)
+	r.FallbackConditionsPath = legacy
+
+	moveTo := func(u *unstructured.Unstructured, path []string) {
+		conditions, _, err := unstructured.NestedSlice(u.Object, "status", "conditions")
+		require.Nil(t, err)
+		unstructured.RemoveNestedField(u.Object, "status", "conditions")
+		err = unstructured.SetNestedSlice(u.Object, conditions, path...)
+		require.Nil(t, err)
+	}
+
+	// only at the primary path.
+	thing := newThing(t, "thing")
+	ready, err := r.isReadyAndSynced(ctx, thing)
+	require.Nil(t, err)
+	require.True(t, ready)
+
+	// only at the fallback path.
+	thing = newThing(t, "thing")
+	moveTo(thing, legacy)
+	ready, err = r.isReadyAndSynced(ctx, thing)
+	require.Nil(t, err)
+	require.True(t, ready)
+	ready, err = newThingReconciler(nil).isReadyAndSynced(ctx, thing)
+	require.Nil(t, err)
+	require.False(t, ready)
+
+	// the primary path takes precedence.
+	setConditions(t, thing, xpv1.Unavailable(), xpv1.ReconcileSuccess())
+	ready, err = r.isReadyAndSynced(ctx, thing)
+	require.Nil(t, err)
+	require.False(t, ready)
+
+	// a custom primary path.
+	r.ConditionsPath = []string{"status", "v2", "conditions"}
+	r.FallbackConditionsPath = nil
+	thing = newThing(t, "thing")
+	moveTo(thing, r.ConditionsPath)
+	c, err := r.condition(ctx, thing, xpv1.TypeReady)
+	require.Nil(t, err)
+	require.Equal(t, corev1.ConditionTrue, c.Status)
+}
+
+func TestControllerName(t *testing.T) {
+	names := make(map[string]bool)
+	for _, gvk := range []schema.GroupVersionKind{
+		testGVK,
+		testGVK.GroupVersion().WithKind("Other"),
+		{Group: "other.crossplane.io", Version: "v1", Kind: "Thing"},
+		{Group: "test.crossplane.io", Version: "v2", Kind: "Thing"},
+		ec2v1beta1.SubnetGroupVersionKind,
+		corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+	} {
+		name := (&Reconciler{GroupVersionKind: gvk}).controllerName()
+		require.False(t, names[name], name)
+		names[name] = true
+	}
+	require.True(t, names["pause-subnet.v1beta1.ec2.aws.crossplane.io"])
+	require.True(t, names["pause-configmap.v1"])
+
+	r := &Reconciler{GroupVersionKind: testGVK, ControllerName: "custom"}
+	require.Equal(t, "custom", r.controllerName())
+}
+
 func TestGVK(t *testing.T) {
 	gvk := ec2v1beta1.SubnetGroupVersionKind
 	t.Log(gvk)
 	// output:
 	// ec2.aws.crossplane.io/v1beta1, Kind=Subnet
 }
+
+func TestConcurrentReconcile(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+	err := cli.Create(ctx, newThing(t, "thing"))
+	require.Nil(t, err)
+
+	// the frozen window keeps it from being paused again once unpaused.
+	r := newThingReconciler(cli)
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}}
+
+	reconcileConcurrently := func(t *testing.T) {
+		t.Helper()
+		var wg sync.WaitGroup
+		errs := make(chan error, 8)
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := r.Reconcile(ctx, req)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.Nil(t, err)
+		}
+	}
+	resourceVersion := func(t *testing.T) int {
+		t.Helper()
+		v, err := strconv.Atoi(getThing(t, cli, "thing").GetResourceVersion())
+		require.Nil(t, err)
+		return v
+	}
+
+	// paused exactly once.
+	version := resourceVersion(t)
+	reconcileConcurrently(t)
+	require.Equal(t, version+1, resourceVersion(t))
+	info, err := r.parsePauseInfo(getThing(t, cli, "thing"))
+	require.Nil(t, err)
+	require.True(t, info.Pause)
+
+	// unpaused exactly once.
+	thing := getThing(t, cli, "thing")
+	err = unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+	require.Nil(t, err)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+	version = resourceVersion(t)
+	reconcileConcurrently(t)
+	require.Equal(t, version+1, resourceVersion(t))
+	info, err = r.parsePauseInfo(getThing(t, cli, "thing"))
+	require.Nil(t, err)
+	require.False(t, info.Pause)
+	require.Len(t, info.History, 1)
+	require.Equal(t, UnpauseReasonUpdated, info.History[0].Reason)
+}
+
+func TestLogFieldNotEqual(t *testing.T) {
+	for verbosity, withDiff := range map[int]bool{0: false, 1: true} {
+		var logs []string
+		logger := funcr.New(func(prefix, args string) {
+			logs = append(logs, args)
+		}, funcr.Options{Verbosity: verbosity})
+		ctx := log.IntoContext(context.Background(), logger)
+
+		old := newThing(t, "thing")
+		now := newThing(t, "thing")
+		err := unstructured.SetNestedField(now.Object, "b", "spec", "forProvider", "cidrBlock")
+		require.Nil(t, err)
+		updated, err := newThingReconciler(nil).isUpdated(ctx, now, old)
+		require.Nil(t, err)
+		require.True(t, updated)
+		require.Len(t, logs, 1)
+		require.Contains(t, logs[0], `"field"="spec"`)
+		require.Equal(t, withDiff, strings.Contains(logs[0], "diff"), logs[0])
+	}
+}
+
+func BenchmarkIsUpdatedLargeSpec(b *testing.B) {
+	ctx := log.IntoContext(context.Background(), logr.Discard())
+	newObj := func() *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(testGVK)
+		u.SetName("thing")
+		u.SetAnnotations(map[string]string{"a": "b"})
+		rules := make([]interface{}, 0, 2000)
+		for i := 0; i < 2000; i++ {
+			rules = append(rules, map[string]interface{}{
+				"name":     fmt.Sprintf("rule-%d", i),
+				"cidr":     fmt.Sprintf("10.%d.%d.0/24", i/256, i%256),
+				"ports":    []interface{}{int64(80), int64(443)},
+				"protocol": "tcp",
+			})
+		}
+		u.Object["spec"] = map[string]interface{}{"forProvider": map[string]interface{}{"rules": rules}}
+		u.Object["status"] = map[string]interface{}{"atProvider": map[string]interface{}{"rules": runtime.DeepCopyJSONValue(rules)}}
+		return u
+	}
+
+	r := newThingReconciler(nil)
+	old := r.trimObject(newObj())
+	now := newObj()
+	changed := newObj()
+	changed.Object["spec"].(map[string]interface{})["forProvider"].(map[string]interface{})["rules"].([]interface{})[1999].(map[string]interface{})["protocol"] = "udp"
+
+	b.Run("equal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			updated, err := r.isUpdated(ctx, now, old)
+			if err != nil || updated {
+				b.Fatal(updated, err)
+			}
+		}
+	})
+	b.Run("changed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			updated, err := r.isUpdated(ctx, changed, old)
+			if err != nil || !updated {
+				b.Fatal(updated, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package crossplanepause
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Resync enqueues all the resources of the GroupVersionKind to reconcile, e.g. once the settings are changed, so the
+// paused ones apply them now instead of at their next event or ShouldUnpauseTime. It never blocks, the triggers before
+// the previous one is handled are coalesced into it.
+func (r *Reconciler) Resync() {
+	select {
+	case r.resyncEvents() <- event.GenericEvent{Object: &metav1.PartialObjectMetadata{}}:
+	default:
+	}
+}
+
+func (r *Reconciler) resyncEvents() chan event.GenericEvent {
+	r.resyncOnce.Do(func() {
+		r.resync = make(chan event.GenericEvent, 1)
+	})
+	return r.resync
+}
+
+// watchResync makes c enqueue all the resources once Resync is called.
+func (r *Reconciler) watchResync(c controller.Controller) error {
+	return c.Watch(&source.Channel{Source: r.resyncEvents()}, handler.EnqueueRequestsFromMapFunc(func(_ client.Object) []reconcile.Request {
+		ctx := context.Background()
+		logger := log.FromContext(ctx).WithValues("gvk", r.GroupVersionKind.String())
+		reqs, err := r.allRequests(ctx)
+		if err != nil {
+			logger.Error(err, "unable to list resources to resync")
+			return nil
+		}
+		logger.Info("resync", "resources", len(reqs))
+		return reqs
+	}))
+}
+
+// allRequests returns the requests of all the resources of the GroupVersionKind.
+func (r *Reconciler) allRequests(ctx context.Context) ([]reconcile.Request, error) {
+	list := new(unstructured.UnstructuredList)
+	list.SetGroupVersionKind(r.GroupVersionKind.GroupVersion().WithKind(r.GroupVersionKind.Kind + "List"))
+	err := r.Client.List(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	reqs := make([]reconcile.Request, 0, len(list.Items))
+	for i := range list.Items {
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: list.Items[i].GetNamespace(),
+			Name:      list.Items[i].GetName(),
+		}})
+	}
+
+	return reqs, nil
+}
@@ -0,0 +1,50 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+func TestResync(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	names := make([]string, 3)
+	for i := range names {
+		names[i] = fmt.Sprintf("thing-%d", i)
+		err := cli.Create(ctx, newThing(t, names[i]))
+		require.Nil(t, err)
+	}
+
+	r := newThingReconciler(cli)
+	// trigger before started, it's handled once started.
+	r.Resync()
+	r.Resync()
+
+	// Watch the resync only, so it doesn't need an API server.
+	mgr := newTestManager(t)
+	c, err := controller.NewUnmanaged("resync", mgr, controller.Options{Reconciler: r})
+	require.Nil(t, err)
+	err = r.watchResync(c)
+	require.Nil(t, err)
+	go func() {
+		_ = c.Start(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		for _, name := range names {
+			info, err := r.parsePauseInfo(getThing(t, cli, name))
+			if err != nil || info == nil || !info.Pause {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,63 @@
+package crossplanepause
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// validateSelector returns an error if the LabelSelector is malformed.
+func (r *Reconciler) validateSelector() error {
+	if r.LabelSelector == nil {
+		return nil
+	}
+
+	_, err := metav1.LabelSelectorAsSelector(r.LabelSelector)
+	if err != nil {
+		return fmt.Errorf("invalid LabelSelector: %w", err)
+	}
+
+	return nil
+}
+
+// selected returns true if obj is selected by the IncludeNames, ExcludeNames, Namespaces and LabelSelector, unlike
+// the Predicates, it's an explicit membership check which holds for the resources without any event, e.g. in the sweep.
+// A malformed LabelSelector selects nothing, it's rejected by SetupWithManager anyway.
+func (r *Reconciler) selected(obj client.Object) bool {
+	if !r.nameAllowed(obj.GetName()) || !r.namespaceAllowed(obj.GetNamespace()) {
+		return false
+	}
+	if r.LabelSelector == nil {
+		return true
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(r.LabelSelector)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// namespaceAllowed returns true if the resource in namespace should be reconciled by the Namespaces.
+func (r *Reconciler) namespaceAllowed(namespace string) bool {
+	if len(r.Namespaces) == 0 {
+		return true
+	}
+
+	for _, ns := range r.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectorPredicate filters the resources by selected.
+func (r *Reconciler) selectorPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(r.selected)
+}
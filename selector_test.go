@@ -0,0 +1,60 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestSelector(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+	r := newThingReconciler(cli)
+	r.Namespaces = []string{"prod"}
+	r.LabelSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"managed": "true"}}
+	require.Nil(t, r.validateSelector())
+	pd := r.predicate()
+
+	var paused, passed []client.ObjectKey
+	for _, tc := range []struct {
+		namespace string
+		labels    map[string]string
+	}{
+		{namespace: "prod", labels: map[string]string{"managed": "true"}},
+		{namespace: "dev", labels: map[string]string{"managed": "true"}},
+		{namespace: "prod", labels: map[string]string{"managed": "false"}},
+	} {
+		thing := newThing(t, "thing-"+tc.namespace+"-"+tc.labels["managed"])
+		thing.SetNamespace(tc.namespace)
+		thing.SetLabels(tc.labels)
+		err := cli.Create(ctx, thing)
+		require.Nil(t, err)
+		key := client.ObjectKeyFromObject(thing)
+		if pd.Create(event.CreateEvent{Object: thing}) {
+			passed = append(passed, key)
+		}
+
+		// the reconcile checks it too, e.g. for the ExternalEvents.
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.Nil(t, err)
+		err = cli.Get(ctx, key, thing)
+		require.Nil(t, err)
+		if isPaused(thing.GetAnnotations()[AnnotationKeyReconciliationPaused]) {
+			paused = append(paused, key)
+		}
+	}
+	selected := []client.ObjectKey{{Namespace: "prod", Name: "thing-prod-true"}}
+	require.Equal(t, selected, passed)
+	require.Equal(t, selected, paused)
+
+	r = &Reconciler{LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "managed", Operator: "bad"},
+	}}}
+	require.NotNil(t, r.validateSelector())
+}
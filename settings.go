@@ -0,0 +1,203 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// DefaultUnPausePollJitter the default max jitter added to the UnPausePollInterval, as a fraction of it.
+const DefaultUnPausePollJitter = 0.1
+
+// The keys of the SettingsConfigMap, all of them are optional, the missing ones fall back to the fields of the Reconciler.
+const (
+	// ConfigMapKeyUnPausePollInterval the UnPausePollInterval as a Go duration like "1h", "0" disables it.
+	ConfigMapKeyUnPausePollInterval = "unPausePollInterval"
+	// ConfigMapKeyFrozenTimeDuration the FrozenTimeDuration as a Go duration like "5m".
+	ConfigMapKeyFrozenTimeDuration = "frozenTimeDuration"
+	// ConfigMapKeyUnPausePollJitter the UnPausePollJitter as a float like "0.1".
+	ConfigMapKeyUnPausePollJitter = "unPausePollJitter"
+)
+
+// settings the effective settings of the reconciler which can be reloaded from the SettingsConfigMap.
+type settings struct {
+	unPausePollInterval *time.Duration
+	frozenTimeDuration  time.Duration
+	unPausePollJitter   float64
+}
+
+// settings returns the effective settings, the one reloaded from the SettingsConfigMap if any, otherwise the one
+// from the fields of the reconciler.
+func (r *Reconciler) settings() *settings {
+	if s, ok := r.reloaded.Load().(*settings); ok && s != nil {
+		return s
+	}
+
+	return r.fieldSettings()
+}
+
+// fieldSettings returns the settings from the fields of the reconciler.
+func (r *Reconciler) fieldSettings() *settings {
+	s := &settings{
+		unPausePollInterval: r.UnPausePollInterval,
+		frozenTimeDuration:  DefaultFrozenTimeDuration,
+		unPausePollJitter:   DefaultUnPausePollJitter,
+	}
+	if r.FrozenTimeDuration != nil {
+		s.frozenTimeDuration = *r.FrozenTimeDuration
+	}
+	if r.UnPausePollJitter != nil {
+		s.unPausePollJitter = *r.UnPausePollJitter
+	}
+
+	return s
+}
+
+// parseSettings parses the data of the SettingsConfigMap on top of the settings from the fields of the reconciler.
+func (r *Reconciler) parseSettings(data map[string]string) (*settings, error) {
+	s := r.fieldSettings()
+
+	if v, ok := data[ConfigMapKeyUnPausePollInterval]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid %s %q", ConfigMapKeyUnPausePollInterval, v)
+		}
+		s.unPausePollInterval = nil
+		if d > 0 {
+			s.unPausePollInterval = &d
+		}
+	}
+
+	if v, ok := data[ConfigMapKeyFrozenTimeDuration]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid %s %q", ConfigMapKeyFrozenTimeDuration, v)
+		}
+		s.frozenTimeDuration = d
+	}
+
+	if v, ok := data[ConfigMapKeyUnPausePollJitter]; ok {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("invalid %s %q", ConfigMapKeyUnPausePollJitter, v)
+		}
+		s.unPausePollJitter = f
+	}
+
+	return s, nil
+}
+
+// settingsSource is the source of the events of the SettingsConfigMap, which reloads the settings on them with the
+// context the controller starts it with, so the reloading stops along with the manager.
+type settingsSource struct {
+	source.SyncingSource
+	r *Reconciler
+}
+
+// Start implements source.Source, the events are handled by reloadSettings instead of the passed handler.
+func (s *settingsSource) Start(ctx context.Context, _ handler.EventHandler, queue workqueue.RateLimitingInterface, pds ...predicate.Predicate) error {
+	return s.SyncingSource.Start(ctx, handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+		return s.r.reloadSettings(ctx)
+	}), queue, pds...)
+}
+
+// watchSettingsConfigMap watches the SettingsConfigMap by c. It's watched in a cache of its own limited to it, so the
+// ConfigMaps of the whole cluster are not cached by the manager.
+func (r *Reconciler) watchSettingsConfigMap(mgr ctrl.Manager, c controller.Controller) error {
+	scheme := runtime.NewScheme()
+	err := corev1.AddToScheme(scheme)
+	if err != nil {
+		return err
+	}
+
+	configMaps, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:    scheme,
+		Mapper:    mgr.GetRESTMapper(),
+		Namespace: r.SettingsConfigMap.Namespace,
+		SelectorsByObject: cache.SelectorsByObject{
+			&corev1.ConfigMap{}: {Field: fields.OneTermEqualSelector("metadata.name", r.SettingsConfigMap.Name)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create cache: %w", err)
+	}
+	err = mgr.Add(configMaps)
+	if err != nil {
+		return fmt.Errorf("unable to add cache: %w", err)
+	}
+	r.settingsReader = configMaps
+
+	// The source enqueues by reloadSettings, there is no handler.
+	src := &settingsSource{SyncingSource: source.NewKindWithCache(&corev1.ConfigMap{}, configMaps), r: r}
+	return c.Watch(src, nil, r.settingsPredicate())
+}
+
+// settingsPredicate filters the events of the SettingsConfigMap.
+func (r *Reconciler) settingsPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == r.SettingsConfigMap.Namespace && obj.GetName() == r.SettingsConfigMap.Name
+	})
+}
+
+// reloadSettings reloads the settings from the SettingsConfigMap and returns the requests of all the resources, so the
+// new settings take effect, especially the ShouldUnpauseTime is recomputed for the new UnPausePollInterval.
+// If the ConfigMap is malformed, we keep the last good settings.
+func (r *Reconciler) reloadSettings(ctx context.Context) []reconcile.Request {
+	logger := log.FromContext(ctx).WithValues("gvk", r.GroupVersionKind.String(), "configMap", r.SettingsConfigMap.String())
+	reader := r.settingsReader
+	if reader == nil {
+		reader = r.Client
+	}
+
+	cm := new(corev1.ConfigMap)
+	err := reader.Get(ctx, *r.SettingsConfigMap, cm)
+	switch {
+	case apierrors.IsNotFound(err):
+		logger.Info("settings config map not found, use the reconciler fields")
+		r.reloaded.Store(r.fieldSettings())
+	case err != nil:
+		logger.Error(err, "unable to get settings config map, keep the last good settings")
+		return nil
+	default:
+		s, err := r.parseSettings(cm.Data)
+		if err != nil {
+			logger.Error(err, "malformed settings config map, keep the last good settings")
+			return nil
+		}
+		r.reloaded.Store(s)
+	}
+
+	s := r.settings()
+	unPausePollInterval := "disabled"
+	if s.unPausePollInterval != nil {
+		unPausePollInterval = s.unPausePollInterval.String()
+	}
+	logger.Info("reload settings",
+		"unPausePollInterval", unPausePollInterval,
+		"frozenTimeDuration", s.frozenTimeDuration.String(),
+		"unPausePollJitter", s.unPausePollJitter)
+
+	reqs, err := r.allRequests(ctx)
+	if err != nil {
+		logger.Error(err, "unable to list resources to apply the new settings")
+		return nil
+	}
+
+	return reqs
+}
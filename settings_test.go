@@ -0,0 +1,153 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReloadSettings(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	for _, name := range []string{"thing", "other"} {
+		err := cli.Create(ctx, newThing(t, name))
+		require.Nil(t, err)
+	}
+
+	r := newThingReconciler(cli)
+	r.UnPausePollInterval = pointer.Duration(time.Hour)
+	r.UnPausePollJitter = pointer.Float64(0)
+	r.FrozenTimeDuration = pointer.Duration(0)
+	r.SettingsConfigMap = &types.NamespacedName{Namespace: "default", Name: "settings"}
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}}
+
+	reconcileThing := func(t *testing.T) (ctrl.Result, *PauseInfo) {
+		t.Helper()
+		result, err := r.Reconcile(ctx, req)
+		require.Nil(t, err)
+		info, err := r.parsePauseInfo(getThing(t, cli, "thing"))
+		require.Nil(t, err)
+		return result, info
+	}
+
+	result, info := reconcileThing(t)
+	require.True(t, info.Pause)
+	require.Equal(t, time.Hour, info.UnPausePollInterval.Duration)
+	result, _ = reconcileThing(t)
+	require.Greater(t, result.RequeueAfter, 50*time.Minute)
+
+	// the new interval applies to the paused resource, the ShouldUnpauseTime is shifted.
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "settings"},
+		Data: map[string]string{
+			ConfigMapKeyUnPausePollInterval: "1ns",
+			ConfigMapKeyFrozenTimeDuration:  "1h",
+		},
+	}
+	err := cli.Create(ctx, cm)
+	require.Nil(t, err)
+	reqs := r.reloadSettings(ctx)
+	require.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: client.ObjectKey{Name: "thing"}},
+		{NamespacedName: client.ObjectKey{Name: "other"}},
+	}, reqs)
+	require.Equal(t, time.Nanosecond, *r.settings().unPausePollInterval)
+	require.Equal(t, time.Hour, r.settings().frozenTimeDuration)
+
+	_, info = reconcileThing(t)
+	require.False(t, info.Pause)
+	// the new FrozenTimeDuration applies too.
+	result, info = reconcileThing(t)
+	require.False(t, info.Pause)
+	require.Greater(t, result.RequeueAfter, 50*time.Minute)
+
+	// keep the last good settings if malformed.
+	cm.Data[ConfigMapKeyUnPausePollJitter] = "abc"
+	err = cli.Update(ctx, cm)
+	require.Nil(t, err)
+	require.Nil(t, r.reloadSettings(ctx))
+	require.Equal(t, time.Nanosecond, *r.settings().unPausePollInterval)
+	require.Equal(t, time.Hour, r.settings().frozenTimeDuration)
+
+	// disable the UnPausePollInterval by "0".
+	cm.Data = map[string]string{ConfigMapKeyUnPausePollInterval: "0"}
+	err = cli.Update(ctx, cm)
+	require.Nil(t, err)
+	require.Len(t, r.reloadSettings(ctx), 2)
+	require.Nil(t, r.settings().unPausePollInterval)
+	require.Equal(t, time.Duration(0), r.settings().frozenTimeDuration)
+
+	// fall back to the fields once the config map is deleted.
+	err = cli.Delete(ctx, cm)
+	require.Nil(t, err)
+	require.Len(t, r.reloadSettings(ctx), 2)
+	require.Equal(t, time.Hour, *r.settings().unPausePollInterval)
+	require.Equal(t, float64(0), r.settings().unPausePollJitter)
+}
+
+// capturingSource captures the handler it's started with.
+type capturingSource struct {
+	handler handler.EventHandler
+}
+
+func (s *capturingSource) Start(_ context.Context, h handler.EventHandler, _ workqueue.RateLimitingInterface, _ ...predicate.Predicate) error {
+	s.handler = h
+	return nil
+}
+
+func (s *capturingSource) WaitForSync(context.Context) error {
+	return nil
+}
+
+// ctxReader records the context it's read with.
+type ctxReader struct {
+	client.Reader
+	ctx context.Context
+}
+
+func (r *ctxReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	r.ctx = ctx
+	return r.Reader.Get(ctx, key, obj, opts...)
+}
+
+func TestSettingsSource(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	for _, name := range []string{"thing", "other"} {
+		err := cli.Create(context.Background(), newThing(t, name))
+		require.Nil(t, err)
+	}
+	r := newThingReconciler(cli)
+	r.SettingsConfigMap = &types.NamespacedName{Namespace: "default", Name: "settings"}
+	reader := &ctxReader{Reader: cli}
+	r.settingsReader = reader
+
+	// the settings are reloaded with the context the controller starts the source with.
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "controller")
+	inner := &capturingSource{}
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	err := (&settingsSource{SyncingSource: inner, r: r}).Start(ctx, nil, queue)
+	require.Nil(t, err)
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "settings"}}
+	inner.handler.Create(event.CreateEvent{Object: cm}, queue)
+	require.NotNil(t, reader.ctx)
+	require.Equal(t, "controller", reader.ctx.Value(ctxKey{}))
+	require.Equal(t, 2, queue.Len())
+}
@@ -0,0 +1,85 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// EventReasonPauseDeferred the reason of the event emitted once we defer pausing a resource by the StabilityWindow.
+const EventReasonPauseDeferred = "PauseDeferred"
+
+// The reasons of SettlingPolicy.Wait.
+const (
+	settlingReasonTooYoung = "too young"
+	settlingReasonUnstable = "conditions not stable"
+)
+
+// SettlingPolicy decides if a Ready and Synced resource has settled to pause, by both its age and how long its
+// conditions have been stable, so the two gates never requeue the resource for each other.
+type SettlingPolicy struct {
+	// MinResourceAge the min Duration since the creation of the resource.
+	MinResourceAge time.Duration
+	// StabilityWindow the min Duration since the last transition of the Ready and Synced conditions.
+	StabilityWindow time.Duration
+
+	// conditionsPaths the paths to read the conditions from, DefaultConditionsPath if empty.
+	conditionsPaths [][]string
+}
+
+// settlingPolicy returns the SettlingPolicy of the reconciler.
+func (r *Reconciler) settlingPolicy() SettlingPolicy {
+	return SettlingPolicy{MinResourceAge: r.MinResourceAge, StabilityWindow: r.StabilityWindow, conditionsPaths: r.conditionsPaths()}
+}
+
+// Wait returns how long to wait at now before obj has settled, which is the longer wait of the gates, along with the
+// reason of it. It returns 0 if obj has settled.
+func (p SettlingPolicy) Wait(ctx context.Context, obj *unstructured.Unstructured, now time.Time) (time.Duration, string, error) {
+	var wait time.Duration
+	var reason string
+
+	if p.MinResourceAge > 0 {
+		if delay := obj.GetCreationTimestamp().Add(p.MinResourceAge).Sub(now); delay > wait {
+			wait, reason = delay, settlingReasonTooYoung
+		}
+	}
+
+	if p.StabilityWindow > 0 {
+		paths := p.conditionsPaths
+		if len(paths) == 0 {
+			paths = [][]string{DefaultConditionsPath}
+		}
+
+		var last time.Time
+		for _, ty := range requiredConditionTypes {
+			c, err := getConditionAt(ctx, obj, ty, paths)
+			if err != nil {
+				return 0, "", fmt.Errorf("unable to get %s condition: %w", strings.ToLower(string(ty)), err)
+			}
+			// The missing condition is checked by getBlockingCondition, e.g. the optional Synced.
+			if c != nil && c.LastTransitionTime.After(last) {
+				last = c.LastTransitionTime.Time
+			}
+		}
+
+		if delay := last.Add(p.StabilityWindow).Sub(now); delay > wait {
+			wait, reason = delay, settlingReasonUnstable
+		}
+	}
+
+	return wait, reason, nil
+}
+
+// deferPause records we defer pausing obj for delay since it has not settled for the reason.
+func (r *Reconciler) deferPause(ctx context.Context, obj *unstructured.Unstructured, delay time.Duration, reason string) {
+	if reason == settlingReasonUnstable {
+		r.metrics().incObject(pauseDeferredStability, obj)
+	}
+	log.FromContext(ctx).V(1).Info("defer pause since not settled", "reason", reason, "after", delay.String())
+	r.event(obj, corev1.EventTypeNormal, EventReasonPauseDeferred, "Defer pause for %s since %s", delay, reason)
+}
@@ -0,0 +1,114 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestStabilityWindow(t *testing.T) {
+	h := newHarness(t)
+	h.r.StabilityWindow = 5 * time.Minute
+	recorder := record.NewFakeRecorder(10)
+	h.r.Recorder = recorder
+
+	// Ready just transitioned a minute ago.
+	ready := xpv1.Available()
+	ready.LastTransitionTime = metav1.NewTime(h.clock.Now().Add(-time.Minute))
+	synced := xpv1.ReconcileSuccess()
+	synced.LastTransitionTime = metav1.NewTime(h.clock.Now().Add(-time.Hour))
+	thing := newThing(t, "thing")
+	setConditions(t, thing, ready, synced)
+	err := h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+
+	deferred := pauseDeferredStability.plain.WithLabelValues(testGVK.String())
+	before := testutil.ToFloat64(deferred)
+
+	result := h.reconcile()
+	require.Equal(t, 4*time.Minute, result.RequeueAfter)
+	paused, _ := h.state()
+	require.False(t, paused)
+	require.Equal(t, before+1, testutil.ToFloat64(deferred))
+	require.Contains(t, <-recorder.Events, EventReasonPauseDeferred)
+
+	h.advance(time.Minute)
+	result = h.reconcile()
+	require.Equal(t, 3*time.Minute, result.RequeueAfter)
+	require.Equal(t, before+2, testutil.ToFloat64(deferred))
+
+	// stable now.
+	h.advance(3 * time.Minute)
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+	require.Equal(t, before+2, testutil.ToFloat64(deferred))
+}
+
+func TestSettlingPolicy(t *testing.T) {
+	now := time.Date(2022, 7, 22, 10, 54, 18, 0, time.UTC)
+	p := SettlingPolicy{MinResourceAge: 10 * time.Minute, StabilityWindow: 5 * time.Minute}
+
+	newObj := func(age, stable time.Duration) *unstructured.Unstructured {
+		ready := xpv1.Available()
+		ready.LastTransitionTime = metav1.NewTime(now.Add(-stable))
+		synced := xpv1.ReconcileSuccess()
+		synced.LastTransitionTime = metav1.NewTime(now.Add(-age))
+		thing := newThing(t, "thing")
+		thing.SetCreationTimestamp(metav1.NewTime(now.Add(-age)))
+		setConditions(t, thing, ready, synced)
+		return thing
+	}
+
+	for _, tc := range []struct {
+		name   string
+		age    time.Duration
+		stable time.Duration
+		wait   time.Duration
+		reason string
+	}{
+		{name: "young and stable", age: 8 * time.Minute, stable: 8 * time.Minute, wait: 2 * time.Minute, reason: settlingReasonTooYoung},
+		{name: "old and unstable", age: time.Hour, stable: time.Minute, wait: 4 * time.Minute, reason: settlingReasonUnstable},
+		{name: "old and stable", age: time.Hour, stable: 5 * time.Minute},
+		{name: "young and unstable", age: 9 * time.Minute, stable: 2 * time.Minute, wait: 3 * time.Minute, reason: settlingReasonUnstable},
+		{name: "younger and unstable", age: 2 * time.Minute, stable: 2 * time.Minute, wait: 8 * time.Minute, reason: settlingReasonTooYoung},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			wait, reason, err := p.Wait(context.Background(), newObj(tc.age, tc.stable), now)
+			require.Nil(t, err)
+			require.Equal(t, tc.wait, wait)
+			require.Equal(t, tc.reason, reason)
+		})
+	}
+
+	// the gates are off by default.
+	wait, _, err := SettlingPolicy{}.Wait(context.Background(), newObj(0, 0), now)
+	require.Nil(t, err)
+	require.Zero(t, wait)
+}
+
+func TestMinResourceAge(t *testing.T) {
+	h := newHarness(t)
+	h.r.MinResourceAge = 10 * time.Minute
+	thing := newThing(t, "thing")
+	thing.SetCreationTimestamp(metav1.NewTime(h.clock.Now().Add(-time.Minute)))
+	err := h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+
+	result := h.reconcile()
+	require.Equal(t, 9*time.Minute, result.RequeueAfter)
+	paused, _ := h.state()
+	require.False(t, paused)
+
+	h.advance(result.RequeueAfter)
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+}
@@ -0,0 +1,63 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// transformSnapshot applies the SnapshotTransform to obj, obj is returned as is if it's not set or returns nil.
+func (r *Reconciler) transformSnapshot(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if r.SnapshotTransform == nil || obj == nil {
+		return obj
+	}
+
+	if res := r.SnapshotTransform(obj.DeepCopy()); res != nil {
+		return res
+	}
+	return obj
+}
+
+// isSnapshotStale returns true if the snapshot of the paused resource differs from obj, it should be called only
+// if obj is not updated, which means the difference is ignored, e.g. by SpecDefaults or SpecEqual.
+func (r *Reconciler) isSnapshotStale(obj *unstructured.Unstructured, info *PauseInfo) bool {
+	return info.Object != nil && !reflect.DeepEqual(info.Object.Object, r.trimObject(obj).Object)
+}
+
+// refreshSnapshot refreshes the snapshot of the paused resource to obj while keeping it paused,
+// so we don't keep diffing against an outdated one.
+func (r *Reconciler) refreshSnapshot(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo) error {
+	err := r.update(ctx, obj, func(obj *unstructured.Unstructured, refetched bool) (bool, error) {
+		if refetched {
+			freshInfo, err := r.parsePauseInfo(obj)
+			if err != nil {
+				return false, fmt.Errorf("unable to parse pause info: %w", err)
+			}
+			if freshInfo == nil || !freshInfo.Pause || freshInfo.Object == nil {
+				return false, nil
+			}
+			// Never take a real update as the snapshot, the next reconcile will unpause it.
+			updated, err := r.isUpdated(ctx, obj, freshInfo.Object)
+			if err != nil || updated {
+				return false, err
+			}
+			info = freshInfo
+		}
+
+		info.Object = r.trimObject(obj)
+		err := r.setPauseInfo(obj, info)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update object: %w", err)
+	}
+
+	log.FromContext(ctx).Info("refresh the snapshot of the paused resource")
+	return nil
+}
@@ -0,0 +1,92 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRefreshSnapshot(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	err := cli.Create(ctx, newThing(t, "thing"))
+	require.Nil(t, err)
+
+	r := newThingReconciler(cli)
+	r.SpecDefaults = map[string]interface{}{"forProvider.tenancy": "default"}
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "thing"}}
+	_, err = r.Reconcile(ctx, req)
+	require.Nil(t, err)
+
+	// the provider defaults the field which is ignored.
+	thing := getThing(t, cli, "thing")
+	err = unstructured.SetNestedField(thing.Object, "default", "spec", "forProvider", "tenancy")
+	require.Nil(t, err)
+	err = cli.Update(ctx, thing)
+	require.Nil(t, err)
+
+	writes := 0
+	version := getThing(t, cli, "thing").GetResourceVersion()
+	for i := 0; i < 3; i++ {
+		_, err = r.Reconcile(ctx, req)
+		require.Nil(t, err)
+		thing = getThing(t, cli, "thing")
+		if thing.GetResourceVersion() != version {
+			writes++
+			version = thing.GetResourceVersion()
+		}
+	}
+	require.Equal(t, 1, writes)
+
+	info, err := r.parsePauseInfo(thing)
+	require.Nil(t, err)
+	require.True(t, info.Pause)
+	tenancy, _, err := unstructured.NestedString(info.Object.Object, "spec", "forProvider", "tenancy")
+	require.Nil(t, err)
+	require.Equal(t, "default", tenancy)
+}
+
+func TestSnapshotTransform(t *testing.T) {
+	h := newHarness(t)
+	h.r.SnapshotTransform = func(obj *unstructured.Unstructured) *unstructured.Unstructured {
+		if _, ok, _ := unstructured.NestedString(obj.Object, "spec", "forProvider", "secret"); ok {
+			_ = unstructured.SetNestedField(obj.Object, "REDACTED", "spec", "forProvider", "secret")
+		}
+		return obj
+	}
+	thing := newThing(t, "thing")
+	err := unstructured.SetNestedField(thing.Object, "s3cr3t", "spec", "forProvider", "secret")
+	require.Nil(t, err)
+	err = h.cli.Create(context.Background(), thing)
+	require.Nil(t, err)
+
+	h.reconcile()
+	paused, info := h.state()
+	require.True(t, paused)
+	secret, _, err := unstructured.NestedString(info.Object.Object, "spec", "forProvider", "secret")
+	require.Nil(t, err)
+	require.Equal(t, "REDACTED", secret)
+
+	// the redacted field is not taken as an update, neither a stale snapshot.
+	version := getThing(t, h.cli, "thing").GetResourceVersion()
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+	require.Equal(t, version, getThing(t, h.cli, "thing").GetResourceVersion())
+
+	// the other fields are still watched.
+	h.mutate(func(thing *unstructured.Unstructured) {
+		err := unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+		require.Nil(t, err)
+	})
+	h.reconcile()
+	paused, info = h.state()
+	require.False(t, paused)
+	require.Equal(t, UnpauseReasonUpdated, info.History[len(info.History)-1].Reason)
+}
@@ -0,0 +1,16 @@
+package crossplanepause
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// specMatched returns true if obj should be paused by the SpecMatch, the spec of a specless resource is nil.
+func (r *Reconciler) specMatched(obj *unstructured.Unstructured) bool {
+	if r.SpecMatch == nil {
+		return true
+	}
+
+	spec, _, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec")
+	m, _ := spec.(map[string]interface{})
+	return r.SpecMatch(m)
+}
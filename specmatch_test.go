@@ -0,0 +1,90 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSpecMatch(t *testing.T) {
+	instanceTypes := map[string]bool{"t3.micro": true, "t3.small": true}
+	setInstanceType := func(t *testing.T, thing *unstructured.Unstructured, instanceType string) {
+		err := unstructured.SetNestedField(thing.Object, instanceType, "spec", "forProvider", "instanceType")
+		require.Nil(t, err)
+	}
+
+	for _, tc := range []struct {
+		name         string
+		instanceType string
+		paused       bool
+	}{
+		{name: "matched", instanceType: "t3.micro", paused: true},
+		{name: "not matched", instanceType: "m5.large", paused: false},
+		{name: "missing", paused: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHarness(t)
+			h.r.SpecMatch = func(spec map[string]interface{}) bool {
+				instanceType, _, _ := unstructured.NestedString(spec, "forProvider", "instanceType")
+				return instanceTypes[instanceType]
+			}
+			thing := newThing(t, "thing")
+			if tc.instanceType != "" {
+				setInstanceType(t, thing, tc.instanceType)
+			}
+			err := h.cli.Create(context.Background(), thing)
+			require.Nil(t, err)
+
+			h.reconcile()
+			paused, _ := h.state()
+			require.Equal(t, tc.paused, paused)
+		})
+	}
+
+	t.Run("specless", func(t *testing.T) {
+		h := newHarness(t)
+		var called bool
+		h.r.SpecMatch = func(spec map[string]interface{}) bool {
+			called = true
+			require.Nil(t, spec)
+			return true
+		}
+		err := h.cli.Create(context.Background(), newThing(t, "thing"))
+		require.Nil(t, err)
+
+		h.reconcile()
+		require.True(t, called)
+		paused, _ := h.state()
+		require.True(t, paused)
+	})
+
+	t.Run("no longer matched", func(t *testing.T) {
+		h := newHarness(t)
+		h.r.SpecMatch = func(spec map[string]interface{}) bool {
+			instanceType, _, _ := unstructured.NestedString(spec, "forProvider", "instanceType")
+			return instanceTypes[instanceType]
+		}
+		thing := newThing(t, "thing")
+		setInstanceType(t, thing, "t3.small")
+		err := h.cli.Create(context.Background(), thing)
+		require.Nil(t, err)
+		h.reconcile()
+		paused, _ := h.state()
+		require.True(t, paused)
+
+		// the class is narrowed without touching the resource.
+		delete(instanceTypes, "t3.small")
+		h.reconcile()
+		paused, info := h.state()
+		require.False(t, paused)
+		require.Equal(t, UnpauseReasonSpecNotMatched, info.History[len(info.History)-1].Reason)
+
+		// never paused again.
+		h.advance(DefaultFrozenTimeDuration)
+		h.reconcile()
+		paused, _ = h.state()
+		require.False(t, paused)
+	})
+}
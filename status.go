@@ -0,0 +1,44 @@
+package crossplanepause
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// StatusFieldPauseState the field of the status we write the PauseState to if StatusPauseState is set.
+const StatusFieldPauseState = "pauseState"
+
+// PauseState the pause state of a resource written to status.pauseState for GitOps visibility.
+type PauseState struct {
+	Paused        bool         `json:"paused"`
+	LastPauseTime *metav1.Time `json:"lastPauseTime,omitempty"`
+	// Why we pause or unpause the resource last time.
+	Reason string `json:"reason,omitempty"`
+}
+
+// patchPauseState writes the pause state of obj paused or unpaused with info for the reason to its status by the status
+// subresource, only status.pauseState is patched so the fields owned by the provider are left alone. It's best effort,
+// the failure is logged since the pause annotation has been written.
+func (r *Reconciler) patchPauseState(ctx context.Context, obj *unstructured.Unstructured, info *PauseInfo, reason string) {
+	if !r.StatusPauseState {
+		return
+	}
+
+	state := PauseState{Paused: info.Pause, LastPauseTime: info.LastPauseTime, Reason: reason}
+	data, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{StatusFieldPauseState: state},
+	})
+	if err == nil {
+		err = r.Client.Status().Patch(ctx, obj, client.RawPatch(types.MergePatchType, data))
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(fmt.Errorf("unable to patch status.%s: %w", StatusFieldPauseState, err), "ignore it")
+	}
+}
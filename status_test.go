@@ -0,0 +1,78 @@
+package crossplanepause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestStatusPauseState(t *testing.T) {
+	h := newHarness(t)
+	h.r.StatusPauseState = true
+	err := h.cli.Create(context.Background(), newThing(t, "thing"))
+	require.Nil(t, err)
+
+	pauseState := func() map[string]interface{} {
+		state, _, err := unstructured.NestedMap(getThing(t, h.cli, "thing").Object, "status", StatusFieldPauseState)
+		require.Nil(t, err)
+		return state
+	}
+
+	h.reconcile()
+	paused, info := h.state()
+	require.True(t, paused)
+	require.Equal(t, map[string]interface{}{
+		"paused":        true,
+		"lastPauseTime": info.LastPauseTime.UTC().Format(time.RFC3339),
+		"reason":        "Ready=Available, Synced=ReconcileSuccess",
+	}, pauseState())
+	// the conditions owned by the provider are kept.
+	_, found, err := unstructured.NestedSlice(getThing(t, h.cli, "thing").Object, "status", "conditions")
+	require.Nil(t, err)
+	require.True(t, found)
+
+	// it's not an update.
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+
+	h.mutate(func(thing *unstructured.Unstructured) {
+		err := unstructured.SetNestedField(thing.Object, "a", "spec", "forProvider", "cidrBlock")
+		require.Nil(t, err)
+	})
+	h.reconcile()
+	paused, _ = h.state()
+	require.False(t, paused)
+	require.Equal(t, map[string]interface{}{
+		"paused":        false,
+		"lastPauseTime": info.LastPauseTime.UTC().Format(time.RFC3339),
+		"reason":        string(UnpauseReasonUpdated),
+	}, pauseState())
+
+	// off by default.
+	h.r.StatusPauseState = false
+	h.mutate(func(thing *unstructured.Unstructured) {
+		unstructured.RemoveNestedField(thing.Object, "status", StatusFieldPauseState)
+	})
+	h.advance(DefaultFrozenTimeDuration)
+	h.reconcile()
+	paused, _ = h.state()
+	require.True(t, paused)
+	require.Nil(t, pauseState())
+}
+
+func TestFingerprintIgnoresPauseState(t *testing.T) {
+	r := newThingReconciler(nil)
+	thing := newThing(t, "thing")
+	before, err := r.fingerprint(thing)
+	require.Nil(t, err)
+
+	err = unstructured.SetNestedField(thing.Object, true, "status", StatusFieldPauseState, "paused")
+	require.Nil(t, err)
+	after, err := r.fingerprint(thing)
+	require.Nil(t, err)
+	require.Equal(t, before, after)
+}
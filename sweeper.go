@@ -0,0 +1,80 @@
+package crossplanepause
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// sweeper periodically unpauses the orphaned resources, see sweep.
+// It only runs in the leader if the leader election of the manager is enabled, so the replicas don't duplicate the work,
+// while the reconciles follow the leader election of the controller as usual.
+type sweeper struct {
+	r *Reconciler
+}
+
+var _ manager.LeaderElectionRunnable = &sweeper{}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (s *sweeper) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable.
+func (s *sweeper) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithValues("gvk", s.r.GroupVersionKind.String())
+	ticker := time.NewTicker(s.r.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		swept, err := s.r.sweep(ctx)
+		if err != nil {
+			logger.Error(err, "unable to sweep orphaned resources")
+			continue
+		}
+		logger.Info("sweep orphaned resources", "swept", swept)
+	}
+}
+
+// sweep unpauses the resources paused by us but not selected by the reconciler now, e.g. the label we select is removed,
+// we would never reconcile them again otherwise. It returns the number of the unpaused resources.
+func (r *Reconciler) sweep(ctx context.Context) (int, error) {
+	list := new(unstructured.UnstructuredList)
+	list.SetGroupVersionKind(r.GroupVersionKind.GroupVersion().WithKind(r.GroupVersionKind.Kind + "List"))
+	err := r.kube().List(ctx, list)
+	if err != nil {
+		return 0, fmt.Errorf("unable to list %s: %w", r.GroupVersionKind, err)
+	}
+
+	swept := 0
+	for i := range list.Items {
+		obj := &list.Items[i]
+		info, err := r.parsePauseInfo(obj)
+		if err != nil {
+			return swept, fmt.Errorf("unable to parse pause info of %s: %w", client.ObjectKeyFromObject(obj), err)
+		}
+
+		if info == nil || !info.Pause || r.selected(obj) {
+			continue
+		}
+
+		err = r.ensureUnPause(ctx, obj, info, UnpauseReasonOrphaned)
+		if err != nil {
+			return swept, fmt.Errorf("unable to unpause %s: %w", client.ObjectKeyFromObject(obj), err)
+		}
+		swept++
+	}
+
+	return swept, nil
+}
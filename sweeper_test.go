@@ -0,0 +1,165 @@
+package crossplanepause
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// fakeLock is an in-memory resourcelock.Interface, it's held by others if the record is held by another identity.
+type fakeLock struct {
+	mu     sync.Mutex
+	record *resourcelock.LeaderElectionRecord
+}
+
+func (l *fakeLock) Get(ctx context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.record == nil {
+		return nil, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "leases"}, "test")
+	}
+	record := *l.record
+	raw, err := json.Marshal(record)
+	return &record, raw, err
+}
+
+func (l *fakeLock) Create(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	return l.Update(ctx, ler)
+}
+
+func (l *fakeLock) Update(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.record = &ler
+	return nil
+}
+
+func (l *fakeLock) RecordEvent(string) {}
+
+func (l *fakeLock) Identity() string {
+	return "me"
+}
+
+func (l *fakeLock) Describe() string {
+	return "fake"
+}
+
+func newPausedThings(t *testing.T, r *Reconciler, labels map[string]map[string]string) {
+	t.Helper()
+
+	for name, ls := range labels {
+		thing := newThing(t, name)
+		thing.SetLabels(ls)
+		err := r.Client.Create(context.Background(), thing)
+		require.Nil(t, err)
+		err = r.ensurePause(context.Background(), thing, nil, "test")
+		require.Nil(t, err)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	cli := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	r := newThingReconciler(cli)
+	r.LabelSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"managed": "true"}}
+	// it filters out the generic events, yet the selected resources are not swept.
+	r.Predicates = []predicate.Predicate{predicate.Funcs{GenericFunc: func(event.GenericEvent) bool { return false }}}
+	newPausedThings(t, r, map[string]map[string]string{
+		"managed":  {"managed": "true"},
+		"orphaned": {"managed": "false"},
+	})
+
+	swept, err := r.sweep(ctx)
+	require.Nil(t, err)
+	require.Equal(t, 1, swept)
+
+	info, err := r.parsePauseInfo(getThing(t, cli, "managed"))
+	require.Nil(t, err)
+	require.True(t, info.Pause)
+	info, err = r.parsePauseInfo(getThing(t, cli, "orphaned"))
+	require.Nil(t, err)
+	require.False(t, info.Pause)
+	require.Equal(t, UnpauseReasonOrphaned, info.History[0].Reason)
+
+	swept, err = r.sweep(ctx)
+	require.Nil(t, err)
+	require.Equal(t, 0, swept)
+}
+
+func TestSweeperLeaderElection(t *testing.T) {
+	tests := []struct {
+		name   string
+		lock   *fakeLock
+		leader bool
+	}{
+		{
+			name:   "leader",
+			lock:   &fakeLock{},
+			leader: true,
+		},
+		{
+			name: "held by others",
+			lock: &fakeLock{record: &resourcelock.LeaderElectionRecord{
+				HolderIdentity:       "others",
+				LeaseDurationSeconds: 3600,
+				AcquireTime:          metav1.Now(),
+				RenewTime:            metav1.Now(),
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := newTestManager(t, func(o *manager.Options) {
+				o.LeaderElection = true
+				o.LeaderElectionID = "test"
+				o.LeaderElectionNamespace = "default"
+				o.LeaderElectionResourceLockInterface = tt.lock
+				o.LeaderElectionReleaseOnCancel = true
+			})
+
+			cli := fake.NewClientBuilder().Build()
+			r := newThingReconciler(cli)
+			r.SweepInterval = 10 * time.Millisecond
+			r.ExcludeNames = []string{"thing"}
+			newPausedThings(t, r, map[string]map[string]string{"thing": nil})
+			r.addBackground(&sweeper{r: r})
+			err := r.setupBackgrounds(mgr)
+			require.Nil(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				_ = mgr.Start(ctx)
+			}()
+			defer func() {
+				cancel()
+				<-done
+			}()
+
+			swept := func() bool {
+				info, err := r.parsePauseInfo(getThing(t, cli, "thing"))
+				return err == nil && !info.Pause
+			}
+			if tt.leader {
+				require.Eventually(t, swept, 5*time.Second, 10*time.Millisecond)
+			} else {
+				require.Never(t, swept, 200*time.Millisecond, 10*time.Millisecond)
+			}
+		})
+	}
+}